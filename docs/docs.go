@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/auth/email/resend": {
             "post": {
-                "description": "Аутентифицирует пользователя и возвращает refresh token",
+                "description": "Отправляет новое письмо для подтверждения email. Ответ не зависит от того, зарегистрирован ли адрес",
                 "consumes": [
                     "application/json"
                 ],
@@ -27,24 +27,21 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Вход в систему",
+                "summary": "Повторная отправка письма подтверждения",
                 "parameters": [
                     {
-                        "description": "Данные для входа",
+                        "description": "Email пользователя",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.LoginRequest"
+                            "$ref": "#/definitions/handler.EmailRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handler.TokenResponse"
-                        }
+                    "202": {
+                        "description": "Accepted"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -52,12 +49,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -67,9 +58,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh": {
+        "/auth/email/verify": {
             "post": {
-                "description": "Обновляет пару токенов используя refresh token",
+                "description": "Подтверждает адрес электронной почты по токену из письма",
                 "consumes": [
                     "application/json"
                 ],
@@ -79,58 +70,40 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Обновление токенов",
+                "summary": "Подтверждение email",
                 "parameters": [
                     {
-                        "description": "Refresh token",
+                        "description": "Токен подтверждения",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.RefreshTokenRequest"
+                            "$ref": "#/definitions/handler.VerifyEmailRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handler.TokenResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/register": {
+        "/auth/login": {
             "post": {
-                "description": "Создает нового пользователя и возвращает refresh token",
+                "description": "Аутентифицирует пользователя и возвращает refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -140,15 +113,15 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Регистрация нового пользователя",
+                "summary": "Вход в систему",
                 "parameters": [
                     {
-                        "description": "Данные для регистрации",
+                        "description": "Данные для входа",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.RegisterRequest"
+                            "$ref": "#/definitions/handler.LoginRequest"
                         }
                     }
                 ],
@@ -159,41 +132,33 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.TokenResponse"
                         }
                     },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MFAChallengeResponse"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/projects": {
-            "get": {
-                "description": "Возвращает список всех проектов",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "projects"
-                ],
-                "summary": "Получение списка проектов",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.SwaggerProject"
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Через сколько секунд можно повторить попытку"
                             }
                         }
                     },
@@ -204,14 +169,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/logout": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Создает новый проект для текущего пользователя",
+                "description": "Отзывает refresh token и все токены, полученные из него при обновлении",
                 "consumes": [
                     "application/json"
                 ],
@@ -219,26 +181,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "auth"
                 ],
-                "summary": "Создание нового проекта",
+                "summary": "Выход из системы",
                 "parameters": [
                     {
-                        "description": "Данные проекта",
+                        "description": "Refresh token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateProjectRequest"
+                            "$ref": "#/definitions/handler.RefreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.SwaggerProject"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -261,40 +220,27 @@ const docTemplate = `{
                 }
             }
         },
-        "/projects/search": {
-            "get": {
-                "description": "Возвращает список проектов, названия которых содержат поисковый запрос",
-                "consumes": [
-                    "application/json"
+        "/auth/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Отзывает все refresh токены текущего пользователя",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
-                ],
-                "summary": "Поиск проектов по названию",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Поисковый запрос",
-                        "name": "q",
-                        "in": "query",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Выход со всех устройств",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.ProjectResponse"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -308,9 +254,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/projects/{id}": {
-            "get": {
-                "description": "Возвращает информацию о проекте по его ID",
+        "/auth/mfa/enroll": {
+            "post": {
+                "description": "Генерирует секрет TOTP для пользователя, которому второй фактор обязателен, но еще не настроен",
                 "consumes": [
                     "application/json"
                 ],
@@ -318,23 +264,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "auth"
                 ],
-                "summary": "Получение информации о проекте",
+                "summary": "Настройка обязательного второго фактора при входе",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID проекта",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "MFA токен",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MFATokenRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SwaggerProject"
+                            "$ref": "#/definitions/handler.TOTPSetupResponse"
                         }
                     },
                     "400": {
@@ -343,16 +291,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Обновляет данные существующего проекта",
+            }
+        },
+        "/auth/mfa/enroll/confirm": {
+            "post": {
+                "description": "Включает второй фактор кодом из приложения и завершает вход. Коды восстановления показываются один раз",
                 "consumes": [
                     "application/json"
                 ],
@@ -360,24 +316,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "auth"
                 ],
-                "summary": "Обновление проекта",
+                "summary": "Подтверждение обязательного второго фактора при входе",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID проекта",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные проекта",
+                        "description": "MFA токен и код",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdateProjectRequest"
+                            "$ref": "#/definitions/handler.MFAVerifyRequest"
                         }
                     }
                 ],
@@ -385,7 +334,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ProjectResponse"
+                            "$ref": "#/definitions/handler.MFAEnrollmentResponse"
                         }
                     },
                     "400": {
@@ -394,41 +343,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Удаляет проект по указанному ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "projects"
-                ],
-                "summary": "Удаление проекта",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID проекта",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -442,9 +364,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/projects/{id}/invite": {
+        "/auth/mfa/verify": {
             "post": {
-                "description": "Приглашает пользователя в проект по email",
+                "description": "Завершает вход кодом из приложения-аутентификатора или кодом восстановления. После нескольких неверных кодов MFA токен перестает действовать, а частые ошибки для пользователя ограничиваются (429 с Retry-After)",
                 "consumes": [
                     "application/json"
                 ],
@@ -452,24 +374,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "auth"
                 ],
-                "summary": "Приглашение участника в проект",
+                "summary": "Второй шаг входа",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID проекта",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные приглашения",
+                        "description": "MFA токен и код",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.InviteMemberRequest"
+                            "$ref": "#/definitions/handler.MFAVerifyRequest"
                         }
                     }
                 ],
@@ -477,7 +392,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ProjectMemberResponse"
+                            "$ref": "#/definitions/handler.TokenResponse"
                         }
                     },
                     "400": {
@@ -492,14 +407,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -507,37 +422,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/projects/{id}/members": {
+        "/auth/oauth/providers": {
             "get": {
-                "description": "Возвращает список всех участников проекта",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает список настроенных внешних провайдеров входа",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
-                ],
-                "summary": "Получение списка участников проекта",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID проекта",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Список провайдеров входа",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.ProjectMemberResponse"
-                            }
+                            "$ref": "#/definitions/handler.OAuthProvidersResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/auth/oauth/{provider}": {
+            "get": {
+                "description": "Перенаправляет пользователя на страницу авторизации провайдера (authorization code + PKCE). State входа сохраняется в HttpOnly cookie oauth_state: callback принимается только в том же браузере",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Вход через внешнего провайдера",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "github",
+                        "description": "Имя провайдера",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Адрес фронтенда, куда вернуть пользователя с токенами во фрагменте URL",
+                        "name": "redirect_uri",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -545,14 +475,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -560,46 +490,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/projects/{id}/vacancies": {
+        "/auth/oauth/{provider}/callback": {
             "get": {
-                "description": "Получает список вакансий, привязанных к проекту",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Обменивает код авторизации на токены. State из адреса должен совпасть с cookie oauth_state, установленной при начале входа. Находит пользователя по внешней учетной записи, привязывает ее к аккаунту с тем же подтвержденным email или регистрирует нового пользователя",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "vacancies"
+                    "auth"
                 ],
-                "summary": "Получить вакансии проекта",
+                "summary": "Завершение входа через внешнего провайдера",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID проекта",
-                        "name": "id",
+                        "type": "string",
+                        "example": "github",
+                        "description": "Имя провайдера",
+                        "name": "provider",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Код авторизации",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.SwaggerProjectVacancy"
-                            }
+                            "$ref": "#/definitions/handler.TokenResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MFAChallengeResponse"
                         }
                     },
+                    "302": {
+                        "description": "Found"
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -607,9 +567,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/projects/{id}/vacancy": {
+        "/auth/password/forgot": {
             "post": {
-                "description": "Создаёт новую вакансию, привязанную к проекту",
+                "description": "Отправляет на email ссылку для сброса пароля. Ответ не зависит от того, зарегистрирован ли адрес",
                 "consumes": [
                     "application/json"
                 ],
@@ -617,33 +577,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "vacancies"
+                    "auth"
                 ],
-                "summary": "Создать вакансию для проекта",
+                "summary": "Запрос сброса пароля",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID проекта",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные вакансии",
+                        "description": "Email пользователя",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateProjectVacancyRequest"
+                            "$ref": "#/definitions/handler.EmailRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.SwaggerProjectVacancy"
-                        }
+                    "202": {
+                        "description": "Accepted"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -660,9 +610,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tags": {
-            "get": {
-                "description": "Возвращает список всех тегов",
+        "/auth/password/reset": {
+            "post": {
+                "description": "Устанавливает новый пароль по токену из письма и завершает все сессии пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -670,17 +620,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "auth"
+                ],
+                "summary": "Сброс пароля",
+                "parameters": [
+                    {
+                        "description": "Токен и новый пароль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ResetPasswordRequest"
+                        }
+                    }
                 ],
-                "summary": "Получение списка тегов",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.TagResponse"
-                            }
+                            "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
                     "500": {
@@ -690,9 +651,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh": {
             "post": {
-                "description": "Создает новый тег в системе",
+                "description": "Обновляет пару токенов используя refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -700,45 +663,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "auth"
                 ],
-                "summary": "Создание нового тега",
+                "summary": "Обновление токенов",
                 "parameters": [
                     {
-                        "description": "Данные тега",
+                        "description": "Refresh token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateTagRequest"
+                            "$ref": "#/definitions/handler.RefreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.TagResponse"
+                            "$ref": "#/definitions/handler.TokenResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/tags/search": {
-            "get": {
-                "description": "Возвращает список тегов, соответствующих поисковому запросу",
+        "/auth/register": {
+            "post": {
+                "description": "Создает нового пользователя и возвращает refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -746,26 +724,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "auth"
                 ],
-                "summary": "Поиск тегов",
+                "summary": "Регистрация нового пользователя",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Поисковый запрос",
-                        "name": "q",
-                        "in": "query",
-                        "required": true
+                        "description": "Данные для регистрации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.TagResponse"
-                            }
+                            "$ref": "#/definitions/handler.TokenResponse"
                         }
                     },
                     "400": {
@@ -783,9 +760,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/tags/{id}": {
-            "put": {
-                "description": "Обновляет информацию о теге",
+        "/auth/unlock": {
+            "post": {
+                "description": "Снимает блокировку входа, установленную после неудачных попыток ввода пароля, по токену из письма",
                 "consumes": [
                     "application/json"
                 ],
@@ -793,33 +770,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "auth"
                 ],
-                "summary": "Обновление тега",
+                "summary": "Разблокировка входа",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID тега",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные тега",
+                        "description": "Токен разблокировки",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdateTagRequest"
+                            "$ref": "#/definitions/handler.UnlockAccountRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handler.TagResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -827,12 +794,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -840,31 +801,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Удаляет тег по его ID",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/invitations/{token}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Принимает приглашение по токену из ссылки в письме и добавляет текущего пользователя в участники проекта с ролью из приглашения. Принять приглашение можно только из аккаунта с подтвержденным адресом, на который оно отправлено",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "invitations"
                 ],
-                "summary": "Удаление тега",
+                "summary": "Принятие приглашения",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID тега",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Токен из ссылки-приглашения",
+                        "name": "token",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProjectMemberResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -872,51 +840,63 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
-                    }
-                }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
             }
         },
-        "/technologies": {
+        "/invitations/{token}/decline": {
             "post": {
-                "description": "Создаёт новую технологию",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Отклоняет приглашение по токену из ссылки в письме. Отклонить приглашение можно только из аккаунта с подтвержденным адресом, на который оно отправлено",
                 "tags": [
-                    "technologies"
+                    "invitations"
                 ],
-                "summary": "Создать технологию",
+                "summary": "Отказ от приглашения",
                 "parameters": [
                     {
-                        "description": "Данные технологии",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateTechnologyRequest"
-                        }
+                        "type": "string",
+                        "description": "Токен из ссылки-приглашения",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/models.Technology"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -924,8 +904,32 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -933,14 +937,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
+        "/projects": {
             "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Возвращает информацию о текущем авторизованном пользователе",
+                "description": "Возвращает список всех проектов",
                 "consumes": [
                     "application/json"
                 ],
@@ -948,31 +947,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "projects"
                 ],
-                "summary": "Получение информации о текущем пользователе",
+                "summary": "Получение списка проектов",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SwaggerUser"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SwaggerProject"
+                            }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Обновляет данные авторизованного пользователя",
+                "description": "Создает новый проект для текущего пользователя. Проект создается в статусе draft, recruiting или active (по умолчанию active); черновики не показываются в списках и поиске. Даты проверяются так же, как при смене статуса. Уровень доступа: public — проект виден всем, unlisted — открывается по ссылке, но не показывается в списках и поиске, private — виден только участникам",
                 "consumes": [
                     "application/json"
                 ],
@@ -980,25 +982,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "projects"
                 ],
-                "summary": "Обновление данных текущего пользователя",
+                "summary": "Создание нового проекта",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
+                        "description": "Данные проекта",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdateUserRequest"
+                            "$ref": "#/definitions/handler.CreateProjectRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.SwaggerUser"
+                            "$ref": "#/definitions/models.SwaggerProject"
                         }
                     },
                     "400": {
@@ -1013,6 +1015,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1022,9 +1030,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/projects/search": {
             "get": {
-                "description": "Возвращает информацию о пользователе по его ID",
+                "description": "Ищет проекты по названию, заголовку, подзаголовку, тегам и описанию с учетом словоформ русского и английского языков. Результаты упорядочены по релевантности, совпадения во фрагменте выделены тегом \u003cmark\u003e. Проекты по ссылке и закрытые проекты находятся только для их участников",
                 "consumes": [
                     "application/json"
                 ],
@@ -1032,13 +1040,86 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "projects"
                 ],
-                "summary": "Получение информации о пользователе",
+                "summary": "Полнотекстовый поиск проектов",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос: слова, фразы в двойных кавычках, -слово для исключения, or между вариантами",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "ID пользователя",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы, не больше 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SwaggerListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.ProjectSearchResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}": {
+            "get": {
+                "description": "Возвращает информацию о проекте по его ID. Закрытый проект доступен только участникам, для остальных он не существует",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получение информации о проекте",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1048,7 +1129,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SwaggerUser"
+                            "$ref": "#/definitions/handler.ProjectResponse"
                         }
                     },
                     "400": {
@@ -1062,18 +1143,22 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/users/{user_id}/projects": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Возвращает список проектов текущего пользователя",
+                "description": "Обновляет данные существующего проекта",
                 "consumes": [
                     "application/json"
                 ],
@@ -1081,25 +1166,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "projects"
+                ],
+                "summary": "Обновление проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные проекта",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateProjectRequest"
+                        }
+                    }
                 ],
-                "summary": "Получение проектов пользователя",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.ProjectResponse"
-                            }
+                            "$ref": "#/definitions/handler.ProjectResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1107,170 +1213,6402 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handler.CreateProjectRequest": {
-            "type": "object",
-            "required": [
-                "name"
-            ],
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "photo": {
-                    "type": "string"
-                },
-                "subtitle": {
-                    "type": "string"
-                },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
-                },
-                "title": {
-                    "type": "string"
-                }
-            }
-        },
-        "handler.CreateProjectVacancyRequest": {
+                ],
+                "description": "Удаляет проект по указанному ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Удаление проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/applications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает отклики на вакансии проекта в порядке подачи",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Отклики на вакансии проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID вакансии",
+                        "name": "vacancy_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Этап отбора: new, screening, interview, offer, accepted, rejected или withdrawn",
+                        "name": "stage",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ApplicationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/applications/{application_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает отклик с заметками команды проекта и историей смены этапов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Отклик на вакансию проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID отклика",
+                        "name": "application_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApplicationDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/applications/{application_id}/attachment": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отдает файл, приложенный к отклику, команде проекта с правом управлять вакансиями",
+                "produces": [
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Скачивание вложения отклика на вакансию проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID отклика",
+                        "name": "application_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/applications/{application_id}/notes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет к отклику заметку команды проекта (до 2000 символов). Кандидат заметок не видит",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Заметка к отклику",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID отклика",
+                        "name": "application_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Заметка",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddApplicationNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApplicationNoteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/applications/{application_id}/stage": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переводит отклик на следующий этап отбора: new → screening → interview → offer → accepted. Этапы можно пропускать, отклонить отклик можно на любом этапе; принятый и отклоненный отклики больше не меняются. Принятый кандидат становится участником проекта с ролью из вакансии; с close_vacancy вакансия помечается заполненной",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Смена этапа отклика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID отклика",
+                        "name": "application_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый этап",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MoveApplicationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.MoveApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает все приглашения в проект, начиная с последних",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Приглашения в проект",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ProjectInvitationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/invitations/{invitation_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает ожидающее приглашение в проект; ссылка из письма перестает действовать",
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Отзыв приглашения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID приглашения",
+                        "name": "invitation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/invite": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отправляет на email ссылку-приглашение в проект. Адрес может еще не быть зарегистрирован: после регистрации с ним приглашение появится в списке приглашений пользователя. Участником приглашенный становится, только приняв приглашение. Повторное приглашение на тот же адрес меняет роль, продлевает срок действия и отправляет новую ссылку, прежняя ссылка перестает действовать",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Приглашение участника в проект",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные приглашения",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.InviteMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProjectInvitationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/join-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает заявки на вступление в проект в порядке подачи. По умолчанию — только ожидающие рассмотрения",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Заявки на вступление в проект",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Состояние заявок: pending (по умолчанию), approved, rejected, withdrawn или all",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.JoinRequestResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подает заявку текущего пользователя на вступление в проект с сопроводительным сообщением (до 2000 символов). Заявку рассматривают владелец или мейнтейнер проекта. Заявки не принимаются, если проект их отключил или завершен",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Заявка на вступление в проект",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Заявка",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SubmitJoinRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JoinRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/join-requests/{request_id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Одобряет ожидающую заявку и добавляет ее автора в участники проекта с указанной ролью, по умолчанию member",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Одобрение заявки на вступление",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID заявки",
+                        "name": "request_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Роль нового участника",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApproveJoinRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProjectMemberResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/join-requests/{request_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отклоняет ожидающую заявку. Причина отказа (до 1000 символов) видна автору заявки",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Отклонение заявки на вступление",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID заявки",
+                        "name": "request_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина отказа",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RejectJoinRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JoinRequestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает список всех участников проекта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Получение списка участников проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ProjectMemberResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/photos": {
+            "get": {
+                "description": "Возвращает фотографии проекта в заданном порядке со ссылками для скачивания",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Фотографии проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.PhotoResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет фотографию в конец списка фотографий проекта. Тип файла определяется по содержимому: допускаются JPEG, PNG и WebP. Размер файла и число фотографий в проекте ограничены настройками сервера. Метаданные EXIF удаляются, изображение поворачивается по ориентации из EXIF, для него создаются уменьшенные варианты thumb, medium и large в WebP и JPEG (PNG для изображений с прозрачностью) и blurhash превью",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Загрузка фотографии проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл фотографии",
+                        "name": "photo",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PhotoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/photos/order": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Задает порядок фотографий проекта. Список должен содержать каждую фотографию проекта ровно один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Порядок фотографий проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ID фотографий в новом порядке",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReorderPhotosRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.PhotoResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/photos/{photo_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет фотографию проекта и файлы всех ее вариантов из хранилища",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Удаление фотографии проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID фотографии",
+                        "name": "photo_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/transitions": {
+            "get": {
+                "description": "Возвращает переходы между статусами проекта, начиная с последних",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "История статусов проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ProjectTransitionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переводит проект в новый статус и записывает переход в историю. Допустимые переходы: draft → recruiting, active, archived; recruiting → draft, active, paused, archived; active → recruiting, paused, completed, archived; paused → recruiting, active, completed, archived; completed → active, archived. Архивный проект не меняет статус. Активный и приостановленный проект уже начался и не закончился, завершенный — начался и закончился; недостающая дата начала при переходе в active и дата окончания при переходе в completed равны текущему времени. Доступно только автору проекта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Смена статуса проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый статус",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TransitionProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProjectResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/vacancies": {
+            "get": {
+                "description": "Получает страницу вакансий, привязанных к проекту. По умолчанию показываются только открытые вакансии, срок которых не истек. Вакансии закрытого проекта видят только его участники",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Получить вакансии проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы, не больше 100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Поле сортировки: created_at, name; префикс - задает обратный порядок",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "closed",
+                            "filled",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "open",
+                        "description": "Состояние вакансии; all — вакансии во всех состояниях",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название технологии",
+                        "name": "technology",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "junior",
+                            "middle",
+                            "senior",
+                            "lead"
+                        ],
+                        "type": "string",
+                        "description": "Уровень квалификации",
+                        "name": "seniority",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "volunteer",
+                            "paid",
+                            "equity",
+                            "internship"
+                        ],
+                        "type": "string",
+                        "description": "Вид занятости",
+                        "name": "employment_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "remote",
+                            "hybrid",
+                            "onsite"
+                        ],
+                        "type": "string",
+                        "description": "Формат работы",
+                        "name": "remote_policy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Часть названия места работы",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Занятость не больше стольких часов в неделю; вакансии без указанной занятости не подходят",
+                        "name": "max_hours_per_week",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Вознаграждение может достичь этой суммы; требует currency",
+                        "name": "min_compensation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "RUB",
+                        "description": "Код валюты вознаграждения ISO 4217",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Не меньше стольких мест",
+                        "name": "min_openings",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана не раньше (YYYY-MM-DD или RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана не позже (YYYY-MM-DD или RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SwaggerListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.VacancyResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/vacancy": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт новую вакансию, привязанную к проекту",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Создать вакансию для проекта",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные вакансии",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateProjectVacancyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerProjectVacancy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Ищет одновременно проекты, вакансии, пользователей и теги и возвращает их одним списком по убыванию релевантности. Фасеты показывают, сколько найдено записей каждого типа и с каждым значением тега, технологии, страны и статуса проекта. Фильтр по атрибуту оставляет только типы, у которых этот атрибут есть: tag — проекты и пользователи, technology — вакансии, country — проекты и пользователи, status — проекты и вакансии. Проекты по ссылке и закрытые проекты и их вакансии находятся только для участников этих проектов. Доступны первые 1000 результатов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Общий поиск",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "project",
+                            "vacancy",
+                            "user",
+                            "tag"
+                        ],
+                        "type": "string",
+                        "description": "Тип записей",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тег проекта или пользователя",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Технология вакансии",
+                        "name": "technology",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Страна пользователя или автора проекта",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "active",
+                            "archived"
+                        ],
+                        "type": "string",
+                        "description": "Статус проекта",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы, не больше 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/handler.SearchResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.SearchHitResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Возвращает страницу тегов. Популярность тега — число проектов с ним",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Получение списка тегов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы, не больше 100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Поле сортировки: created_at, name, popularity; префикс - задает обратный порядок",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Часть названия тега",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SwaggerListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.TagResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает новый тег в системе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Создание нового тега",
+                "parameters": [
+                    {
+                        "description": "Данные тега",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/search": {
+            "get": {
+                "description": "Возвращает список тегов, соответствующих поисковому запросу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Поиск тегов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.TagResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Обновляет информацию о теге",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Обновление тега",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные тега",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет тег по его ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Удаление тега",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID тега",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/technologies": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создаёт новую технологию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "technologies"
+                ],
+                "summary": "Создать технологию",
+                "parameters": [
+                    {
+                        "description": "Данные технологии",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateTechnologyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Technology"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Возвращает страницу пользователей с фильтрами и сортировкой. Популярность пользователя — число проектов, в которых он участвует",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение списка пользователей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы, не больше 100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Поле сортировки: created_at, name, popularity; префикс - задает обратный порядок",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Страна",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Город",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название тега",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Глобальная роль",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирован не раньше (YYYY-MM-DD или RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Зарегистрирован не позже (YYYY-MM-DD или RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SwaggerListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.UserSummaryResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает информацию о текущем авторизованном пользователе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение информации о текущем пользователе",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerUser"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Назначает удаление аккаунта по истечении льготного периода. После него личные данные обезличиваются, проекты передаются участникам или архивируются. До этого удаление можно отменить",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Удаление аккаунта",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JobResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Обновляет данные авторизованного пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Обновление данных текущего пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/applications": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает отклики текущего пользователя на вакансии, начиная с последних. Заметки команды проекта кандидату не показываются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Мои отклики",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ApplicationResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/applications/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает рассматриваемый отклик текущего пользователя и удаляет его вложение. Принятый или отклоненный отклик отозвать нельзя",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Отзыв отклика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID отклика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/applications/{id}/attachment": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отдает файл, приложенный к отклику текущего пользователя. Вложения не раздаются по общим ссылкам; после отзыва отклика вложение удаляется",
+                "produces": [
+                    "application/pdf",
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Скачивание вложения своего отклика",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID отклика",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/avatar": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет аватар текущего пользователя. Тип файла определяется по содержимому: допускаются JPEG, PNG и WebP. Изображение обрезается по центру до квадрата, метаданные EXIF удаляются, создаются варианты small, medium и large в WebP и JPEG (PNG для изображений с прозрачностью) и blurhash превью. Исходный файл не сохраняется",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Загрузка аватара",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл изображения",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AvatarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет аватар текущего пользователя и файлы всех его вариантов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удаление аватара",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/deletion": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отменяет назначенное удаление аккаунта, пока не истек льготный период",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Отмена удаления аккаунта",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/export": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ставит в очередь подготовку zip архива с JSON файлами профиля, тегов, проектов, участия в проектах и вакансий. Статус подготовки отслеживается по ID задачи",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Выгрузка данных аккаунта",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JobResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/invitations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает действующие приглашения в проекты, отправленные на адрес текущего пользователя, в том числе до его регистрации. Требуется подтвержденный email. Принять приглашение можно по ссылке из письма",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Мои приглашения",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ProjectInvitationResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает состояние выгрузки данных или удаления аккаунта текущего пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Статус фоновой задачи",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.JobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/jobs/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отдает zip архив завершенной выгрузки данных. Архив хранится ограниченное время",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "account"
+                ],
+                "summary": "Скачивание выгрузки данных",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи выгрузки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/join-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает заявки текущего пользователя на вступление в проекты во всех состояниях, начиная с последних",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Мои заявки на вступление",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.JoinRequestResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/join-requests/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отзывает ожидающую рассмотрения заявку текущего пользователя",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Отзыв заявки на вступление",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отключает второй фактор после проверки кода из приложения или кода восстановления",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Отключение двухфакторной аутентификации",
+                "parameters": [
+                    {
+                        "description": "Код из приложения или код восстановления",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет коды восстановления новыми. Старые коды перестают действовать",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Новые коды восстановления",
+                "parameters": [
+                    {
+                        "description": "Код из приложения или код восстановления",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/totp": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Генерирует секрет TOTP. Второй фактор включается после подтверждения кодом из приложения",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Начало настройки двухфакторной аутентификации",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TOTPSetupResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/totp/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Подтверждает настройку кодом из приложения и возвращает коды восстановления. Коды показываются один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "mfa"
+                ],
+                "summary": "Включение двухфакторной аутентификации",
+                "parameters": [
+                    {
+                        "description": "Код из приложения",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/portfolio/files": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет файл в портфолио текущего пользователя, например резюме. Тип файла определяется по содержимому: допускаются документы PDF. Размер файла и число файлов в портфолио ограничены настройками сервера. Поле title должно идти в форме перед файлом",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Загрузка файла в портфолио",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Название файла",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PortfolioFileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/portfolio/files/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет файл из портфолио текущего пользователя и из хранилища",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удаление файла из портфолио",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID файла",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/portfolio/links": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет ссылку в портфолио текущего пользователя. Допускаются адреса http и https; вид ссылки (github, behance или website) определяется по адресу",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Добавление ссылки в портфолио",
+                "parameters": [
+                    {
+                        "description": "Ссылка",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PortfolioLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PortfolioLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/portfolio/links/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет адрес и название ссылки в портфолио текущего пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Изменение ссылки в портфолио",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ссылки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ссылка",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PortfolioLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PortfolioLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет ссылку из портфолио текущего пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Удаление ссылки из портфолио",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID ссылки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает устройства, на которых выполнен вход в аккаунт текущего пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Список активных сессий",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.SessionResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выполняет выход на выбранном устройстве: отзывает refresh токены сессии и перестает принимать ее access токены",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Завершение сессии",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сессии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает персональные токены текущего пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Список персональных токенов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.PersonalAccessTokenResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Создает токен для скриптов и интеграций. Значение токена возвращается только один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Создание персонального токена",
+                "parameters": [
+                    {
+                        "description": "Данные токена",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreatedTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет персональный токен текущего пользователя",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Отзыв персонального токена",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID токена",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Возвращает публичный профиль пользователя по его ID: аватар и портфолио со ссылками и файлами. Контактные данные в профиль не входят",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение профиля пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.UserProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/impersonate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выдает администратору access токен ограниченного срока действия для работы от имени пользователя. Токен передается в заголовке Authorization. Пока он используется, управление учетными данными пользователя недоступно, а каждый запрос записывается в журнал аудита",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Вход от имени пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ImpersonationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/projects": {
+            "get": {
+                "description": "Возвращает список проектов пользователя. Черновики видны только самому пользователю, проекты по ссылке и закрытые проекты — только участникам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получение проектов пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.ProjectResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Назначает пользователю глобальную роль и завершает все его сессии, чтобы токены со старой ролью перестали действовать. Доступно только администраторам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Изменение роли пользователя",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая роль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vacancies": {
+            "get": {
+                "description": "Получает страницу вакансий всех проектов, кроме черновиков и проектов по ссылке. По умолчанию показываются только открытые вакансии, срок которых не истек. Вакансии закрытых проектов видят только их участники",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Список вакансий",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы, не больше 100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Поле сортировки: created_at, name; префикс - задает обратный порядок",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID проекта",
+                        "name": "project",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "closed",
+                            "filled",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "open",
+                        "description": "Состояние вакансии; all — вакансии во всех состояниях",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название технологии",
+                        "name": "technology",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "junior",
+                            "middle",
+                            "senior",
+                            "lead"
+                        ],
+                        "type": "string",
+                        "description": "Уровень квалификации",
+                        "name": "seniority",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "volunteer",
+                            "paid",
+                            "equity",
+                            "internship"
+                        ],
+                        "type": "string",
+                        "description": "Вид занятости",
+                        "name": "employment_type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "remote",
+                            "hybrid",
+                            "onsite"
+                        ],
+                        "type": "string",
+                        "description": "Формат работы",
+                        "name": "remote_policy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Часть названия места работы",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Занятость не больше стольких часов в неделю; вакансии без указанной занятости не подходят",
+                        "name": "max_hours_per_week",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Вознаграждение может достичь этой суммы; требует currency",
+                        "name": "min_compensation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "RUB",
+                        "description": "Код валюты вознаграждения ISO 4217",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Не меньше стольких мест",
+                        "name": "min_openings",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана не раньше (YYYY-MM-DD или RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Создана не позже (YYYY-MM-DD или RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SwaggerListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.VacancyResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vacancies/search": {
+            "get": {
+                "description": "Ищет вакансии по названию, технологиям и описанию с учетом словоформ русского и английского языков. Результаты упорядочены по релевантности, совпадения во фрагменте выделены тегом \u003cmark\u003e",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Полнотекстовый поиск вакансий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос: слова, фразы в двойных кавычках, -слово для исключения, or между вариантами",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы, не больше 100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SwaggerListResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/handler.VacancySearchResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vacancies/{id}": {
+            "get": {
+                "description": "Получает вакансию в любом состоянии. Вакансию закрытого проекта видят только его участники",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Получить вакансию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вакансии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.VacancyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет название, описание, технологии, роль, срок и условия работы вакансии; если срок не передан, вакансия становится бессрочной, не переданные условия работы снимаются. Состояние вакансии не меняется. Доступно автору и сопровождающим проекта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Изменить вакансию целиком",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вакансии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные вакансии",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReplaceVacancyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.VacancyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Удаляет вакансию, на которую еще никто не откликнулся. Вакансию с откликами можно только закрыть. Доступно автору и сопровождающим проекта",
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Удалить вакансию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вакансии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Меняет переданные поля вакансии. Через status вакансию можно закрыть (closed), отметить заполненной (filled) или открыть снова (open); вакансию с истекшим сроком можно открыть, только передав новый срок. Открытая вакансия закрывается автоматически после expires_at. Доступно автору и сопровождающим проекта",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Изменить вакансию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вакансии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменения вакансии",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateVacancyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.VacancyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ForbiddenResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vacancies/{id}/applications": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Откликается на открытую вакансию от имени текущего пользователя. Сопроводительное письмо — до 5000 символов. Отклик без вложения можно отправить в JSON; с вложением — в multipart/form-data, где поле cover_letter должно идти перед файлом attachment. Тип вложения определяется по содержимому: допускаются документы PDF, размер ограничен настройками сервера. На вакансию можно иметь только один рассматриваемый отклик",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vacancies"
+                ],
+                "summary": "Отклик на вакансию",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID вакансии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Отклик без вложения",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApplyRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Сопроводительное письмо",
+                        "name": "cover_letter",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Вложение, например резюме",
+                        "name": "attachment",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.AddApplicationNoteRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Сильное портфолио, стоит обсудить опыт с PostgreSQL"
+                }
+            }
+        },
+        "handler.ApplicationAttachmentResponse": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "resume.pdf"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 183204
+                },
+                "url": {
+                    "description": "URL — адрес API, по которому вложение скачивается с авторизацией: для кандидата\n/users/me/applications/{id}/attachment, для команды проекта\n/projects/{id}/applications/{application_id}/attachment",
+                    "type": "string",
+                    "example": "/api/v1/users/me/applications/1/attachment"
+                }
+            }
+        },
+        "handler.ApplicationDetailResponse": {
+            "type": "object",
+            "properties": {
+                "attachment": {
+                    "description": "Attachment пустой, если к отклику ничего не приложено",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.ApplicationAttachmentResponse"
+                        }
+                    ]
+                },
+                "cover_letter": {
+                    "type": "string",
+                    "example": "Три года пишу сервисы на Go, хочу развиваться в продуктовой команде"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ApplicationStageChangeResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "notes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ApplicationNoteResponse"
+                    }
+                },
+                "project_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "project_name": {
+                    "type": "string",
+                    "example": "Shance"
+                },
+                "stage": {
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "screening",
+                        "interview",
+                        "offer",
+                        "accepted",
+                        "rejected",
+                        "withdrawn"
+                    ],
+                    "example": "new"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/handler.UserResponse"
+                },
+                "vacancy_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "vacancy_title": {
+                    "type": "string",
+                    "example": "Go-разработчик"
+                }
+            }
+        },
+        "handler.ApplicationNoteResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/handler.UserResponse"
+                },
+                "body": {
+                    "type": "string",
+                    "example": "Сильное портфолио, стоит обсудить опыт с PostgreSQL"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.ApplicationResponse": {
+            "type": "object",
+            "properties": {
+                "attachment": {
+                    "description": "Attachment пустой, если к отклику ничего не приложено",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.ApplicationAttachmentResponse"
+                        }
+                    ]
+                },
+                "cover_letter": {
+                    "type": "string",
+                    "example": "Три года пишу сервисы на Go, хочу развиваться в продуктовой команде"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "project_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "project_name": {
+                    "type": "string",
+                    "example": "Shance"
+                },
+                "stage": {
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "screening",
+                        "interview",
+                        "offer",
+                        "accepted",
+                        "rejected",
+                        "withdrawn"
+                    ],
+                    "example": "new"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/handler.UserResponse"
+                },
+                "vacancy_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "vacancy_title": {
+                    "type": "string",
+                    "example": "Go-разработчик"
+                }
+            }
+        },
+        "handler.ApplicationStageChangeResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Созвон в четверг в 18:00"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_stage": {
+                    "type": "string",
+                    "example": "new"
+                },
+                "to_stage": {
+                    "type": "string",
+                    "example": "interview"
+                },
+                "user_id": {
+                    "description": "UserID пустой, если этап сменила система",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.ApplyRequest": {
+            "type": "object",
+            "properties": {
+                "cover_letter": {
+                    "type": "string",
+                    "example": "Три года пишу сервисы на Go, хочу развиваться в продуктовой команде"
+                }
+            }
+        },
+        "handler.ApproveJoinRequestRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "description": "Role — роль нового участника, по умолчанию member",
+                    "type": "string",
+                    "enum": [
+                        "maintainer",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
+                }
+            }
+        },
+        "handler.AvatarResponse": {
+            "type": "object",
+            "properties": {
+                "blurhash": {
+                    "description": "Blurhash — компактное размытое превью для показа до загрузки изображения",
+                    "type": "string",
+                    "example": "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PhotoVariantResponse"
+                    }
+                }
+            }
+        },
+        "handler.CreateProjectRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "accepts_join_requests": {
+                    "description": "AcceptsJoinRequests — принимает ли проект заявки на вступление, по умолчанию true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-09-30T00:00:00Z"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photo": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-04-01T00:00:00Z"
+                },
+                "status": {
+                    "description": "Status — начальный статус проекта, по умолчанию active",
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "recruiting",
+                        "active"
+                    ],
+                    "example": "active"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Visibility — уровень доступа к проекту, по умолчанию public",
+                    "type": "string",
+                    "enum": [
+                        "public",
+                        "unlisted",
+                        "private"
+                    ],
+                    "example": "public"
+                }
+            }
+        },
+        "handler.CreateProjectVacancyRequest": {
+            "type": "object",
+            "required": [
+                "description",
+                "technologies",
+                "title"
+            ],
+            "properties": {
+                "compensation_max": {
+                    "type": "integer",
+                    "example": 150000
+                },
+                "compensation_min": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "currency": {
+                    "description": "обязательна, если указано вознаграждение",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "employment_type": {
+                    "type": "string",
+                    "enum": [
+                        "volunteer",
+                        "paid",
+                        "equity",
+                        "internship"
+                    ],
+                    "example": "paid"
+                },
+                "expires_at": {
+                    "description": "ExpiresAt — когда вакансия закроется автоматически; без значения вакансия бессрочная",
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "hours_per_week": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "location": {
+                    "description": "обязательно для hybrid и onsite",
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "openings": {
+                    "description": "по умолчанию 1",
+                    "type": "integer",
+                    "example": 2
+                },
+                "remote_policy": {
+                    "type": "string",
+                    "enum": [
+                        "remote",
+                        "hybrid",
+                        "onsite"
+                    ],
+                    "example": "hybrid"
+                },
+                "role": {
+                    "description": "Role — роль в проекте, которую получает принятый кандидат, по умолчанию member",
+                    "type": "string",
+                    "enum": [
+                        "maintainer",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
+                },
+                "seniority": {
+                    "type": "string",
+                    "enum": [
+                        "junior",
+                        "middle",
+                        "senior",
+                        "lead"
+                    ],
+                    "example": "middle"
+                },
+                "technologies": {
+                    "description": "id технологий",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Тег"
+                }
+            }
+        },
+        "handler.CreateTechnologyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateTokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-12-31T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "CI"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "projects:write",
+                        "vacancies:read"
+                    ]
+                }
+            }
+        },
+        "handler.CreatedTokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "CI"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "shp_a1b2c3"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "projects:write",
+                        "vacancies:read"
+                    ]
+                },
+                "token": {
+                    "type": "string",
+                    "example": "shp_a1b2c3..."
+                }
+            }
+        },
+        "handler.EmailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ForbiddenResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "forbidden"
+                },
+                "permission": {
+                    "type": "string",
+                    "example": "project:update"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "project_role_not_allowed"
+                }
+            }
+        },
+        "handler.ImpersonationResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handler.InviteMemberRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "maintainer",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
+                }
+            }
+        },
+        "handler.JobResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "description": "DownloadURL — ссылка на архив для завершенной выгрузки данных",
+                    "type": "string",
+                    "example": "/api/v1/users/me/jobs/1/download"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "running",
+                        "completed",
+                        "failed",
+                        "cancelled"
+                    ],
+                    "example": "pending"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "data_export",
+                        "account_deletion"
+                    ],
+                    "example": "data_export"
+                }
+            }
+        },
+        "handler.JoinRequestResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Пишу на Go третий год, хочу помочь с бэкендом"
+                },
+                "project_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "project_name": {
+                    "type": "string",
+                    "example": "Shance"
+                },
+                "reason": {
+                    "description": "Reason — причина отказа",
+                    "type": "string",
+                    "example": "Сейчас ищем только дизайнеров"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewer_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected",
+                        "withdrawn"
+                    ],
+                    "example": "pending"
+                },
+                "user": {
+                    "$ref": "#/definitions/handler.UserResponse"
+                }
+            }
+        },
+        "handler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "handler.MFAChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "enrollment_required": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "mfa_required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.MFACodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "handler.MFAEnrollmentResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "abcde-fghij",
+                        "klmno-pqrst"
+                    ]
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.MFATokenRequest": {
+            "type": "object",
+            "required": [
+                "mfa_token"
+            ],
+            "properties": {
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.MFAVerifyRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.MoveApplicationRequest": {
+            "type": "object",
+            "required": [
+                "stage"
+            ],
+            "properties": {
+                "close_vacancy": {
+                    "description": "CloseVacancy при принятии кандидата помечает вакансию заполненной",
+                    "type": "boolean",
+                    "example": true
+                },
+                "comment": {
+                    "type": "string",
+                    "example": "Созвон в четверг в 18:00"
+                },
+                "stage": {
+                    "type": "string",
+                    "enum": [
+                        "screening",
+                        "interview",
+                        "offer",
+                        "accepted",
+                        "rejected"
+                    ],
+                    "example": "interview"
+                }
+            }
+        },
+        "handler.MoveApplicationResponse": {
+            "type": "object",
+            "properties": {
+                "attachment": {
+                    "description": "Attachment пустой, если к отклику ничего не приложено",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.ApplicationAttachmentResponse"
+                        }
+                    ]
+                },
+                "cover_letter": {
+                    "type": "string",
+                    "example": "Три года пишу сервисы на Go, хочу развиваться в продуктовой команде"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ApplicationStageChangeResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "member": {
+                    "$ref": "#/definitions/handler.ProjectMemberResponse"
+                },
+                "notes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ApplicationNoteResponse"
+                    }
+                },
+                "project_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "project_name": {
+                    "type": "string",
+                    "example": "Shance"
+                },
+                "stage": {
+                    "type": "string",
+                    "enum": [
+                        "new",
+                        "screening",
+                        "interview",
+                        "offer",
+                        "accepted",
+                        "rejected",
+                        "withdrawn"
+                    ],
+                    "example": "new"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/handler.UserResponse"
+                },
+                "vacancy_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "vacancy_title": {
+                    "type": "string",
+                    "example": "Go-разработчик"
+                }
+            }
+        },
+        "handler.OAuthProvidersResponse": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "github",
+                        "google"
+                    ]
+                }
+            }
+        },
+        "handler.PersonalAccessTokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "CI"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "shp_a1b2c3"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "projects:write",
+                        "vacancies:read"
+                    ]
+                }
+            }
+        },
+        "handler.PhotoResponse": {
+            "type": "object",
+            "properties": {
+                "blurhash": {
+                    "description": "Blurhash — компактное размытое превью для показа до загрузки изображения",
+                    "type": "string",
+                    "example": "LEHV6nWB2yk8pyo0adR*.7kCMdnj"
+                },
+                "content_type": {
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 1080
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 482113
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:8000/uploads/projects/1/8hPVJ7y2zK1mQ3xW9aLc0g.jpg"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PhotoVariantResponse"
+                    }
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 1920
+                }
+            }
+        },
+        "handler.PhotoVariantResponse": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "webp",
+                        "jpeg",
+                        "png"
+                    ],
+                    "example": "webp"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "name": {
+                    "type": "string",
+                    "enum": [
+                        "thumb",
+                        "medium",
+                        "large"
+                    ],
+                    "example": "thumb"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:8000/uploads/projects/1/8hPVJ7y2zK1mQ3xW9aLc0g_thumb.webp"
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 320
+                }
+            }
+        },
+        "handler.PortfolioFileResponse": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "cv.pdf"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 182113
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Резюме"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:8000/uploads/users/1/portfolio/8hPVJ7y2zK1mQ3xW9aLc0g.pdf"
+                }
+            }
+        },
+        "handler.PortfolioLinkRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "title": {
+                    "type": "string",
+                    "example": "Мои проекты на GitHub"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://github.com/ivanov"
+                }
+            }
+        },
+        "handler.PortfolioLinkResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "github",
+                        "behance",
+                        "website"
+                    ],
+                    "example": "github"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Мои проекты на GitHub"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://github.com/ivanov"
+                }
+            }
+        },
+        "handler.PortfolioResponse": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PortfolioFileResponse"
+                    }
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PortfolioLinkResponse"
+                    }
+                }
+            }
+        },
+        "handler.ProjectInvitationResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "invitee_id": {
+                    "description": "InviteeID заполнен, если у адреса есть аккаунт",
+                    "type": "integer",
+                    "example": 2
+                },
+                "inviter_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "project_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "project_name": {
+                    "type": "string",
+                    "example": "Shance"
+                },
+                "responded_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "maintainer",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "accepted",
+                        "declined",
+                        "revoked",
+                        "expired"
+                    ],
+                    "example": "pending"
+                }
+            }
+        },
+        "handler.ProjectMemberResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "Иван"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Иванов"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "member"
+                }
+            }
+        },
+        "handler.ProjectResponse": {
+            "type": "object",
+            "properties": {
+                "accepts_join_requests": {
+                    "description": "AcceptsJoinRequests — можно ли подать заявку на вступление в проект",
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-20T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Описание проекта"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-09-30T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Новый проект"
+                },
+                "photo": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PhotoResponse"
+                    }
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-04-01T00:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "recruiting",
+                        "active",
+                        "paused",
+                        "completed",
+                        "archived"
+                    ],
+                    "example": "active"
+                },
+                "subtitle": {
+                    "type": "string",
+                    "example": "Подзаголовок проекта"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "tag1",
+                        "tag2"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Заголовок проекта"
+                },
+                "user": {
+                    "$ref": "#/definitions/handler.UserResponse"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "visibility": {
+                    "type": "string",
+                    "enum": [
+                        "public",
+                        "unlisted",
+                        "private"
+                    ],
+                    "example": "public"
+                }
+            }
+        },
+        "handler.ProjectSearchResponse": {
+            "type": "object",
+            "properties": {
+                "accepts_join_requests": {
+                    "description": "AcceptsJoinRequests — можно ли подать заявку на вступление в проект",
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-03-20T12:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Описание проекта"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-09-30T00:00:00Z"
+                },
+                "headline": {
+                    "type": "string",
+                    "example": "Платформа для поиска \u003cmark\u003eкоманды\u003c/mark\u003e на pet-проекты"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Новый проект"
+                },
+                "photo": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PhotoResponse"
+                    }
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.6079271
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-04-01T00:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "recruiting",
+                        "active",
+                        "paused",
+                        "completed",
+                        "archived"
+                    ],
+                    "example": "active"
+                },
+                "subtitle": {
+                    "type": "string",
+                    "example": "Подзаголовок проекта"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "tag1",
+                        "tag2"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Заголовок проекта"
+                },
+                "user": {
+                    "$ref": "#/definitions/handler.UserResponse"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "visibility": {
+                    "type": "string",
+                    "enum": [
+                        "public",
+                        "unlisted",
+                        "private"
+                    ],
+                    "example": "public"
+                }
+            }
+        },
+        "handler.ProjectTransitionResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Ищем дизайнера и двух разработчиков"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "from_status": {
+                    "type": "string",
+                    "example": "draft"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "to_status": {
+                    "type": "string",
+                    "example": "recruiting"
+                },
+                "user_id": {
+                    "description": "UserID пустой, если статус сменила система",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.RecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "abcde-fghij",
+                        "klmno-pqrst"
+                    ]
+                }
+            }
+        },
+        "handler.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name",
+                "password"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Moscow"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "Russia"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "password123"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+79001234567"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "tag1",
+                        "tag2"
+                    ]
+                }
+            }
+        },
+        "handler.RejectJoinRequestRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Сейчас ищем только дизайнеров"
+                }
+            }
+        },
+        "handler.ReorderPhotosRequest": {
+            "type": "object",
+            "required": [
+                "photo_ids"
+            ],
+            "properties": {
+                "photo_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "handler.ReplaceVacancyRequest": {
             "type": "object",
             "required": [
                 "description",
+                "role",
                 "technologies",
                 "title"
             ],
             "properties": {
+                "compensation_max": {
+                    "type": "integer",
+                    "example": 150000
+                },
+                "compensation_min": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "currency": {
+                    "description": "обязательна, если указано вознаграждение",
+                    "type": "string",
+                    "example": "RUB"
+                },
                 "description": {
                     "type": "string"
                 },
+                "employment_type": {
+                    "type": "string",
+                    "enum": [
+                        "volunteer",
+                        "paid",
+                        "equity",
+                        "internship"
+                    ],
+                    "example": "paid"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "hours_per_week": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "location": {
+                    "description": "обязательно для hybrid и onsite",
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "openings": {
+                    "description": "по умолчанию 1",
+                    "type": "integer",
+                    "example": 2
+                },
+                "remote_policy": {
+                    "type": "string",
+                    "enum": [
+                        "remote",
+                        "hybrid",
+                        "onsite"
+                    ],
+                    "example": "hybrid"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "maintainer",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
+                },
+                "seniority": {
+                    "type": "string",
+                    "enum": [
+                        "junior",
+                        "middle",
+                        "senior",
+                        "lead"
+                    ],
+                    "example": "middle"
+                },
+                "technologies": {
+                    "description": "id технологий",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6,
+                    "example": "newpassword123"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.SearchFacetsResponse": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.FacetCount"
+                    }
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.FacetCount"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.FacetCount"
+                    }
+                },
                 "technologies": {
-                    "description": "id технологий",
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/repository.FacetCount"
                     }
                 },
-                "title": {
-                    "type": "string"
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.FacetCount"
+                    }
                 }
             }
         },
-        "handler.CreateTagRequest": {
+        "handler.SearchHitResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "headline": {
                     "type": "string",
-                    "example": "Тег"
+                    "example": "Сервис для поиска \u003cmark\u003eкоманды\u003c/mark\u003e"
+                },
+                "project": {
+                    "$ref": "#/definitions/handler.ProjectResponse"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.6079271
+                },
+                "tag": {
+                    "$ref": "#/definitions/handler.TagResponse"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "project",
+                        "vacancy",
+                        "user",
+                        "tag"
+                    ],
+                    "example": "project"
+                },
+                "user": {
+                    "$ref": "#/definitions/handler.UserSummaryResponse"
+                },
+                "vacancy": {
+                    "$ref": "#/definitions/handler.VacancyResponse"
                 }
             }
         },
-        "handler.CreateTechnologyRequest": {
+        "handler.SearchResponse": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
-                "name": {
+                "count": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "facets": {
+                    "$ref": "#/definitions/handler.SearchFacetsResponse"
+                },
+                "next": {
+                    "type": "string",
+                    "example": "/api/v1/users?page=2\u0026page_size=10"
+                },
+                "previous": {
+                    "type": "string",
+                    "example": "/api/v1/users?page=1\u0026page_size=10"
+                },
+                "results": {}
+            }
+        },
+        "handler.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.10"
+                },
+                "last_seen_at": {
                     "type": "string"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (X11; Linux x86_64)"
                 }
             }
         },
-        "handler.ErrorResponse": {
+        "handler.SubmitJoinRequestRequest": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "message": {
+                    "type": "string",
+                    "example": "Пишу на Go третий год, хочу помочь с бэкендом"
                 }
             }
         },
-        "handler.InviteMemberRequest": {
+        "handler.TOTPSetupResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "role"
-            ],
             "properties": {
-                "email": {
+                "provisioning_uri": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "otpauth://totp/Shance:user@example.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=Shance"
                 },
-                "role": {
+                "secret": {
                     "type": "string",
-                    "example": "member"
+                    "example": "JBSWY3DPEHPK3PXP"
                 }
             }
         },
-        "handler.LoginRequest": {
+        "handler.TagResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "password": {
+                "name": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "Тег"
                 }
             }
         },
-        "handler.ProjectMemberResponse": {
+        "handler.TokenResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "access_token": {
+                    "type": "string"
                 },
-                "first_name": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TransitionProjectRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "comment": {
                     "type": "string",
-                    "example": "Иван"
+                    "example": "Ищем дизайнера и двух разработчиков"
                 },
-                "id": {
-                    "type": "integer",
-                    "example": 1
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-09-30T00:00:00Z"
                 },
-                "last_name": {
+                "start_date": {
                     "type": "string",
-                    "example": "Иванов"
+                    "example": "2024-04-01T00:00:00Z"
                 },
-                "role": {
+                "status": {
                     "type": "string",
-                    "example": "member"
+                    "enum": [
+                        "draft",
+                        "recruiting",
+                        "active",
+                        "paused",
+                        "completed",
+                        "archived"
+                    ],
+                    "example": "recruiting"
                 }
             }
         },
-        "handler.ProjectResponse": {
+        "handler.UnlockAccountRequest": {
             "type": "object",
+            "required": [
+                "token"
+            ],
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2024-03-20T12:00:00Z"
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UpdateProjectRequest": {
+            "type": "object",
+            "properties": {
+                "accepts_join_requests": {
+                    "description": "AcceptsJoinRequests включает или выключает прием заявок на вступление; пустое значение оставляет прежнее",
+                    "type": "boolean",
+                    "example": false
                 },
                 "description": {
                     "type": "string",
-                    "example": "Описание проекта"
-                },
-                "id": {
-                    "type": "integer",
-                    "example": 1
+                    "example": "Новое описание"
                 },
                 "name": {
                     "type": "string",
-                    "example": "Новый проект"
+                    "example": "Обновленный проект"
                 },
                 "photo": {
                     "type": "array",
@@ -1278,13 +7616,13 @@ const docTemplate = `{
                         "type": "string"
                     },
                     "example": [
-                        "['photo1.jpg'",
-                        " 'photo2.jpg']"
+                        "['new_photo1.jpg'",
+                        " 'new_photo2.jpg']"
                     ]
                 },
                 "subtitle": {
                     "type": "string",
-                    "example": "Подзаголовок проекта"
+                    "example": "Новый подзаголовок"
                 },
                 "tags": {
                     "type": "array",
@@ -1292,75 +7630,215 @@ const docTemplate = `{
                         "type": "string"
                     },
                     "example": [
-                        "tag1",
-                        "tag2"
+                        "new_tag1",
+                        "new_tag2"
                     ]
                 },
                 "title": {
                     "type": "string",
-                    "example": "Заголовок проекта"
-                },
-                "user": {
-                    "$ref": "#/definitions/handler.UserResponse"
+                    "example": "Новый заголовок"
                 },
-                "user_id": {
-                    "type": "integer",
-                    "example": 1
+                "visibility": {
+                    "description": "Visibility — новый уровень доступа к проекту; пустое значение оставляет прежний",
+                    "type": "string",
+                    "enum": [
+                        "public",
+                        "unlisted",
+                        "private"
+                    ],
+                    "example": "unlisted"
                 }
             }
         },
-        "handler.RefreshTokenRequest": {
+        "handler.UpdateRoleRequest": {
             "type": "object",
             "required": [
-                "refresh_token"
+                "role"
             ],
             "properties": {
-                "refresh_token": {
-                    "type": "string"
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "moderator",
+                        "admin"
+                    ],
+                    "example": "moderator"
                 }
             }
         },
-        "handler.RegisterRequest": {
+        "handler.UpdateTagRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Обновленный тег"
+                }
+            }
+        },
+        "handler.UpdateUserRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "first_name",
-                "last_name",
-                "password"
-            ],
             "properties": {
                 "city": {
                     "type": "string",
-                    "example": "Moscow"
+                    "example": "Санкт-Петербург"
                 },
-                "country": {
+                "name": {
                     "type": "string",
-                    "example": "Russia"
+                    "example": "Новое имя"
                 },
-                "email": {
+                "photo": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "new_photo1.jpg",
+                        " new_photo2.jpg"
+                    ]
+                },
+                "subtitle": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "Новый номер телефона"
                 },
-                "first_name": {
+                "tags": {
                     "type": "string",
-                    "example": "John"
+                    "example": "РА СИ Я"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Новая фамилия"
+                }
+            }
+        },
+        "handler.UpdateVacancyRequest": {
+            "type": "object",
+            "properties": {
+                "compensation_max": {
+                    "type": "integer",
+                    "example": 150000
+                },
+                "compensation_min": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "employment_type": {
+                    "type": "string",
+                    "enum": [
+                        "volunteer",
+                        "paid",
+                        "equity",
+                        "internship"
+                    ],
+                    "example": "paid"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "hours_per_week": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "openings": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "remote_policy": {
+                    "type": "string",
+                    "enum": [
+                        "remote",
+                        "hybrid",
+                        "onsite"
+                    ],
+                    "example": "hybrid"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "maintainer",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
+                },
+                "seniority": {
+                    "description": "Условия работы; пустая строка или ноль снимает условие",
+                    "type": "string",
+                    "enum": [
+                        "junior",
+                        "middle",
+                        "senior",
+                        "lead"
+                    ],
+                    "example": "middle"
+                },
+                "status": {
+                    "description": "Status закрывает вакансию или открывает ее снова",
+                    "type": "string",
+                    "enum": [
+                        "open",
+                        "closed",
+                        "filled"
+                    ],
+                    "example": "closed"
+                },
+                "technologies": {
+                    "description": "id технологий",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "handler.UserProfileResponse": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "$ref": "#/definitions/handler.AvatarResponse"
                 },
-                "last_name": {
+                "city": {
                     "type": "string",
-                    "example": "Doe"
+                    "example": "Санкт-Петербург"
                 },
-                "password": {
+                "country": {
                     "type": "string",
-                    "minLength": 6,
-                    "example": "password123"
+                    "example": "Россия"
                 },
-                "phone": {
+                "created_at": {
+                    "type": "string"
+                },
+                "first_name": {
                     "type": "string",
-                    "example": "+79001234567"
+                    "example": "Иван"
                 },
-                "role": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_name": {
                     "type": "string",
-                    "example": "user"
+                    "example": "Иванов"
+                },
+                "portfolio": {
+                    "$ref": "#/definitions/handler.PortfolioResponse"
                 },
                 "tags": {
                     "type": "array",
@@ -1368,60 +7846,61 @@ const docTemplate = `{
                         "type": "string"
                     },
                     "example": [
-                        "tag1",
-                        "tag2"
+                        "go",
+                        "backend"
                     ]
                 }
             }
         },
-        "handler.TagResponse": {
+        "handler.UserResponse": {
             "type": "object",
             "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "ivan@example.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "Иван"
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
                 },
-                "name": {
+                "last_name": {
                     "type": "string",
-                    "example": "Тег"
+                    "example": "Иванов"
                 }
             }
         },
-        "handler.TokenResponse": {
+        "handler.UserSummaryResponse": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "avatar": {
+                    "$ref": "#/definitions/handler.AvatarResponse"
                 },
-                "refresh_token": {
-                    "type": "string"
-                }
-            }
-        },
-        "handler.UpdateProjectRequest": {
-            "type": "object",
-            "properties": {
-                "description": {
+                "city": {
                     "type": "string",
-                    "example": "Новое описание"
+                    "example": "Санкт-Петербург"
                 },
-                "name": {
+                "country": {
                     "type": "string",
-                    "example": "Обновленный проект"
+                    "example": "Россия"
                 },
-                "photo": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "['new_photo1.jpg'",
-                        " 'new_photo2.jpg']"
-                    ]
+                "created_at": {
+                    "type": "string"
                 },
-                "subtitle": {
+                "first_name": {
                     "type": "string",
-                    "example": "Новый подзаголовок"
+                    "example": "Иван"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Иванов"
                 },
                 "tags": {
                     "type": "array",
@@ -1429,88 +7908,258 @@ const docTemplate = `{
                         "type": "string"
                     },
                     "example": [
-                        "new_tag1",
-                        "new_tag2"
+                        "go",
+                        "backend"
                     ]
-                },
-                "title": {
-                    "type": "string",
-                    "example": "Новый заголовок"
                 }
             }
         },
-        "handler.UpdateTagRequest": {
+        "handler.VacancyResponse": {
             "type": "object",
             "properties": {
-                "name": {
+                "compensation_max": {
+                    "type": "integer",
+                    "example": 150000
+                },
+                "compensation_min": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
                     "type": "string",
-                    "example": "Обновленный тег"
+                    "example": "RUB"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "employment_type": {
+                    "type": "string",
+                    "enum": [
+                        "volunteer",
+                        "paid",
+                        "equity",
+                        "internship"
+                    ],
+                    "example": "paid"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "hours_per_week": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Москва"
+                },
+                "openings": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "remote_policy": {
+                    "type": "string",
+                    "enum": [
+                        "remote",
+                        "hybrid",
+                        "onsite"
+                    ],
+                    "example": "hybrid"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "maintainer",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
+                },
+                "seniority": {
+                    "type": "string",
+                    "enum": [
+                        "junior",
+                        "middle",
+                        "senior",
+                        "lead"
+                    ],
+                    "example": "middle"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "open",
+                        "closed",
+                        "filled"
+                    ],
+                    "example": "open"
+                },
+                "technology_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "handler.UpdateUserRequest": {
+        "handler.VacancySearchResponse": {
             "type": "object",
             "properties": {
-                "city": {
+                "compensation_max": {
+                    "type": "integer",
+                    "example": 150000
+                },
+                "compensation_min": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
                     "type": "string",
-                    "example": "Санкт-Петербург"
+                    "example": "RUB"
                 },
                 "description": {
+                    "type": "string"
+                },
+                "employment_type": {
                     "type": "string",
-                    "example": "Новая роль"
+                    "enum": [
+                        "volunteer",
+                        "paid",
+                        "equity",
+                        "internship"
+                    ],
+                    "example": "paid"
                 },
-                "name": {
+                "expires_at": {
                     "type": "string",
-                    "example": "Новое имя"
+                    "example": "2024-06-01T00:00:00Z"
                 },
-                "photo": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "new_photo1.jpg",
-                        " new_photo2.jpg"
-                    ]
+                "headline": {
+                    "type": "string",
+                    "example": "Ищем \u003cmark\u003eразработчика\u003c/mark\u003e на Go"
                 },
-                "subtitle": {
+                "hours_per_week": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
                     "type": "string",
-                    "example": "Новый номер телефона"
+                    "example": "Москва"
                 },
-                "tags": {
+                "openings": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.6079271
+                },
+                "remote_policy": {
                     "type": "string",
-                    "example": "РА СИ Я"
+                    "enum": [
+                        "remote",
+                        "hybrid",
+                        "onsite"
+                    ],
+                    "example": "hybrid"
                 },
-                "title": {
+                "role": {
                     "type": "string",
-                    "example": "Новая фамилия"
+                    "enum": [
+                        "maintainer",
+                        "member",
+                        "viewer"
+                    ],
+                    "example": "member"
+                },
+                "seniority": {
+                    "type": "string",
+                    "enum": [
+                        "junior",
+                        "middle",
+                        "senior",
+                        "lead"
+                    ],
+                    "example": "middle"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "open",
+                        "closed",
+                        "filled"
+                    ],
+                    "example": "open"
+                },
+                "technology_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "handler.UserResponse": {
+        "handler.VerifyEmailRequest": {
             "type": "object",
+            "required": [
+                "token"
+            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "ivan@example.com"
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SwaggerListResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 100
                 },
-                "first_name": {
+                "next": {
                     "type": "string",
-                    "example": "Иван"
-                },
-                "id": {
-                    "type": "integer",
-                    "example": 1
+                    "example": "/api/v1/users?page=2\u0026page_size=10"
                 },
-                "last_name": {
+                "previous": {
                     "type": "string",
-                    "example": "Иванов"
-                }
+                    "example": "/api/v1/users?page=1\u0026page_size=10"
+                },
+                "results": {}
             }
         },
         "models.SwaggerProject": {
             "type": "object",
             "properties": {
+                "accepts_join_requests": {
+                    "description": "AcceptsJoinRequests — принимает ли проект заявки на вступление",
+                    "type": "boolean"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -1540,24 +8189,63 @@ const docTemplate = `{
                 },
                 "user_id": {
                     "type": "integer"
+                },
+                "visibility": {
+                    "type": "string"
                 }
             }
         },
         "models.SwaggerProjectVacancy": {
             "type": "object",
             "properties": {
+                "compensation_max": {
+                    "type": "integer"
+                },
+                "compensation_min": {
+                    "type": "integer"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "currency": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
+                "employment_type": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "hours_per_week": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "location": {
+                    "type": "string"
+                },
+                "openings": {
+                    "type": "integer"
+                },
                 "project_id": {
                     "type": "integer"
                 },
+                "remote_policy": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "seniority": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
                 "technology_names": {
                     "type": "array",
                     "items": {
@@ -1624,6 +8312,25 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "repository.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Bearer \u003cJWT или персональный токен\u003e. Персональный токен также принимается в заголовке X-API-Key, JWT — в cookie access_token",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,60 @@
+// Package auth описывает аутентифицированного пользователя запроса.
+package auth
+
+import "github.com/gin-gonic/gin"
+
+// Способы аутентификации
+const (
+	MethodSession = "session"
+	MethodToken   = "token"
+)
+
+const principalKey = "principal"
+
+// Principal — пользователь, от имени которого выполняется запрос
+type Principal struct {
+	UserID uint
+	Email  string
+	Role   string
+	Method string
+	// SessionID заполняется при входе по access токену
+	SessionID uint
+	// ImpersonatorID — администратор, действующий от имени пользователя UserID
+	ImpersonatorID uint
+	// Scopes и TokenID заполняются только при входе по персональному токену
+	Scopes  []string
+	TokenID uint
+}
+
+// HasScope сообщает, разрешено ли действие. Сессии пользователя не ограничены scope.
+func (p *Principal) HasScope(scope string) bool {
+	if p.Method != MethodToken {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Impersonated сообщает, что запрос выполняет администратор от имени пользователя
+func (p *Principal) Impersonated() bool {
+	return p.ImpersonatorID != 0
+}
+
+// SetPrincipal сохраняет пользователя в контексте запроса
+func SetPrincipal(c *gin.Context, p *Principal) {
+	c.Set(principalKey, p)
+}
+
+// FromContext возвращает пользователя запроса, если запрос аутентифицирован
+func FromContext(c *gin.Context) (*Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return nil, false
+	}
+	p, ok := value.(*Principal)
+	return p, ok
+}
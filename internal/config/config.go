@@ -2,9 +2,15 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// DefaultJWTSecret — секрет по умолчанию для разработки. Вне ENV=dev приложение
+// с этим секретом не запускается, если не заданы асимметричные ключи.
+const DefaultJWTSecret = "your-secret-key"
+
 type Config struct {
 	Database struct {
 		Host     string
@@ -14,17 +20,135 @@ type Config struct {
 		DBName   string
 	}
 	Server struct {
-		Host string
-		Port string
-		Env  string
+		Host      string
+		Port      string
+		Env       string
+		PublicURL string
+		// TrustedProxies — адреса и подсети (CIDR) обратных прокси, которым разрешено
+		// передавать адрес клиента в X-Forwarded-For и X-Real-IP. Задаются через запятую
+		// в TRUSTED_PROXIES. По умолчанию список пуст, и адресом клиента считается адрес
+		// соединения: иначе клиент мог бы подставить заголовок и обойти ограничение
+		// попыток входа по IP.
+		TrustedProxies []string
 	}
 	JWT struct {
 		Secret          string
 		AccessTokenTTL  time.Duration
 		RefreshTokenTTL time.Duration
+		Issuer          string
+		Audience        string
+		// KeysFile — список асимметричных ключей подписи (см. signing.LoadKeys).
+		// Если не задан, токены подписываются HS256 секретом Secret.
+		KeysFile string
+		// AcceptHS256Until — до какого момента после перехода на асимметричные
+		// ключи принимаются токены, подписанные секретом Secret. До этого же
+		// момента принимаются HS256 токены без iss и aud, выданные до их появления.
+		AcceptHS256Until time.Time
+	}
+	Auth struct {
+		RequireVerifiedEmail bool
+		PasswordResetTTL     time.Duration
+		EmailVerificationTTL time.Duration
+		RequireAdminMFA      bool
+		MFAIssuer            string
+		MFAChallengeTTL      time.Duration
+		MFAMaxAttempts       int // неверных кодов, после которых MFA токен перестает действовать
+		AccountUnlockTTL     time.Duration
+		ImpersonationTTL     time.Duration
+		// VettedAdmins и VettedModerators — email пользователей, чьи роли admin
+		// и moderator сохраняются при сбросе ролей, назначенных самими пользователями
+		VettedAdmins     []string
+		VettedModerators []string
+	}
+	// Throttle — защита входа от перебора паролей
+	Throttle struct {
+		Store              string
+		AccountMaxAttempts int
+		IPMaxAttempts      int
+		BaseDelay          time.Duration
+		MaxDelay           time.Duration
+		LockoutDuration    time.Duration
+		Window             time.Duration
+	}
+	// Account — удаление аккаунта и выгрузка данных пользователя
+	Account struct {
+		DeletionGracePeriod time.Duration
+		ExportDir           string
+		ExportTTL           time.Duration
+	}
+	// Projects — приглашения в проекты
+	Projects struct {
+		InvitationTTL time.Duration
+	}
+	// Jobs — выполнение фоновых задач
+	Jobs struct {
+		PollInterval time.Duration
+		Lease        time.Duration
+		MaxAttempts  int
+		RetryDelay   time.Duration
+		// VacancyExpiryInterval — как часто закрывать вакансии с истекшим сроком
+		VacancyExpiryInterval time.Duration
+		// ExportCleanupInterval — как часто удалять архивы выгрузок с истекшим сроком хранения
+		ExportCleanupInterval time.Duration
+	}
+	// Storage — хранилище загружаемых файлов: local (директория на диске)
+	// или s3 (S3-совместимое хранилище, например MinIO)
+	Storage struct {
+		Driver   string
+		LocalDir string
+		// PublicURL — адрес, по которому файлы раздаются статически. Для s3,
+		// если адрес не задан, выдаются подписанные ссылки со сроком URLTTL.
+		PublicURL   string
+		URLTTL      time.Duration
+		S3Endpoint  string
+		S3Region    string
+		S3Bucket    string
+		S3AccessKey string
+		S3SecretKey string
+		S3PathStyle bool
+		// PrivateDir и S3PrivateBucket — закрытое хранилище вложений откликов.
+		// Оно не раздается статически, файлы выдаются только через API.
+		PrivateDir      string
+		S3PrivateBucket string
+	}
+	// Uploads — ограничения на загружаемые фотографии и файлы
+	Uploads struct {
+		MaxPhotoSize      int64
+		MaxProjectPhotos  int
+		MaxAvatarSize     int64
+		MaxPortfolioSize  int64
+		MaxPortfolioFiles int
+		// MaxAttachmentSize — размер вложения к отклику на вакансию
+		MaxAttachmentSize int64
+	}
+	Mail struct {
+		Driver       string
+		From         string
+		SMTPHost     string
+		SMTPPort     string
+		SMTPUser     string
+		SMTPPassword string
+		Dir          string
+	}
+	OAuth struct {
+		RedirectBaseURL string
+		StateTTL        time.Duration
+		Providers       []OAuthProvider
 	}
 }
 
+// OAuthProvider описывает внешнего провайдера входа.
+// Настраивается переменными OAUTH_<NAME>_CLIENT_ID, OAUTH_<NAME>_CLIENT_SECRET,
+// OAUTH_<NAME>_ISSUER, OAUTH_<NAME>_SCOPES и OAUTH_<NAME>_KIND.
+type OAuthProvider struct {
+	Name         string
+	Kind         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -32,6 +156,78 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvTime читает время в формате RFC 3339; при ошибке возвращает нулевое время
+func getEnvTime(key string) time.Time {
+	value, err := time.Parse(time.RFC3339, os.Getenv(key))
+	if err != nil {
+		return time.Time{}
+	}
+	return value
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func loadOAuthProviders() []OAuthProvider {
+	var providers []OAuthProvider
+	for _, name := range getEnvList("OAUTH_PROVIDERS") {
+		prefix := "OAUTH_" + strings.ToUpper(name) + "_"
+
+		provider := OAuthProvider{
+			Name:         name,
+			Kind:         getEnv(prefix+"KIND", "oidc"),
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:       getEnvList(prefix + "SCOPES"),
+		}
+
+		switch name {
+		case "github":
+			provider.Kind = getEnv(prefix+"KIND", "github")
+		case "google":
+			provider.Issuer = getEnv(prefix+"ISSUER", "https://accounts.google.com")
+		}
+
+		if provider.ClientID == "" {
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
 func LoadConfig() (*Config, error) {
 	config := &Config{
 		Database: struct {
@@ -48,22 +244,175 @@ func LoadConfig() (*Config, error) {
 			DBName:   getEnv("DB_NAME", "shance_db"),
 		},
 		Server: struct {
-			Host string
-			Port string
-			Env  string
+			Host           string
+			Port           string
+			Env            string
+			PublicURL      string
+			TrustedProxies []string
 		}{
-			Host: getEnv("SERVER_HOST", "localhost"),
-			Port: getEnv("SERVER_PORT", "8000"),
-			Env:  getEnv("ENV", "prod"),
+			Host:           getEnv("SERVER_HOST", "localhost"),
+			Port:           getEnv("SERVER_PORT", "8000"),
+			Env:            getEnv("ENV", "prod"),
+			PublicURL:      getEnv("PUBLIC_URL", "http://localhost:3000"),
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 		JWT: struct {
-			Secret          string
-			AccessTokenTTL  time.Duration
-			RefreshTokenTTL time.Duration
+			Secret           string
+			AccessTokenTTL   time.Duration
+			RefreshTokenTTL  time.Duration
+			Issuer           string
+			Audience         string
+			KeysFile         string
+			AcceptHS256Until time.Time
+		}{
+			Secret:           getEnv("JWT_SECRET", DefaultJWTSecret),
+			AccessTokenTTL:   getEnvDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL:  getEnvDuration("JWT_REFRESH_TOKEN_TTL", 720*time.Hour),
+			Issuer:           getEnv("JWT_ISSUER", "shance"),
+			Audience:         getEnv("JWT_AUDIENCE", "shance-api"),
+			KeysFile:         getEnv("JWT_KEYS_FILE", ""),
+			AcceptHS256Until: getEnvTime("JWT_ACCEPT_HS256_UNTIL"),
+		},
+		Auth: struct {
+			RequireVerifiedEmail bool
+			PasswordResetTTL     time.Duration
+			EmailVerificationTTL time.Duration
+			RequireAdminMFA      bool
+			MFAIssuer            string
+			MFAChallengeTTL      time.Duration
+			MFAMaxAttempts       int
+			AccountUnlockTTL     time.Duration
+			ImpersonationTTL     time.Duration
+			VettedAdmins         []string
+			VettedModerators     []string
+		}{
+			RequireVerifiedEmail: getEnvBool("AUTH_REQUIRE_VERIFIED_EMAIL", false),
+			PasswordResetTTL:     time.Hour,
+			EmailVerificationTTL: 48 * time.Hour,
+			RequireAdminMFA:      getEnvBool("AUTH_REQUIRE_ADMIN_MFA", false),
+			MFAIssuer:            getEnv("MFA_ISSUER", "Shance"),
+			MFAChallengeTTL:      5 * time.Minute,
+			MFAMaxAttempts:       getEnvInt("AUTH_MFA_MAX_ATTEMPTS", 5),
+			AccountUnlockTTL:     24 * time.Hour,
+			ImpersonationTTL:     getEnvDuration("AUTH_IMPERSONATION_TTL", 30*time.Minute),
+			VettedAdmins:         getEnvList("AUTH_VETTED_ADMINS"),
+			VettedModerators:     getEnvList("AUTH_VETTED_MODERATORS"),
+		},
+		Throttle: struct {
+			Store              string
+			AccountMaxAttempts int
+			IPMaxAttempts      int
+			BaseDelay          time.Duration
+			MaxDelay           time.Duration
+			LockoutDuration    time.Duration
+			Window             time.Duration
+		}{
+			Store:              getEnv("THROTTLE_STORE", "postgres"),
+			AccountMaxAttempts: getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+			IPMaxAttempts:      getEnvInt("LOGIN_IP_MAX_ATTEMPTS", 50),
+			BaseDelay:          time.Second,
+			MaxDelay:           time.Minute,
+			LockoutDuration:    getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			Window:             15 * time.Minute,
+		},
+		Account: struct {
+			DeletionGracePeriod time.Duration
+			ExportDir           string
+			ExportTTL           time.Duration
+		}{
+			DeletionGracePeriod: getEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+			ExportDir:           getEnv("EXPORT_DIR", "data/exports"),
+			ExportTTL:           getEnvDuration("EXPORT_TTL", 7*24*time.Hour),
+		},
+		Projects: struct {
+			InvitationTTL time.Duration
+		}{
+			InvitationTTL: getEnvDuration("PROJECT_INVITATION_TTL", 7*24*time.Hour),
+		},
+		Jobs: struct {
+			PollInterval          time.Duration
+			Lease                 time.Duration
+			MaxAttempts           int
+			RetryDelay            time.Duration
+			VacancyExpiryInterval time.Duration
+			ExportCleanupInterval time.Duration
+		}{
+			PollInterval:          getEnvDuration("JOBS_POLL_INTERVAL", 5*time.Second),
+			Lease:                 10 * time.Minute,
+			MaxAttempts:           getEnvInt("JOBS_MAX_ATTEMPTS", 3),
+			RetryDelay:            time.Minute,
+			VacancyExpiryInterval: getEnvDuration("JOBS_VACANCY_EXPIRY_INTERVAL", 15*time.Minute),
+			ExportCleanupInterval: getEnvDuration("JOBS_EXPORT_CLEANUP_INTERVAL", time.Hour),
+		},
+		Storage: struct {
+			Driver      string
+			LocalDir    string
+			PublicURL   string
+			URLTTL      time.Duration
+			S3Endpoint  string
+			S3Region    string
+			S3Bucket    string
+			S3AccessKey string
+			S3SecretKey string
+			S3PathStyle bool
+
+			PrivateDir      string
+			S3PrivateBucket string
+		}{
+			Driver:      getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:    getEnv("STORAGE_LOCAL_DIR", "uploads"),
+			PublicURL:   getEnv("STORAGE_PUBLIC_URL", ""),
+			URLTTL:      getEnvDuration("STORAGE_URL_TTL", time.Hour),
+			S3Endpoint:  getEnv("S3_ENDPOINT", "http://localhost:9000"),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", "shance"),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle: getEnvBool("S3_PATH_STYLE", true),
+
+			PrivateDir:      getEnv("STORAGE_PRIVATE_DIR", "data/private"),
+			S3PrivateBucket: getEnv("S3_PRIVATE_BUCKET", ""),
+		},
+		Uploads: struct {
+			MaxPhotoSize      int64
+			MaxProjectPhotos  int
+			MaxAvatarSize     int64
+			MaxPortfolioSize  int64
+			MaxPortfolioFiles int
+			MaxAttachmentSize int64
+		}{
+			MaxPhotoSize:      int64(getEnvInt("UPLOAD_MAX_PHOTO_SIZE", 10<<20)),
+			MaxProjectPhotos:  getEnvInt("UPLOAD_MAX_PROJECT_PHOTOS", 10),
+			MaxAvatarSize:     int64(getEnvInt("UPLOAD_MAX_AVATAR_SIZE", 5<<20)),
+			MaxPortfolioSize:  int64(getEnvInt("UPLOAD_MAX_PORTFOLIO_FILE_SIZE", 20<<20)),
+			MaxPortfolioFiles: getEnvInt("UPLOAD_MAX_PORTFOLIO_FILES", 10),
+			MaxAttachmentSize: int64(getEnvInt("UPLOAD_MAX_APPLICATION_ATTACHMENT_SIZE", 10<<20)),
+		},
+		Mail: struct {
+			Driver       string
+			From         string
+			SMTPHost     string
+			SMTPPort     string
+			SMTPUser     string
+			SMTPPassword string
+			Dir          string
+		}{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "Shance <no-reply@shance.local>"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUser:     getEnv("SMTP_USER", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			Dir:          getEnv("MAIL_DIR", ""),
+		},
+		OAuth: struct {
+			RedirectBaseURL string
+			StateTTL        time.Duration
+			Providers       []OAuthProvider
 		}{
-			Secret:          getEnv("JWT_SECRET", "your-secret-key"),
-			AccessTokenTTL:  15 * time.Minute,
-			RefreshTokenTTL: 720 * time.Hour,
+			RedirectBaseURL: getEnv("OAUTH_REDIRECT_BASE_URL", "http://localhost:8000/api/v1/auth/oauth"),
+			StateTTL:        10 * time.Minute,
+			Providers:       loadOAuthProviders(),
 		},
 	}
 
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/config"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"gorm.io/gorm"
+)
+
+// dataMigration — однократное изменение данных. Name не меняется после выпуска:
+// по нему определяется, что изменение уже применено.
+type dataMigration struct {
+	Name string
+	Run  func(tx *gorm.DB) error
+}
+
+func dataMigrations(cfg *config.Config) []dataMigration {
+	return []dataMigration{
+		{
+			Name: "reset_unvetted_roles",
+			Run: func(tx *gorm.DB) error {
+				return resetUnvettedRoles(tx, cfg.Auth.VettedAdmins, cfg.Auth.VettedModerators)
+			},
+		},
+	}
+}
+
+// runDataMigrations применяет еще не выполненные изменения данных. Каждое изменение
+// выполняется в одной транзакции с записью о нем, поэтому не применяется дважды.
+func runDataMigrations(db *gorm.DB, migrations []dataMigration) error {
+	for _, migration := range migrations {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var applied models.DataMigration
+			err := tx.Where("name = ?", migration.Name).First(&applied).Error
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			if err := migration.Run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&models.DataMigration{Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to run data migration %s: %w", migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// resetUnvettedRoles сбрасывает до user роли, которые пользователи могли назначить себе
+// сами до появления проверки прав. Роли admin и moderator сохраняются только
+// у пользователей из списков admins и moderators.
+func resetUnvettedRoles(tx *gorm.DB, admins, moderators []string) error {
+	query := tx.Unscoped().Model(&models.User{}).Where("role IS DISTINCT FROM ?", policy.RoleUser)
+	if len(admins) > 0 {
+		query = query.Where("NOT (role = ? AND lower(email) IN ?)", policy.RoleAdmin, lowerAll(admins))
+	}
+	if len(moderators) > 0 {
+		query = query.Where("NOT (role = ? AND lower(email) IN ?)", policy.RoleModerator, lowerAll(moderators))
+	}
+
+	result := query.UpdateColumn("role", policy.RoleUser)
+	if result.Error != nil {
+		return result.Error
+	}
+	log.Printf("Reset role to %s for %d users", policy.RoleUser, result.RowsAffected)
+	return nil
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, v := range values {
+		lowered[i] = strings.ToLower(v)
+	}
+	return lowered
+}
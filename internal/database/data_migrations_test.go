@@ -0,0 +1,76 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/levstremilov/shance-app/internal/dbtest"
+	"github.com/levstremilov/shance-app/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestResetUnvettedRoles(t *testing.T) {
+	db := dbtest.Open(t, &models.User{}, &models.DataMigration{})
+
+	roles := map[string]string{
+		"root@example.com":     "admin",
+		"self@example.com":     "admin",
+		"mod@example.com":      "moderator",
+		"selfmod@example.com":  "moderator",
+		"invented@example.com": "superuser",
+		"regular@example.com":  "user",
+		"adminmod@example.com": "moderator",
+		"modadmin@example.com": "admin",
+	}
+	for email, role := range roles {
+		if err := db.Create(&models.User{Email: email, PasswordHash: "hash", Role: role}).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	migrations := []dataMigration{{
+		Name: "reset_unvetted_roles",
+		Run: func(tx *gorm.DB) error {
+			// Списки из конфигурации сравниваются без учета регистра
+			return resetUnvettedRoles(tx, []string{"Root@Example.com", "adminmod@example.com"}, []string{"mod@example.com", "modadmin@example.com"})
+		},
+	}}
+	if err := runDataMigrations(db, migrations); err != nil {
+		t.Fatalf("runDataMigrations: %v", err)
+	}
+
+	want := map[string]string{
+		"root@example.com":     "admin",
+		"self@example.com":     "user",
+		"mod@example.com":      "moderator",
+		"selfmod@example.com":  "user",
+		"invented@example.com": "user",
+		"regular@example.com":  "user",
+		// Проверенная роль сохраняется только та, что указана в списке
+		"adminmod@example.com": "user",
+		"modadmin@example.com": "user",
+	}
+	for email, role := range want {
+		var user models.User
+		if err := db.Where("email = ?", email).First(&user).Error; err != nil {
+			t.Fatalf("load %s: %v", email, err)
+		}
+		if user.Role != role {
+			t.Errorf("%s role = %q, want %q", email, user.Role, role)
+		}
+	}
+
+	// Повторный запуск не трогает роли, выданные администратором после сброса
+	if err := db.Model(&models.User{}).Where("email = ?", "self@example.com").Update("role", "admin").Error; err != nil {
+		t.Fatalf("promote user: %v", err)
+	}
+	if err := runDataMigrations(db, migrations); err != nil {
+		t.Fatalf("second runDataMigrations: %v", err)
+	}
+	var promoted models.User
+	if err := db.Where("email = ?", "self@example.com").First(&promoted).Error; err != nil {
+		t.Fatalf("load promoted user: %v", err)
+	}
+	if promoted.Role != "admin" {
+		t.Fatalf("data migration ran twice: role = %q", promoted.Role)
+	}
+}
@@ -28,18 +28,49 @@ func InitDB(cfg *config.Config) (*gorm.DB, error) {
 
 	err = db.AutoMigrate(
 		&models.User{},
+		&models.UserAvatar{},
+		&models.UserAvatarVariant{},
+		&models.PortfolioLink{},
+		&models.PortfolioFile{},
 		&models.Tag{},
 		&models.Project{},
+		&models.ProjectTransition{},
+		&models.ProjectPhoto{},
+		&models.ProjectPhotoVariant{},
 		&models.ProjectMember{},
+		&models.ProjectInvitation{},
+		&models.ProjectJoinRequest{},
 		&models.UserTag{},
 		&models.ProjectTag{},
 		&models.ProjectVacancy{},
 		&models.VacancyTechnology{},
 		&models.Technology{},
+		&models.VacancyApplication{},
+		&models.ApplicationNote{},
+		&models.ApplicationStageChange{},
+		&models.RefreshToken{},
+		&models.Session{},
+		&models.OneTimeToken{},
+		&models.UserIdentity{},
+		&models.OAuthState{},
+		&models.MFARecoveryCode{},
+		&models.PersonalAccessToken{},
+		&models.ThrottleEntry{},
+		&models.AuditLog{},
+		&models.Job{},
+		&models.DataMigration{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := setupSearch(db); err != nil {
+		return nil, err
+	}
+
+	if err := runDataMigrations(db, dataMigrations(cfg)); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
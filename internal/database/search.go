@@ -0,0 +1,131 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Поисковые векторы проектов и вакансий поддерживаются триггерами, поэтому
+// остаются актуальными при любом способе записи, включая изменение тегов
+// и технологий. Текст индексируется в конфигурациях russian и english:
+// контент смешанный, и слово должно находиться в любой из форм.
+var searchStatements = []string{
+	`CREATE OR REPLACE FUNCTION project_search_vector(p_id bigint, p_name text, p_title text, p_subtitle text, p_description text)
+	RETURNS tsvector LANGUAGE sql STABLE AS $$
+		WITH doc AS (
+			SELECT
+				concat_ws(' ', p_name, p_title) AS a,
+				concat_ws(' ', p_subtitle, (
+					SELECT string_agg(tags.name, ' ')
+					FROM project_tags JOIN tags ON tags.id = project_tags.tag_id
+					WHERE project_tags.project_id = p_id AND project_tags.deleted_at IS NULL AND tags.deleted_at IS NULL
+				)) AS b,
+				coalesce(p_description, '') AS c
+		)
+		SELECT setweight(to_tsvector('russian', a), 'A') || setweight(to_tsvector('english', a), 'A') ||
+			setweight(to_tsvector('russian', b), 'B') || setweight(to_tsvector('english', b), 'B') ||
+			setweight(to_tsvector('russian', c), 'C') || setweight(to_tsvector('english', c), 'C')
+		FROM doc
+	$$`,
+
+	`CREATE OR REPLACE FUNCTION vacancy_search_vector(v_id bigint, v_title text, v_description text)
+	RETURNS tsvector LANGUAGE sql STABLE AS $$
+		WITH doc AS (
+			SELECT
+				coalesce(v_title, '') AS a,
+				coalesce((
+					SELECT string_agg(technologies.name, ' ')
+					FROM vacancy_technologies JOIN technologies ON technologies.id = vacancy_technologies.technology_id
+					WHERE vacancy_technologies.project_vacancy_id = v_id AND vacancy_technologies.deleted_at IS NULL
+				), '') AS b,
+				coalesce(v_description, '') AS c
+		)
+		SELECT setweight(to_tsvector('russian', a), 'A') || setweight(to_tsvector('english', a), 'A') ||
+			setweight(to_tsvector('russian', b), 'B') || setweight(to_tsvector('english', b), 'B') ||
+			setweight(to_tsvector('russian', c), 'C') || setweight(to_tsvector('english', c), 'C')
+		FROM doc
+	$$`,
+
+	// Проекты
+	`CREATE OR REPLACE FUNCTION projects_search_vector_update() RETURNS trigger LANGUAGE plpgsql AS $$
+	BEGIN
+		NEW.search_vector := project_search_vector(NEW.id, NEW.name, NEW.title, NEW.subtitle, NEW.description);
+		RETURN NEW;
+	END
+	$$`,
+	`DROP TRIGGER IF EXISTS projects_search_vector ON projects`,
+	`CREATE TRIGGER projects_search_vector BEFORE INSERT OR UPDATE OF name, title, subtitle, description ON projects
+	FOR EACH ROW EXECUTE FUNCTION projects_search_vector_update()`,
+
+	`CREATE OR REPLACE FUNCTION project_tags_search_vector_update() RETURNS trigger LANGUAGE plpgsql AS $$
+	BEGIN
+		UPDATE projects SET search_vector = project_search_vector(id, name, title, subtitle, description)
+		WHERE id IN (NEW.project_id, OLD.project_id);
+		RETURN NULL;
+	END
+	$$`,
+	`DROP TRIGGER IF EXISTS project_tags_search_vector ON project_tags`,
+	`CREATE TRIGGER project_tags_search_vector AFTER INSERT OR UPDATE OR DELETE ON project_tags
+	FOR EACH ROW EXECUTE FUNCTION project_tags_search_vector_update()`,
+
+	`CREATE OR REPLACE FUNCTION tags_search_vector_update() RETURNS trigger LANGUAGE plpgsql AS $$
+	BEGIN
+		UPDATE projects SET search_vector = project_search_vector(id, name, title, subtitle, description)
+		WHERE id IN (SELECT project_id FROM project_tags WHERE tag_id = NEW.id);
+		RETURN NULL;
+	END
+	$$`,
+	`DROP TRIGGER IF EXISTS tags_search_vector ON tags`,
+	`CREATE TRIGGER tags_search_vector AFTER UPDATE OF name, deleted_at ON tags
+	FOR EACH ROW EXECUTE FUNCTION tags_search_vector_update()`,
+
+	// Вакансии
+	`CREATE OR REPLACE FUNCTION project_vacancies_search_vector_update() RETURNS trigger LANGUAGE plpgsql AS $$
+	BEGIN
+		NEW.search_vector := vacancy_search_vector(NEW.id, NEW.title, NEW.description);
+		RETURN NEW;
+	END
+	$$`,
+	`DROP TRIGGER IF EXISTS project_vacancies_search_vector ON project_vacancies`,
+	`CREATE TRIGGER project_vacancies_search_vector BEFORE INSERT OR UPDATE OF title, description ON project_vacancies
+	FOR EACH ROW EXECUTE FUNCTION project_vacancies_search_vector_update()`,
+
+	`CREATE OR REPLACE FUNCTION vacancy_technologies_search_vector_update() RETURNS trigger LANGUAGE plpgsql AS $$
+	BEGIN
+		UPDATE project_vacancies SET search_vector = vacancy_search_vector(id, title, description)
+		WHERE id IN (NEW.project_vacancy_id, OLD.project_vacancy_id);
+		RETURN NULL;
+	END
+	$$`,
+	`DROP TRIGGER IF EXISTS vacancy_technologies_search_vector ON vacancy_technologies`,
+	`CREATE TRIGGER vacancy_technologies_search_vector AFTER INSERT OR UPDATE OR DELETE ON vacancy_technologies
+	FOR EACH ROW EXECUTE FUNCTION vacancy_technologies_search_vector_update()`,
+
+	`CREATE OR REPLACE FUNCTION technologies_search_vector_update() RETURNS trigger LANGUAGE plpgsql AS $$
+	BEGIN
+		UPDATE project_vacancies SET search_vector = vacancy_search_vector(id, title, description)
+		WHERE id IN (SELECT project_vacancy_id FROM vacancy_technologies WHERE technology_id = NEW.id);
+		RETURN NULL;
+	END
+	$$`,
+	`DROP TRIGGER IF EXISTS technologies_search_vector ON technologies`,
+	`CREATE TRIGGER technologies_search_vector AFTER UPDATE OF name ON technologies
+	FOR EACH ROW EXECUTE FUNCTION technologies_search_vector_update()`,
+
+	// Заполнение векторов для записей, созданных до появления поиска
+	`UPDATE projects SET search_vector = project_search_vector(id, name, title, subtitle, description) WHERE search_vector IS NULL`,
+	`UPDATE project_vacancies SET search_vector = vacancy_search_vector(id, title, description) WHERE search_vector IS NULL`,
+}
+
+// setupSearch создает функции и триггеры полнотекстового поиска
+func setupSearch(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range searchStatements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("failed to set up full-text search: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,38 @@
+// Package dbtest открывает базы данных для тестов репозиториев и сервисов.
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open открывает отдельную базу SQLite в памяти и создает таблицы для models.
+// База закрывается по завершении теста.
+func Open(t testing.TB, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+		// Связанные таблицы (проекты, теги) тесту нужны не всегда, а их индексы
+		// рассчитаны на PostgreSQL
+		IgnoreRelationshipsWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	// В памяти каждая новая связь открывает пустую базу
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate test database: %v", err)
+	}
+	return db
+}
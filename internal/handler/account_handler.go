@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+type AccountHandler struct {
+	jobService      service.JobServiceInterface
+	exportService   service.DataExportServiceInterface
+	deletionService service.AccountDeletionServiceInterface
+}
+
+func NewAccountHandler(jobService service.JobServiceInterface, exportService service.DataExportServiceInterface, deletionService service.AccountDeletionServiceInterface) *AccountHandler {
+	return &AccountHandler{
+		jobService:      jobService,
+		exportService:   exportService,
+		deletionService: deletionService,
+	}
+}
+
+// JobResponse описывает фоновую задачу пользователя
+type JobResponse struct {
+	ID         uint       `json:"id" example:"1"`
+	Type       string     `json:"type" example:"data_export" enums:"data_export,account_deletion"`
+	Status     string     `json:"status" example:"pending" enums:"pending,running,completed,failed,cancelled"`
+	RunAt      time.Time  `json:"run_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	// DownloadURL — ссылка на архив для завершенной выгрузки данных
+	DownloadURL string    `json:"download_url,omitempty" example:"/api/v1/users/me/jobs/1/download"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newJobResponse(job *models.Job) JobResponse {
+	response := JobResponse{
+		ID:         job.ID,
+		Type:       job.Type,
+		Status:     job.Status,
+		RunAt:      job.RunAt,
+		FinishedAt: job.FinishedAt,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+	}
+	if job.Type == models.JobTypeDataExport && job.Status == models.JobStatusCompleted {
+		response.DownloadURL = fmt.Sprintf("/api/v1/users/me/jobs/%d/download", job.ID)
+	}
+	return response
+}
+
+// RequestExport godoc
+// @Summary Выгрузка данных аккаунта
+// @Description Ставит в очередь подготовку zip архива с JSON файлами профиля, тегов, проектов, участия в проектах и вакансий. Статус подготовки отслеживается по ID задачи
+// @Tags account
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 202 {object} JobResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/export [post]
+func (h *AccountHandler) RequestExport(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	job, err := h.exportService.Request(principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newJobResponse(job))
+}
+
+// GetJob godoc
+// @Summary Статус фоновой задачи
+// @Description Возвращает состояние выгрузки данных или удаления аккаунта текущего пользователя
+// @Tags account
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID задачи"
+// @Success 200 {object} JobResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/jobs/{id} [get]
+func (h *AccountHandler) GetJob(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid job ID"})
+		return
+	}
+
+	job, err := h.jobService.Get(principal.UserID, uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newJobResponse(job))
+}
+
+// DownloadExport godoc
+// @Summary Скачивание выгрузки данных
+// @Description Отдает zip архив завершенной выгрузки данных. Архив хранится ограниченное время
+// @Tags account
+// @Produce application/zip
+// @Security ApiKeyAuth
+// @Param id path int true "ID задачи выгрузки"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/jobs/{id}/download [get]
+func (h *AccountHandler) DownloadExport(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid job ID"})
+		return
+	}
+
+	path, err := h.exportService.Open(principal.UserID, uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrJobNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrExportNotReady):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrExportExpired):
+			c.JSON(http.StatusGone, ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	c.FileAttachment(path, fmt.Sprintf("shance-export-%d.zip", id))
+}
+
+// DeleteAccount godoc
+// @Summary Удаление аккаунта
+// @Description Назначает удаление аккаунта по истечении льготного периода. После него личные данные обезличиваются, проекты передаются участникам или архивируются. До этого удаление можно отменить
+// @Tags account
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 202 {object} JobResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	job, err := h.deletionService.Schedule(principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newJobResponse(job))
+}
+
+// CancelDeletion godoc
+// @Summary Отмена удаления аккаунта
+// @Description Отменяет назначенное удаление аккаунта, пока не истек льготный период
+// @Tags account
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/deletion [delete]
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	if err := h.deletionService.Cancel(principal.UserID); err != nil {
+		if errors.Is(err, service.ErrDeletionNotScheduled) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,631 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+// Поля multipart формы отклика на вакансию
+const (
+	coverLetterFormField = "cover_letter"
+	attachmentFormField  = "attachment"
+	// Сопроводительное письмо из формы читается не больше этого числа байт
+	maxCoverLetterFieldSize = 32 << 10
+)
+
+// ApplicationHandler представляет обработчик откликов на вакансии
+type ApplicationHandler struct {
+	applicationService service.VacancyApplicationServiceInterface
+}
+
+// NewApplicationHandler создает новый экземпляр ApplicationHandler
+func NewApplicationHandler(applicationService service.VacancyApplicationServiceInterface) *ApplicationHandler {
+	return &ApplicationHandler{applicationService: applicationService}
+}
+
+// ApplyRequest представляет отклик на вакансию без вложения
+type ApplyRequest struct {
+	CoverLetter string `json:"cover_letter" example:"Три года пишу сервисы на Go, хочу развиваться в продуктовой команде"`
+}
+
+// MoveApplicationRequest представляет запрос на смену этапа отклика
+type MoveApplicationRequest struct {
+	Stage   string `json:"stage" binding:"required" example:"interview" enums:"screening,interview,offer,accepted,rejected"`
+	Comment string `json:"comment" example:"Созвон в четверг в 18:00"`
+	// CloseVacancy при принятии кандидата помечает вакансию заполненной
+	CloseVacancy bool `json:"close_vacancy" example:"true"`
+}
+
+// AddApplicationNoteRequest представляет заметку к отклику
+type AddApplicationNoteRequest struct {
+	Body string `json:"body" binding:"required" example:"Сильное портфолио, стоит обсудить опыт с PostgreSQL"`
+}
+
+// ApplicationAttachmentResponse представляет вложение отклика
+type ApplicationAttachmentResponse struct {
+	// URL — адрес API, по которому вложение скачивается с авторизацией: для кандидата
+	// /users/me/applications/{id}/attachment, для команды проекта
+	// /projects/{id}/applications/{application_id}/attachment
+	URL         string `json:"url" example:"/api/v1/users/me/applications/1/attachment"`
+	Name        string `json:"name" example:"resume.pdf"`
+	ContentType string `json:"content_type" example:"application/pdf"`
+	Size        int64  `json:"size" example:"183204"`
+}
+
+// ApplicationResponse представляет отклик на вакансию
+type ApplicationResponse struct {
+	ID           uint          `json:"id" example:"1"`
+	VacancyID    uint          `json:"vacancy_id" example:"1"`
+	VacancyTitle string        `json:"vacancy_title" example:"Go-разработчик"`
+	ProjectID    uint          `json:"project_id" example:"1"`
+	ProjectName  string        `json:"project_name,omitempty" example:"Shance"`
+	User         *UserResponse `json:"user,omitempty"`
+	CoverLetter  string        `json:"cover_letter" example:"Три года пишу сервисы на Go, хочу развиваться в продуктовой команде"`
+	// Attachment пустой, если к отклику ничего не приложено
+	Attachment *ApplicationAttachmentResponse `json:"attachment"`
+	Stage      string                         `json:"stage" example:"new" enums:"new,screening,interview,offer,accepted,rejected,withdrawn"`
+	CreatedAt  time.Time                      `json:"created_at"`
+	UpdatedAt  time.Time                      `json:"updated_at"`
+}
+
+// ApplicationNoteResponse представляет заметку команды проекта к отклику
+type ApplicationNoteResponse struct {
+	ID        uint         `json:"id" example:"1"`
+	Author    UserResponse `json:"author"`
+	Body      string       `json:"body" example:"Сильное портфолио, стоит обсудить опыт с PostgreSQL"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// ApplicationStageChangeResponse представляет запись истории смены этапа отклика
+type ApplicationStageChangeResponse struct {
+	FromStage string `json:"from_stage" example:"new"`
+	ToStage   string `json:"to_stage" example:"interview"`
+	// UserID пустой, если этап сменила система
+	UserID    *uint     `json:"user_id" example:"1"`
+	Comment   string    `json:"comment" example:"Созвон в четверг в 18:00"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ApplicationDetailResponse представляет отклик с заметками и историей этапов
+type ApplicationDetailResponse struct {
+	ApplicationResponse
+	Notes   []ApplicationNoteResponse        `json:"notes"`
+	History []ApplicationStageChangeResponse `json:"history"`
+}
+
+// MoveApplicationResponse представляет отклик после смены этапа. Member заполнен,
+// если кандидат принят и стал участником проекта.
+type MoveApplicationResponse struct {
+	ApplicationDetailResponse
+	Member *ProjectMemberResponse `json:"member,omitempty"`
+}
+
+// attachmentURL строит адрес, по которому вложение отклика скачивается через API
+type attachmentURL func(application models.VacancyApplication) string
+
+// myAttachmentURL — адрес вложения для кандидата
+func myAttachmentURL(application models.VacancyApplication) string {
+	return fmt.Sprintf("/api/v1/users/me/applications/%d/attachment", application.ID)
+}
+
+// projectAttachmentURL — адрес вложения для команды проекта
+func projectAttachmentURL(application models.VacancyApplication) string {
+	return fmt.Sprintf("/api/v1/projects/%d/applications/%d/attachment", application.ProjectID, application.ID)
+}
+
+func newApplicationResponse(application models.VacancyApplication, url attachmentURL) ApplicationResponse {
+	response := ApplicationResponse{
+		ID:           application.ID,
+		VacancyID:    application.VacancyID,
+		VacancyTitle: application.Vacancy.Title,
+		ProjectID:    application.ProjectID,
+		ProjectName:  application.Vacancy.Project.Name,
+		CoverLetter:  application.CoverLetter,
+		Stage:        application.Stage,
+		CreatedAt:    application.CreatedAt,
+		UpdatedAt:    application.UpdatedAt,
+	}
+	if application.User.ID != 0 {
+		response.User = &UserResponse{
+			ID:        application.User.ID,
+			FirstName: application.User.FirstName,
+			LastName:  application.User.LastName,
+			Email:     application.User.Email,
+		}
+	}
+	if application.AttachmentKey != "" {
+		response.Attachment = &ApplicationAttachmentResponse{
+			URL:         url(application),
+			Name:        application.AttachmentName,
+			ContentType: application.AttachmentContentType,
+			Size:        application.AttachmentSize,
+		}
+	}
+	return response
+}
+
+func newApplicationResponses(list []models.VacancyApplication, url attachmentURL) []ApplicationResponse {
+	response := make([]ApplicationResponse, len(list))
+	for i, application := range list {
+		response[i] = newApplicationResponse(application, url)
+	}
+	return response
+}
+
+func newApplicationNoteResponse(note models.ApplicationNote) ApplicationNoteResponse {
+	return ApplicationNoteResponse{
+		ID: note.ID,
+		Author: UserResponse{
+			ID:        note.Author.ID,
+			FirstName: note.Author.FirstName,
+			LastName:  note.Author.LastName,
+			Email:     note.Author.Email,
+		},
+		Body:      note.Body,
+		CreatedAt: note.CreatedAt,
+	}
+}
+
+func newApplicationDetailResponse(application models.VacancyApplication) ApplicationDetailResponse {
+	response := ApplicationDetailResponse{
+		ApplicationResponse: newApplicationResponse(application, projectAttachmentURL),
+		Notes:               make([]ApplicationNoteResponse, len(application.Notes)),
+		History:             make([]ApplicationStageChangeResponse, len(application.History)),
+	}
+	for i, note := range application.Notes {
+		response.Notes[i] = newApplicationNoteResponse(note)
+	}
+	for i, change := range application.History {
+		response.History[i] = ApplicationStageChangeResponse{
+			FromStage: change.FromStage,
+			ToStage:   change.ToStage,
+			UserID:    change.UserID,
+			Comment:   change.Comment,
+			CreatedAt: change.CreatedAt,
+		}
+	}
+	return response
+}
+
+func respondApplicationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrVacancyNotFound),
+		errors.Is(err, service.ErrApplicationNotFound),
+		errors.Is(err, service.ErrAttachmentNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrVacancyClosed):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrApplicationExists),
+		errors.Is(err, service.ErrAlreadyProjectMember),
+		errors.Is(err, service.ErrStageChangeNotAllowed),
+		errors.Is(err, service.ErrApplicationStageChanged):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrUnsupportedAttachment):
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrCoverLetterTooLong),
+		errors.Is(err, service.ErrApplicationNoteInvalid),
+		errors.Is(err, service.ErrStageCommentTooLong),
+		errors.Is(err, service.ErrInvalidApplicationStage):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+// parseApplicationPath разбирает ID проекта и отклика из пути запроса
+func parseApplicationPath(c *gin.Context) (uint, uint, bool) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return 0, 0, false
+	}
+	applicationID, err := strconv.ParseUint(c.Param("application_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid application ID"})
+		return 0, 0, false
+	}
+	return uint(projectID), uint(applicationID), true
+}
+
+// Apply godoc
+// @Summary Отклик на вакансию
+// @Description Откликается на открытую вакансию от имени текущего пользователя. Сопроводительное письмо — до 5000 символов. Отклик без вложения можно отправить в JSON; с вложением — в multipart/form-data, где поле cover_letter должно идти перед файлом attachment. Тип вложения определяется по содержимому: допускаются документы PDF, размер ограничен настройками сервера. На вакансию можно иметь только один рассматриваемый отклик
+// @Tags vacancies
+// @Accept json,mpfd
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID вакансии"
+// @Param request body ApplyRequest false "Отклик без вложения"
+// @Param cover_letter formData string false "Сопроводительное письмо"
+// @Param attachment formData file false "Вложение, например резюме"
+// @Success 201 {object} ApplicationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Router /vacancies/{id}/applications [post]
+func (h *ApplicationHandler) Apply(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	vacancyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid vacancy ID"})
+		return
+	}
+
+	if c.ContentType() != gin.MIMEMultipartPOSTForm {
+		var req ApplyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		h.apply(c, uint(vacancyID), principal.UserID, req.CoverLetter, nil)
+		return
+	}
+
+	// Вложение читается из тела запроса потоком, поэтому письмо должно прийти раньше него
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	var coverLetter string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			h.apply(c, uint(vacancyID), principal.UserID, coverLetter, nil)
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if part.FormName() == coverLetterFormField && part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxCoverLetterFieldSize+1))
+			part.Close()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+				return
+			}
+			if len(value) > maxCoverLetterFieldSize {
+				respondApplicationError(c, service.ErrCoverLetterTooLong)
+				return
+			}
+			coverLetter = string(value)
+			continue
+		}
+		if part.FormName() != attachmentFormField || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		h.apply(c, uint(vacancyID), principal.UserID, coverLetter, &service.ApplicationAttachment{Name: part.FileName(), Body: part})
+		part.Close()
+		return
+	}
+}
+
+func (h *ApplicationHandler) apply(c *gin.Context, vacancyID, userID uint, coverLetter string, attachment *service.ApplicationAttachment) {
+	application, err := h.applicationService.Apply(c.Request.Context(), vacancyID, userID, coverLetter, attachment)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newApplicationResponse(*application, myAttachmentURL))
+}
+
+// ListMyApplications godoc
+// @Summary Мои отклики
+// @Description Возвращает отклики текущего пользователя на вакансии, начиная с последних. Заметки команды проекта кандидату не показываются
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} ApplicationResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/applications [get]
+func (h *ApplicationHandler) ListMyApplications(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	applications, err := h.applicationService.ListForUser(principal.UserID)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newApplicationResponses(applications, myAttachmentURL))
+}
+
+// WithdrawApplication godoc
+// @Summary Отзыв отклика
+// @Description Отзывает рассматриваемый отклик текущего пользователя и удаляет его вложение. Принятый или отклоненный отклик отозвать нельзя
+// @Tags users
+// @Security ApiKeyAuth
+// @Param id path int true "ID отклика"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /users/me/applications/{id} [delete]
+func (h *ApplicationHandler) WithdrawApplication(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	applicationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid application ID"})
+		return
+	}
+
+	if err := h.applicationService.Withdraw(c.Request.Context(), uint(applicationID), principal.UserID); err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DownloadMyApplicationAttachment godoc
+// @Summary Скачивание вложения своего отклика
+// @Description Отдает файл, приложенный к отклику текущего пользователя. Вложения не раздаются по общим ссылкам; после отзыва отклика вложение удаляется
+// @Tags users
+// @Produce application/pdf
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID отклика"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /users/me/applications/{id}/attachment [get]
+func (h *ApplicationHandler) DownloadMyApplicationAttachment(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	applicationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid application ID"})
+		return
+	}
+
+	application, body, err := h.applicationService.OpenAttachment(c.Request.Context(), uint(applicationID), principal.UserID)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+	sendAttachment(c, application, body)
+}
+
+// DownloadProjectApplicationAttachment godoc
+// @Summary Скачивание вложения отклика на вакансию проекта
+// @Description Отдает файл, приложенный к отклику, команде проекта с правом управлять вакансиями
+// @Tags projects
+// @Produce application/pdf
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param application_id path int true "ID отклика"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/applications/{application_id}/attachment [get]
+func (h *ApplicationHandler) DownloadProjectApplicationAttachment(c *gin.Context) {
+	projectID, applicationID, ok := parseApplicationPath(c)
+	if !ok {
+		return
+	}
+
+	application, body, err := h.applicationService.OpenProjectAttachment(c.Request.Context(), projectID, applicationID)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+	sendAttachment(c, application, body)
+}
+
+// sendAttachment отдает вложение отклика как файл для скачивания. Ответ не кэшируется
+// посредниками, а браузер не пытается угадать его тип и не открывает его на странице.
+func sendAttachment(c *gin.Context, application *models.VacancyApplication, body io.ReadCloser) {
+	defer body.Close()
+	name := application.AttachmentName
+	if name == "" {
+		name = fmt.Sprintf("application-%d%s", application.ID, path.Ext(application.AttachmentKey))
+	}
+	c.DataFromReader(http.StatusOK, application.AttachmentSize, application.AttachmentContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": name}),
+		"Cache-Control":          "private, no-store",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// ListProjectApplications godoc
+// @Summary Отклики на вакансии проекта
+// @Description Возвращает отклики на вакансии проекта в порядке подачи
+// @Tags projects
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param vacancy_id query int false "ID вакансии"
+// @Param stage query string false "Этап отбора: new, screening, interview, offer, accepted, rejected или withdrawn"
+// @Success 200 {array} ApplicationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/applications [get]
+func (h *ApplicationHandler) ListProjectApplications(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+
+	filter := repository.ApplicationFilter{ProjectID: uint(projectID), Stage: c.Query("stage")}
+	if value := c.Query("vacancy_id"); value != "" {
+		vacancyID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid vacancy ID"})
+			return
+		}
+		filter.VacancyID = uint(vacancyID)
+	}
+
+	applications, err := h.applicationService.List(filter)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newApplicationResponses(applications, projectAttachmentURL))
+}
+
+// GetProjectApplication godoc
+// @Summary Отклик на вакансию проекта
+// @Description Возвращает отклик с заметками команды проекта и историей смены этапов
+// @Tags projects
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param application_id path int true "ID отклика"
+// @Success 200 {object} ApplicationDetailResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/applications/{application_id} [get]
+func (h *ApplicationHandler) GetProjectApplication(c *gin.Context) {
+	projectID, applicationID, ok := parseApplicationPath(c)
+	if !ok {
+		return
+	}
+
+	application, err := h.applicationService.Get(projectID, applicationID)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newApplicationDetailResponse(*application))
+}
+
+// MoveApplication godoc
+// @Summary Смена этапа отклика
+// @Description Переводит отклик на следующий этап отбора: new → screening → interview → offer → accepted. Этапы можно пропускать, отклонить отклик можно на любом этапе; принятый и отклоненный отклики больше не меняются. Принятый кандидат становится участником проекта с ролью из вакансии; с close_vacancy вакансия помечается заполненной
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param application_id path int true "ID отклика"
+// @Param request body MoveApplicationRequest true "Новый этап"
+// @Success 200 {object} MoveApplicationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /projects/{id}/applications/{application_id}/stage [post]
+func (h *ApplicationHandler) MoveApplication(c *gin.Context) {
+	projectID, applicationID, ok := parseApplicationPath(c)
+	if !ok {
+		return
+	}
+
+	var req MoveApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	principal, _ := auth.FromContext(c)
+	application, member, err := h.applicationService.Move(projectID, applicationID, principal.UserID, service.ApplicationStageInput{
+		Stage:        req.Stage,
+		Comment:      req.Comment,
+		CloseVacancy: req.CloseVacancy,
+	})
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	response := MoveApplicationResponse{ApplicationDetailResponse: newApplicationDetailResponse(*application)}
+	if member != nil {
+		response.Member = &ProjectMemberResponse{
+			ID:        member.User.ID,
+			Email:     member.User.Email,
+			FirstName: member.User.FirstName,
+			LastName:  member.User.LastName,
+			Role:      member.Role,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddApplicationNote godoc
+// @Summary Заметка к отклику
+// @Description Добавляет к отклику заметку команды проекта (до 2000 символов). Кандидат заметок не видит
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param application_id path int true "ID отклика"
+// @Param request body AddApplicationNoteRequest true "Заметка"
+// @Success 201 {object} ApplicationNoteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/applications/{application_id}/notes [post]
+func (h *ApplicationHandler) AddApplicationNote(c *gin.Context) {
+	projectID, applicationID, ok := parseApplicationPath(c)
+	if !ok {
+		return
+	}
+
+	var req AddApplicationNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	principal, _ := auth.FromContext(c)
+	note, err := h.applicationService.AddNote(projectID, applicationID, principal.UserID, req.Body)
+	if err != nil {
+		respondApplicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newApplicationNoteResponse(*note))
+}
@@ -1,32 +1,57 @@
 package handler
 
 import (
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
 	"github.com/levstremilov/shance-app/internal/service"
+	"gorm.io/gorm"
 )
 
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// ForbiddenResponse возвращается при отказе в доступе
+type ForbiddenResponse struct {
+	Error      string `json:"error" example:"forbidden"`
+	Reason     string `json:"reason" example:"project_role_not_allowed"`
+	Permission string `json:"permission,omitempty" example:"project:update"`
+}
+
 type AuthHandler struct {
-	authService service.AuthServiceInterface
+	authService          service.AuthServiceInterface
+	accountService       service.AccountServiceInterface
+	loginThrottleService service.LoginThrottleServiceInterface
+	accessTTL            time.Duration
 }
 
-func NewAuthHandler(authService service.AuthServiceInterface) *AuthHandler {
+func NewAuthHandler(authService service.AuthServiceInterface, accountService service.AccountServiceInterface, loginThrottleService service.LoginThrottleServiceInterface, accessTTL time.Duration) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:          authService,
+		accountService:       accountService,
+		loginThrottleService: loginThrottleService,
+		accessTTL:            accessTTL,
 	}
 }
 
+// setAccessTokenCookie сохраняет access токен в cookie на время его действия:
+// cookie с истекшим токеном браузер удаляет сам
+func setAccessTokenCookie(c *gin.Context, accessToken string, ttl time.Duration) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie("access_token", accessToken, int(ttl.Seconds()), "/", "", false, true)
+}
+
 type RegisterRequest struct {
 	FirstName string   `json:"first_name" binding:"required" example:"John"`
 	LastName  string   `json:"last_name" binding:"required" example:"Doe"`
 	Phone     string   `json:"phone" example:"+79001234567"`
-	Role      string   `json:"role" example:"user"`
 	Tags      []string `json:"tags" example:"tag1,tag2"`
 	Country   string   `json:"country" example:"Russia"`
 	City      string   `json:"city" example:"Moscow"`
@@ -48,6 +73,53 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// MFAChallengeResponse возвращается при входе, если требуется второй фактор
+type MFAChallengeResponse struct {
+	MFARequired        bool   `json:"mfa_required" example:"true"`
+	EnrollmentRequired bool   `json:"enrollment_required" example:"false"`
+	MFAToken           string `json:"mfa_token"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// MFAEnrollmentResponse возвращается после обязательной настройки второго фактора при входе
+type MFAEnrollmentResponse struct {
+	AccessToken   string   `json:"access_token"`
+	RefreshToken  string   `json:"refresh_token"`
+	RecoveryCodes []string `json:"recovery_codes" example:"abcde-fghij,klmno-pqrst"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6" example:"newpassword123"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ImpersonationResponse содержит access токен для работы от имени пользователя
+type ImpersonationResponse struct {
+	AccessToken string    `json:"access_token" example:"eyJhbGciOiJSUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	UserID      uint      `json:"user_id" example:"42"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type UnlockAccountRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
 // Register godoc
 // @Summary Регистрация нового пользователя
 // @Description Создает нового пользователя и возвращает refresh token
@@ -72,18 +144,20 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Phone:     req.Phone,
-		Role:      req.Role,
 		Tags:      req.Tags,
 		Country:   req.Country,
 		City:      req.City,
-	})
+	}, clientInfo(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("access_token", tokens.AccessToken, int((time.Hour * 24).Seconds()), "/", "", false, true)
+	if err := h.accountService.RequestEmailVerification(req.Email); err != nil {
+		log.Printf("failed to send verification email to %s: %v", req.Email, err)
+	}
+
+	setAccessTokenCookie(c, tokens.AccessToken, h.accessTTL)
 	c.JSON(http.StatusOK, TokenResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
 }
 
@@ -95,9 +169,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Produce json
 // @Param request body LoginRequest true "Данные для входа"
 // @Success 200 {object} TokenResponse
+// @Success 202 {object} MFAChallengeResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Header 429 {integer} Retry-After "Через сколько секунд можно повторить попытку"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -106,14 +183,25 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	tokens, err := h.authService.Login(req.Email, req.Password)
+	tokens, err := h.authService.Login(req.Email, req.Password, clientInfo(c))
+	var mfaErr *service.MFARequiredError
+	if errors.As(err, &mfaErr) {
+		c.JSON(http.StatusAccepted, newMFAChallengeResponse(mfaErr))
+		return
+	}
+	if respondThrottled(c, err) {
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid credentials"})
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid credentials"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("access_token", tokens.AccessToken, int((time.Hour * 24).Seconds()), "/", "", false, true)
+	setAccessTokenCookie(c, tokens.AccessToken, h.accessTTL)
 	c.JSON(http.StatusOK, TokenResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
 }
 
@@ -136,13 +224,390 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	tokens, err := h.authService.RefreshToken(req.RefreshToken)
+	tokens, err := h.authService.RefreshToken(req.RefreshToken, clientInfo(c))
+	if errors.Is(err, service.ErrRefreshTokenReused) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token has already been used, all sessions of this login were revoked"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
 		return
 	}
 
+	setAccessTokenCookie(c, tokens.AccessToken, h.accessTTL)
+	c.JSON(http.StatusOK, TokenResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
+}
+
+// Logout godoc
+// @Summary Выход из системы
+// @Description Отзывает refresh token и все токены, полученные из него при обновлении
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh token"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.authService.Logout(req.RefreshToken); err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid refresh token"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie("access_token", "", -1, "/", "", false, true)
+	c.Status(http.StatusNoContent)
+}
+
+// LogoutAll godoc
+// @Summary Выход со всех устройств
+// @Description Отзывает все refresh токены текущего пользователя
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	if err := h.authService.LogoutAll(principal.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("access_token", tokens.AccessToken, int((time.Hour * 24).Seconds()), "/", "", false, true)
+	c.SetCookie("access_token", "", -1, "/", "", false, true)
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword godoc
+// @Summary Запрос сброса пароля
+// @Description Отправляет на email ссылку для сброса пароля. Ответ не зависит от того, зарегистрирован ли адрес
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body EmailRequest true "Email пользователя"
+// @Success 202 "Accepted"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.accountService.RequestPasswordReset(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+// @Summary Сброс пароля
+// @Description Устанавливает новый пароль по токену из письма и завершает все сессии пользователя
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Токен и новый пароль"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.accountService.ResetPassword(req.Token, req.Password); err != nil {
+		if errors.Is(err, service.ErrInvalidOneTimeToken) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// VerifyEmail godoc
+// @Summary Подтверждение email
+// @Description Подтверждает адрес электронной почты по токену из письма
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Токен подтверждения"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/email/verify [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidOneTimeToken) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UnlockAccount godoc
+// @Summary Разблокировка входа
+// @Description Снимает блокировку входа, установленную после неудачных попыток ввода пароля, по токену из письма
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body UnlockAccountRequest true "Токен разблокировки"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/unlock [post]
+func (h *AuthHandler) UnlockAccount(c *gin.Context) {
+	var req UnlockAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.loginThrottleService.Unlock(req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidOneTimeToken) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification godoc
+// @Summary Повторная отправка письма подтверждения
+// @Description Отправляет новое письмо для подтверждения email. Ответ не зависит от того, зарегистрирован ли адрес
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body EmailRequest true "Email пользователя"
+// @Success 202 "Accepted"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.accountService.RequestEmailVerification(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// VerifyMFA godoc
+// @Summary Второй шаг входа
+// @Description Завершает вход кодом из приложения-аутентификатора или кодом восстановления. После нескольких неверных кодов MFA токен перестает действовать, а частые ошибки для пользователя ограничиваются (429 с Retry-After)
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body MFAVerifyRequest true "MFA токен и код"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tokens, err := h.authService.VerifyMFA(req.MFAToken, req.Code, clientInfo(c))
+	if respondThrottled(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	setAccessTokenCookie(c, tokens.AccessToken, h.accessTTL)
 	c.JSON(http.StatusOK, TokenResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
 }
+
+// BeginMFAEnrollment godoc
+// @Summary Настройка обязательного второго фактора при входе
+// @Description Генерирует секрет TOTP для пользователя, которому второй фактор обязателен, но еще не настроен
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body MFATokenRequest true "MFA токен"
+// @Success 200 {object} TOTPSetupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/mfa/enroll [post]
+func (h *AuthHandler) BeginMFAEnrollment(c *gin.Context) {
+	var req MFATokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	enrollment, err := h.authService.BeginMFAEnrollment(req.MFAToken)
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, TOTPSetupResponse{Secret: enrollment.Secret, ProvisioningURI: enrollment.ProvisioningURI})
+}
+
+// ConfirmMFAEnrollment godoc
+// @Summary Подтверждение обязательного второго фактора при входе
+// @Description Включает второй фактор кодом из приложения и завершает вход. Коды восстановления показываются один раз
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body MFAVerifyRequest true "MFA токен и код"
+// @Success 200 {object} MFAEnrollmentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/mfa/enroll/confirm [post]
+func (h *AuthHandler) ConfirmMFAEnrollment(c *gin.Context) {
+	var req MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tokens, recoveryCodes, err := h.authService.ConfirmMFAEnrollment(req.MFAToken, req.Code, clientInfo(c))
+	if respondThrottled(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	setAccessTokenCookie(c, tokens.AccessToken, h.accessTTL)
+	c.JSON(http.StatusOK, MFAEnrollmentResponse{
+		AccessToken:   tokens.AccessToken,
+		RefreshToken:  tokens.RefreshToken,
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// respondThrottled отвечает 429 с Retry-After, если попытки временно запрещены
+func respondThrottled(c *gin.Context, err error) bool {
+	var throttledErr *service.LoginThrottledError
+	if !errors.As(err, &throttledErr) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttledErr.RetryAfter.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, ErrorResponse{Error: err.Error()})
+	return true
+}
+
+func newMFAChallengeResponse(err *service.MFARequiredError) MFAChallengeResponse {
+	return MFAChallengeResponse{
+		MFARequired:        true,
+		EnrollmentRequired: err.EnrollmentRequired,
+		MFAToken:           err.ChallengeToken,
+	}
+}
+
+// Impersonate godoc
+// @Summary Вход от имени пользователя
+// @Description Выдает администратору access токен ограниченного срока действия для работы от имени пользователя. Токен передается в заголовке Authorization. Пока он используется, управление учетными данными пользователя недоступно, а каждый запрос записывается в журнал аудита
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} ImpersonationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/impersonate [post]
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	token, expiresAt, err := h.authService.Impersonate(principal.UserID, principal.SessionID, uint(id), clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrImpersonateSelf), errors.Is(err, service.ErrImpersonateAdmin):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ImpersonationResponse{
+		AccessToken: token,
+		UserID:      uint(id),
+		ExpiresAt:   expiresAt,
+	})
+}
+
+// clientInfo описывает устройство, с которого пришел запрос
+func clientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+// InvitationHandler представляет обработчик приглашений в проекты
+type InvitationHandler struct {
+	invitationService service.ProjectInvitationServiceInterface
+}
+
+// NewInvitationHandler создает новый экземпляр InvitationHandler
+func NewInvitationHandler(invitationService service.ProjectInvitationServiceInterface) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitationService}
+}
+
+// InviteMemberRequest представляет запрос на приглашение участника
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email" example:"user@example.com"`
+	Role  string `json:"role" binding:"required" example:"member" enums:"maintainer,member,viewer"`
+}
+
+// ProjectInvitationResponse представляет приглашение в проект
+type ProjectInvitationResponse struct {
+	ID          uint   `json:"id" example:"1"`
+	ProjectID   uint   `json:"project_id" example:"1"`
+	ProjectName string `json:"project_name,omitempty" example:"Shance"`
+	Email       string `json:"email" example:"user@example.com"`
+	Role        string `json:"role" example:"member" enums:"maintainer,member,viewer"`
+	Status      string `json:"status" example:"pending" enums:"pending,accepted,declined,revoked,expired"`
+	InviterID   uint   `json:"inviter_id" example:"1"`
+	// InviteeID заполнен, если у адреса есть аккаунт
+	InviteeID   *uint      `json:"invitee_id" example:"2"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newInvitationResponse(invitation models.ProjectInvitation) ProjectInvitationResponse {
+	return ProjectInvitationResponse{
+		ID:          invitation.ID,
+		ProjectID:   invitation.ProjectID,
+		ProjectName: invitation.Project.Name,
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		Status:      invitation.Status,
+		InviterID:   invitation.InviterID,
+		InviteeID:   invitation.InviteeID,
+		ExpiresAt:   invitation.ExpiresAt,
+		RespondedAt: invitation.RespondedAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+}
+
+func newInvitationResponses(invitations []models.ProjectInvitation) []ProjectInvitationResponse {
+	response := make([]ProjectInvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		response[i] = newInvitationResponse(invitation)
+	}
+	return response
+}
+
+func respondInvitationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, policy.ErrProjectNotFound),
+		errors.Is(err, service.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvitationExpired):
+		c.JSON(http.StatusGone, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvitationNotPending),
+		errors.Is(err, service.ErrAlreadyProjectMember):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvitationEmailMismatch):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrEmailNotVerified):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "confirm your email to see and answer invitations"})
+	case errors.Is(err, service.ErrInvalidInvitationToken),
+		errors.Is(err, service.ErrInvalidProjectRole):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+// InviteMember godoc
+// @Summary Приглашение участника в проект
+// @Description Отправляет на email ссылку-приглашение в проект. Адрес может еще не быть зарегистрирован: после регистрации с ним приглашение появится в списке приглашений пользователя. Участником приглашенный становится, только приняв приглашение. Повторное приглашение на тот же адрес меняет роль, продлевает срок действия и отправляет новую ссылку, прежняя ссылка перестает действовать
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param request body InviteMemberRequest true "Данные приглашения"
+// @Success 201 {object} ProjectInvitationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /projects/{id}/invite [post]
+func (h *InvitationHandler) InviteMember(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+
+	var req InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	principal, _ := auth.FromContext(c)
+	invitation, err := h.invitationService.Invite(uint(projectID), principal.UserID, req.Email, req.Role)
+	if err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newInvitationResponse(*invitation))
+}
+
+// ListProjectInvitations godoc
+// @Summary Приглашения в проект
+// @Description Возвращает все приглашения в проект, начиная с последних
+// @Tags projects
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Success 200 {array} ProjectInvitationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/invitations [get]
+func (h *InvitationHandler) ListProjectInvitations(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+
+	invitations, err := h.invitationService.List(uint(projectID))
+	if err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInvitationResponses(invitations))
+}
+
+// RevokeInvitation godoc
+// @Summary Отзыв приглашения
+// @Description Отзывает ожидающее приглашение в проект; ссылка из письма перестает действовать
+// @Tags projects
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param invitation_id path int true "ID приглашения"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Router /projects/{id}/invitations/{invitation_id} [delete]
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+	invitationID, err := strconv.ParseUint(c.Param("invitation_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid invitation ID"})
+		return
+	}
+
+	if err := h.invitationService.Revoke(uint(projectID), uint(invitationID)); err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptInvitation godoc
+// @Summary Принятие приглашения
+// @Description Принимает приглашение по токену из ссылки в письме и добавляет текущего пользователя в участники проекта с ролью из приглашения. Принять приглашение можно только из аккаунта с подтвержденным адресом, на который оно отправлено
+// @Tags invitations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param token path string true "Токен из ссылки-приглашения"
+// @Success 200 {object} ProjectMemberResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Router /invitations/{token}/accept [post]
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	member, err := h.invitationService.Accept(c.Param("token"), principal.UserID)
+	if err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ProjectMemberResponse{
+		ID:        member.User.ID,
+		Email:     member.User.Email,
+		FirstName: member.User.FirstName,
+		LastName:  member.User.LastName,
+		Role:      member.Role,
+	})
+}
+
+// DeclineInvitation godoc
+// @Summary Отказ от приглашения
+// @Description Отклоняет приглашение по токену из ссылки в письме. Отклонить приглашение можно только из аккаунта с подтвержденным адресом, на который оно отправлено
+// @Tags invitations
+// @Security ApiKeyAuth
+// @Param token path string true "Токен из ссылки-приглашения"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Router /invitations/{token}/decline [post]
+func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	if err := h.invitationService.Decline(c.Param("token"), principal.UserID); err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListMyInvitations godoc
+// @Summary Мои приглашения
+// @Description Возвращает действующие приглашения в проекты, отправленные на адрес текущего пользователя, в том числе до его регистрации. Требуется подтвержденный email. Принять приглашение можно по ссылке из письма
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} ProjectInvitationResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /users/me/invitations [get]
+func (h *InvitationHandler) ListMyInvitations(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	invitations, err := h.invitationService.ListForUser(principal.UserID)
+	if err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newInvitationResponses(invitations))
+}
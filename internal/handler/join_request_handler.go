@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+// JoinRequestHandler представляет обработчик заявок на вступление в проекты
+type JoinRequestHandler struct {
+	joinRequestService service.ProjectJoinRequestServiceInterface
+}
+
+// NewJoinRequestHandler создает новый экземпляр JoinRequestHandler
+func NewJoinRequestHandler(joinRequestService service.ProjectJoinRequestServiceInterface) *JoinRequestHandler {
+	return &JoinRequestHandler{joinRequestService: joinRequestService}
+}
+
+// SubmitJoinRequestRequest представляет заявку на вступление в проект
+type SubmitJoinRequestRequest struct {
+	Message string `json:"message" example:"Пишу на Go третий год, хочу помочь с бэкендом"`
+}
+
+// ApproveJoinRequestRequest представляет запрос на одобрение заявки
+type ApproveJoinRequestRequest struct {
+	// Role — роль нового участника, по умолчанию member
+	Role string `json:"role" example:"member" enums:"maintainer,member,viewer"`
+}
+
+// RejectJoinRequestRequest представляет запрос на отклонение заявки
+type RejectJoinRequestRequest struct {
+	Reason string `json:"reason" example:"Сейчас ищем только дизайнеров"`
+}
+
+// JoinRequestResponse представляет заявку на вступление в проект
+type JoinRequestResponse struct {
+	ID          uint          `json:"id" example:"1"`
+	ProjectID   uint          `json:"project_id" example:"1"`
+	ProjectName string        `json:"project_name,omitempty" example:"Shance"`
+	User        *UserResponse `json:"user,omitempty"`
+	Message     string        `json:"message" example:"Пишу на Go третий год, хочу помочь с бэкендом"`
+	Status      string        `json:"status" example:"pending" enums:"pending,approved,rejected,withdrawn"`
+	// Reason — причина отказа
+	Reason     string     `json:"reason,omitempty" example:"Сейчас ищем только дизайнеров"`
+	ReviewerID *uint      `json:"reviewer_id" example:"1"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newJoinRequestResponse(request models.ProjectJoinRequest) JoinRequestResponse {
+	response := JoinRequestResponse{
+		ID:          request.ID,
+		ProjectID:   request.ProjectID,
+		ProjectName: request.Project.Name,
+		Message:     request.Message,
+		Status:      request.Status,
+		Reason:      request.Reason,
+		ReviewerID:  request.ReviewerID,
+		ReviewedAt:  request.ReviewedAt,
+		CreatedAt:   request.CreatedAt,
+	}
+	if request.User.ID != 0 {
+		response.User = &UserResponse{
+			ID:        request.User.ID,
+			FirstName: request.User.FirstName,
+			LastName:  request.User.LastName,
+			Email:     request.User.Email,
+		}
+	}
+	return response
+}
+
+func newJoinRequestResponses(requests []models.ProjectJoinRequest) []JoinRequestResponse {
+	response := make([]JoinRequestResponse, len(requests))
+	for i, request := range requests {
+		response[i] = newJoinRequestResponse(request)
+	}
+	return response
+}
+
+func respondJoinRequestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, policy.ErrProjectNotFound),
+		errors.Is(err, service.ErrJoinRequestNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrJoinRequestsClosed):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrJoinRequestNotPending),
+		errors.Is(err, service.ErrJoinRequestExists),
+		errors.Is(err, service.ErrAlreadyProjectMember):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrJoinRequestMessageLong),
+		errors.Is(err, service.ErrJoinRequestReasonLong),
+		errors.Is(err, service.ErrInvalidJoinRequestStatus),
+		errors.Is(err, service.ErrInvalidProjectRole):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+// parseJoinRequestPath разбирает ID проекта и заявки из пути запроса
+func parseJoinRequestPath(c *gin.Context) (uint, uint, bool) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return 0, 0, false
+	}
+	requestID, err := strconv.ParseUint(c.Param("request_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid join request ID"})
+		return 0, 0, false
+	}
+	return uint(projectID), uint(requestID), true
+}
+
+// SubmitJoinRequest godoc
+// @Summary Заявка на вступление в проект
+// @Description Подает заявку текущего пользователя на вступление в проект с сопроводительным сообщением (до 2000 символов). Заявку рассматривают владелец или мейнтейнер проекта. Заявки не принимаются, если проект их отключил или завершен
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param request body SubmitJoinRequestRequest true "Заявка"
+// @Success 201 {object} JoinRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /projects/{id}/join-requests [post]
+func (h *JoinRequestHandler) SubmitJoinRequest(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+
+	var req SubmitJoinRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	request, err := h.joinRequestService.Submit(uint(projectID), principal.UserID, req.Message)
+	if err != nil {
+		respondJoinRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newJoinRequestResponse(*request))
+}
+
+// ListJoinRequests godoc
+// @Summary Заявки на вступление в проект
+// @Description Возвращает заявки на вступление в проект в порядке подачи. По умолчанию — только ожидающие рассмотрения
+// @Tags projects
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param status query string false "Состояние заявок: pending (по умолчанию), approved, rejected, withdrawn или all"
+// @Success 200 {array} JoinRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /projects/{id}/join-requests [get]
+func (h *JoinRequestHandler) ListJoinRequests(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+
+	status := c.DefaultQuery("status", models.JoinRequestStatusPending)
+	if status == "all" {
+		status = ""
+	}
+	requests, err := h.joinRequestService.List(uint(projectID), status)
+	if err != nil {
+		respondJoinRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newJoinRequestResponses(requests))
+}
+
+// ApproveJoinRequest godoc
+// @Summary Одобрение заявки на вступление
+// @Description Одобряет ожидающую заявку и добавляет ее автора в участники проекта с указанной ролью, по умолчанию member
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param request_id path int true "ID заявки"
+// @Param request body ApproveJoinRequestRequest false "Роль нового участника"
+// @Success 200 {object} ProjectMemberResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /projects/{id}/join-requests/{request_id}/approve [post]
+func (h *JoinRequestHandler) ApproveJoinRequest(c *gin.Context) {
+	projectID, requestID, ok := parseJoinRequestPath(c)
+	if !ok {
+		return
+	}
+
+	// Тело необязательно: без него участник получает роль member
+	var req ApproveJoinRequestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	principal, _ := auth.FromContext(c)
+	member, err := h.joinRequestService.Approve(projectID, requestID, principal.UserID, req.Role)
+	if err != nil {
+		respondJoinRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ProjectMemberResponse{
+		ID:        member.User.ID,
+		Email:     member.User.Email,
+		FirstName: member.User.FirstName,
+		LastName:  member.User.LastName,
+		Role:      member.Role,
+	})
+}
+
+// RejectJoinRequest godoc
+// @Summary Отклонение заявки на вступление
+// @Description Отклоняет ожидающую заявку. Причина отказа (до 1000 символов) видна автору заявки
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param request_id path int true "ID заявки"
+// @Param request body RejectJoinRequestRequest false "Причина отказа"
+// @Success 200 {object} JoinRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /projects/{id}/join-requests/{request_id}/reject [post]
+func (h *JoinRequestHandler) RejectJoinRequest(c *gin.Context) {
+	projectID, requestID, ok := parseJoinRequestPath(c)
+	if !ok {
+		return
+	}
+
+	var req RejectJoinRequestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	principal, _ := auth.FromContext(c)
+	request, err := h.joinRequestService.Reject(projectID, requestID, principal.UserID, req.Reason)
+	if err != nil {
+		respondJoinRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newJoinRequestResponse(*request))
+}
+
+// ListMyJoinRequests godoc
+// @Summary Мои заявки на вступление
+// @Description Возвращает заявки текущего пользователя на вступление в проекты во всех состояниях, начиная с последних
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} JoinRequestResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/me/join-requests [get]
+func (h *JoinRequestHandler) ListMyJoinRequests(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	requests, err := h.joinRequestService.ListForUser(principal.UserID)
+	if err != nil {
+		respondJoinRequestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newJoinRequestResponses(requests))
+}
+
+// WithdrawJoinRequest godoc
+// @Summary Отзыв заявки на вступление
+// @Description Отзывает ожидающую рассмотрения заявку текущего пользователя
+// @Tags users
+// @Security ApiKeyAuth
+// @Param id path int true "ID заявки"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /users/me/join-requests/{id} [delete]
+func (h *JoinRequestHandler) WithdrawJoinRequest(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	requestID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid join request ID"})
+		return
+	}
+
+	if err := h.joinRequestService.Withdraw(uint(requestID), principal.UserID); err != nil {
+		respondJoinRequestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
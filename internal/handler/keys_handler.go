@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/signing"
+)
+
+type KeysHandler struct {
+	keys *signing.KeySet
+}
+
+func NewKeysHandler(keys *signing.KeySet) *KeysHandler {
+	return &KeysHandler{
+		keys: keys,
+	}
+}
+
+// JWKS отдает открытые ключи подписи JWT (RFC 7517) по адресу /.well-known/jwks.json.
+// Маршрут находится вне /api/v1, поэтому не описан в Swagger.
+func (h *KeysHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
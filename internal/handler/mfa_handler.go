@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+type MFAHandler struct {
+	mfaService service.MFAServiceInterface
+}
+
+func NewMFAHandler(mfaService service.MFAServiceInterface) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// TOTPSetupResponse содержит секрет и URI для QR-кода приложения-аутентификатора
+type TOTPSetupResponse struct {
+	Secret          string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	ProvisioningURI string `json:"provisioning_uri" example:"otpauth://totp/Shance:user@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Shance"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"abcde-fghij,klmno-pqrst"`
+}
+
+// SetupTOTP godoc
+// @Summary Начало настройки двухфакторной аутентификации
+// @Description Генерирует секрет TOTP. Второй фактор включается после подтверждения кодом из приложения
+// @Tags mfa
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} TOTPSetupResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/mfa/totp [post]
+func (h *MFAHandler) SetupTOTP(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	enrollment, err := h.mfaService.BeginEnrollment(principal.UserID)
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, TOTPSetupResponse{Secret: enrollment.Secret, ProvisioningURI: enrollment.ProvisioningURI})
+}
+
+// ConfirmTOTP godoc
+// @Summary Включение двухфакторной аутентификации
+// @Description Подтверждает настройку кодом из приложения и возвращает коды восстановления. Коды показываются один раз
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body MFACodeRequest true "Код из приложения"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmEnrollment(principal.UserID, req.Code)
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMFA godoc
+// @Summary Отключение двухфакторной аутентификации
+// @Description Отключает второй фактор после проверки кода из приложения или кода восстановления
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body MFACodeRequest true "Код из приложения или код восстановления"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/mfa/disable [post]
+func (h *MFAHandler) DisableMFA(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.mfaService.Disable(principal.UserID, req.Code); err != nil {
+		c.JSON(mfaErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Новые коды восстановления
+// @Description Заменяет коды восстановления новыми. Старые коды перестают действовать
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body MFACodeRequest true "Код из приложения или код восстановления"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(principal.UserID, req.Code)
+	if err != nil {
+		c.JSON(mfaErrorStatus(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// mfaErrorStatus сопоставляет ошибки второго фактора с HTTP статусами
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFAChallenge), errors.Is(err, service.ErrInvalidMFACode), errors.Is(err, service.ErrMFAChallengeExhausted):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrMFAAlreadyEnabled), errors.Is(err, service.ErrMFANotEnabled), errors.Is(err, service.ErrMFAEnrollmentEmpty):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrMFARequiredForRole):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+// oauthStateCookie хранит state начатого входа, чтобы callback принимался
+// только в браузере, который начал вход
+const oauthStateCookie = "oauth_state"
+
+type OAuthHandler struct {
+	oauthService service.OAuthServiceInterface
+	accessTTL    time.Duration
+}
+
+func NewOAuthHandler(oauthService service.OAuthServiceInterface, accessTTL time.Duration) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+		accessTTL:    accessTTL,
+	}
+}
+
+type OAuthProvidersResponse struct {
+	Providers []string `json:"providers" example:"github,google"`
+}
+
+// ListProviders godoc
+// @Summary Список провайдеров входа
+// @Description Возвращает список настроенных внешних провайдеров входа
+// @Tags auth
+// @Produce json
+// @Success 200 {object} OAuthProvidersResponse
+// @Router /auth/oauth/providers [get]
+func (h *OAuthHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, OAuthProvidersResponse{Providers: h.oauthService.Providers()})
+}
+
+// Authorize godoc
+// @Summary Вход через внешнего провайдера
+// @Description Перенаправляет пользователя на страницу авторизации провайдера (authorization code + PKCE). State входа сохраняется в HttpOnly cookie oauth_state: callback принимается только в том же браузере
+// @Tags auth
+// @Param provider path string true "Имя провайдера" example(github)
+// @Param redirect_uri query string false "Адрес фронтенда, куда вернуть пользователя с токенами во фрагменте URL"
+// @Success 302 "Found"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/oauth/{provider} [get]
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	authorization, err := h.oauthService.AuthorizationURL(c.Param("provider"), c.Query("redirect_uri"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownOAuthProvider):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrInvalidRedirectURI):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	// Провайдер возвращает пользователя переходом верхнего уровня, поэтому Lax достаточно
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, authorization.State, int(time.Until(authorization.ExpiresAt).Seconds()), "/", "", false, true)
+	c.Redirect(http.StatusFound, authorization.URL)
+}
+
+// Callback godoc
+// @Summary Завершение входа через внешнего провайдера
+// @Description Обменивает код авторизации на токены. State из адреса должен совпасть с cookie oauth_state, установленной при начале входа. Находит пользователя по внешней учетной записи, привязывает ее к аккаунту с тем же подтвержденным email или регистрирует нового пользователя
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Имя провайдера" example(github)
+// @Param code query string true "Код авторизации"
+// @Param state query string true "Состояние"
+// @Success 200 {object} TokenResponse
+// @Success 202 {object} MFAChallengeResponse
+// @Success 302 "Found"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /auth/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	if providerErr := c.Query("error"); providerErr != "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "provider returned error: " + providerErr})
+		return
+	}
+
+	code, state := c.Query("code"), c.Query("state")
+	if code == "" || state == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "code and state are required"})
+		return
+	}
+
+	// state одноразовый: cookie больше не нужна при любом исходе
+	browserState, _ := c.Cookie(oauthStateCookie)
+	c.SetCookie(oauthStateCookie, "", -1, "/", "", false, true)
+
+	tokens, redirectURI, err := h.oauthService.Callback(c.Request.Context(), c.Param("provider"), state, browserState, code, clientInfo(c))
+	var mfaErr *service.MFARequiredError
+	if errors.As(err, &mfaErr) {
+		h.respondMFAChallenge(c, redirectURI, mfaErr)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownOAuthProvider):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrInvalidOAuthState), errors.Is(err, service.ErrOAuthEmailMissing):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrOAuthEmailNotVerified):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	setAccessTokenCookie(c, tokens.AccessToken, h.accessTTL)
+
+	if redirectURI != "" {
+		fragment := url.Values{}
+		fragment.Set("access_token", tokens.AccessToken)
+		fragment.Set("refresh_token", tokens.RefreshToken)
+		c.Redirect(http.StatusFound, redirectURI+"#"+fragment.Encode())
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenResponse{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken})
+}
+
+// respondMFAChallenge передает MFA токен фронтенду так же, как и обычные токены
+func (h *OAuthHandler) respondMFAChallenge(c *gin.Context, redirectURI string, mfaErr *service.MFARequiredError) {
+	if redirectURI != "" {
+		fragment := url.Values{}
+		fragment.Set("mfa_token", mfaErr.ChallengeToken)
+		fragment.Set("enrollment_required", strconv.FormatBool(mfaErr.EnrollmentRequired))
+		c.Redirect(http.StatusFound, redirectURI+"#"+fragment.Encode())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newMFAChallengeResponse(mfaErr))
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
+)
+
+const defaultSort = "-created_at"
+
+var errInvalidPage = errors.New("page and page_size must be positive integers")
+
+// parseListParams читает параметры страницы списка. Страница выбирается по номеру
+// (page, page_size) или, если передан параметр cursor, по курсору; пустой cursor
+// запрашивает первую страницу. sort задает поле сортировки, префикс "-" — обратный порядок.
+func parseListParams(c *gin.Context) (pagination.Params, error) {
+	params := pagination.Params{Page: 1, PageSize: pagination.DefaultPageSize}
+
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return params, errInvalidPage
+		}
+		params.Page = page
+	}
+	if value := c.Query("page_size"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			return params, errInvalidPage
+		}
+		params.PageSize = min(size, pagination.MaxPageSize)
+	}
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		params.Cursor = &cursor
+	}
+
+	sort := c.DefaultQuery("sort", defaultSort)
+	params.Sort = strings.TrimPrefix(sort, "-")
+	params.Desc = strings.HasPrefix(sort, "-")
+	return params, nil
+}
+
+// parseTimeQuery читает время в формате RFC 3339 или дату в формате 2006-01-02.
+// Для верхней границы диапазона дата включает весь день.
+func parseTimeQuery(c *gin.Context, name string, upperBound bool) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC 3339 time", name)
+	}
+	if upperBound {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// parseCreatedRange читает диапазон created_from и created_to
+func parseCreatedRange(c *gin.Context) (*time.Time, *time.Time, error) {
+	from, err := parseTimeQuery(c, "created_from", false)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseTimeQuery(c, "created_to", true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+// newListResponse оборачивает страницу в общий формат ответа со ссылками на соседние страницы
+func newListResponse(c *gin.Context, params pagination.Params, page pagination.Page, results interface{}) models.SwaggerListResponse {
+	response := models.SwaggerListResponse{
+		Count:   page.Total,
+		Results: results,
+	}
+
+	link := func(set map[string]string) string {
+		query := c.Request.URL.Query()
+		for key, value := range set {
+			query.Set(key, value)
+		}
+		return c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	size := strconv.Itoa(params.PageSize)
+	if params.UsesCursor() {
+		if page.HasMore {
+			response.Next = link(map[string]string{"cursor": page.NextCursor, "page_size": size})
+		}
+		return response
+	}
+
+	if page.HasMore {
+		response.Next = link(map[string]string{"page": strconv.Itoa(params.Page + 1), "page_size": size})
+	}
+	if params.Page > 1 {
+		response.Previous = link(map[string]string{"page": strconv.Itoa(params.Page - 1), "page_size": size})
+	}
+	return response
+}
+
+// respondListError отвечает на ошибку выборки страницы списка
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, pagination.ErrInvalidCursor) || errors.Is(err, pagination.ErrInvalidSort) || errors.Is(err, pagination.ErrCursorUnsupported) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+}
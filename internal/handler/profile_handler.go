@@ -0,0 +1,463 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+// Поля multipart форм с аватаром и файлом портфолио
+const (
+	avatarFormField         = "avatar"
+	portfolioFileFormField  = "file"
+	portfolioTitleFormField = "title"
+	// Название файла из формы читается не больше этого числа байт
+	maxTitleFieldSize = 1024
+)
+
+// ProfileHandler представляет обработчик для работы с аватаром и портфолио
+type ProfileHandler struct {
+	profileService service.ProfileServiceInterface
+}
+
+// NewProfileHandler создает новый экземпляр ProfileHandler
+func NewProfileHandler(profileService service.ProfileServiceInterface) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+// AvatarResponse представляет аватар пользователя
+type AvatarResponse struct {
+	// Blurhash — компактное размытое превью для показа до загрузки изображения
+	Blurhash string                 `json:"blurhash" example:"LEHV6nWB2yk8pyo0adR*.7kCMdnj"`
+	Variants []PhotoVariantResponse `json:"variants"`
+}
+
+// PortfolioLinkRequest представляет ссылку, добавляемую в портфолио
+type PortfolioLinkRequest struct {
+	URL   string `json:"url" binding:"required" example:"https://github.com/ivanov"`
+	Title string `json:"title" example:"Мои проекты на GitHub"`
+}
+
+// PortfolioLinkResponse представляет ссылку в портфолио
+type PortfolioLinkResponse struct {
+	ID    uint   `json:"id" example:"1"`
+	Kind  string `json:"kind" example:"github" enums:"github,behance,website"`
+	URL   string `json:"url" example:"https://github.com/ivanov"`
+	Title string `json:"title" example:"Мои проекты на GitHub"`
+}
+
+// PortfolioFileResponse представляет файл в портфолио
+type PortfolioFileResponse struct {
+	ID          uint      `json:"id" example:"1"`
+	URL         string    `json:"url" example:"http://localhost:8000/uploads/users/1/portfolio/8hPVJ7y2zK1mQ3xW9aLc0g.pdf"`
+	Name        string    `json:"name" example:"cv.pdf"`
+	Title       string    `json:"title" example:"Резюме"`
+	ContentType string    `json:"content_type" example:"application/pdf"`
+	Size        int64     `json:"size" example:"182113"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PortfolioResponse представляет портфолио пользователя
+type PortfolioResponse struct {
+	Links []PortfolioLinkResponse `json:"links"`
+	Files []PortfolioFileResponse `json:"files"`
+}
+
+// newAvatarResponse возвращает аватар со ссылками на варианты или nil, если аватара нет
+func newAvatarResponse(avatar *models.UserAvatar, profiles service.ProfileServiceInterface) (*AvatarResponse, error) {
+	if avatar == nil {
+		return nil, nil
+	}
+	variants := make([]PhotoVariantResponse, len(avatar.Variants))
+	for i, variant := range avatar.Variants {
+		url, err := profiles.URL(variant.Key)
+		if err != nil {
+			return nil, err
+		}
+		variants[i] = PhotoVariantResponse{
+			Name:   variant.Name,
+			Format: variant.Format,
+			URL:    url,
+			Width:  variant.Width,
+			Height: variant.Height,
+		}
+	}
+	return &AvatarResponse{Blurhash: avatar.Blurhash, Variants: variants}, nil
+}
+
+func newPortfolioLinkResponse(link models.PortfolioLink) PortfolioLinkResponse {
+	return PortfolioLinkResponse{
+		ID:    link.ID,
+		Kind:  link.Kind,
+		URL:   link.URL,
+		Title: link.Title,
+	}
+}
+
+func newPortfolioFileResponse(file models.PortfolioFile, profiles service.ProfileServiceInterface) (PortfolioFileResponse, error) {
+	url, err := profiles.URL(file.Key)
+	if err != nil {
+		return PortfolioFileResponse{}, err
+	}
+	return PortfolioFileResponse{
+		ID:          file.ID,
+		URL:         url,
+		Name:        file.Name,
+		Title:       file.Title,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+		CreatedAt:   file.CreatedAt,
+	}, nil
+}
+
+// newPortfolioResponse преобразует предварительно загруженное портфолио пользователя в ответ API
+func newPortfolioResponse(u models.User, profiles service.ProfileServiceInterface) (PortfolioResponse, error) {
+	response := PortfolioResponse{
+		Links: make([]PortfolioLinkResponse, len(u.PortfolioLinks)),
+		Files: make([]PortfolioFileResponse, len(u.PortfolioFiles)),
+	}
+	for i, link := range u.PortfolioLinks {
+		response.Links[i] = newPortfolioLinkResponse(link)
+	}
+	for i, file := range u.PortfolioFiles {
+		var err error
+		response.Files[i], err = newPortfolioFileResponse(file, profiles)
+		if err != nil {
+			return PortfolioResponse{}, err
+		}
+	}
+	return response, nil
+}
+
+// respondProfileError отвечает на ошибку работы с аватаром и портфолио
+func respondProfileError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAvatarNotFound),
+		errors.Is(err, service.ErrPortfolioLinkNotFound),
+		errors.Is(err, service.ErrPortfolioFileNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrAvatarTooLarge),
+		errors.Is(err, service.ErrPortfolioFileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrUnsupportedAvatarType),
+		errors.Is(err, service.ErrUnsupportedPortfolioFile):
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTooManyPortfolioLinks),
+		errors.Is(err, service.ErrTooManyPortfolioFiles):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidPortfolioLink),
+		errors.Is(err, service.ErrPortfolioTitleTooLong):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+// UploadAvatar godoc
+// @Summary Загрузка аватара
+// @Description Заменяет аватар текущего пользователя. Тип файла определяется по содержимому: допускаются JPEG, PNG и WebP. Изображение обрезается по центру до квадрата, метаданные EXIF удаляются, создаются варианты small, medium и large в WebP и JPEG (PNG для изображений с прозрачностью) и blurhash превью. Исходный файл не сохраняется
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param avatar formData file true "Файл изображения"
+// @Success 200 {object} AvatarResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/avatar [put]
+func (h *ProfileHandler) UploadAvatar(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	// Файл читается из тела запроса потоком, без сохранения формы во временные файлы
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "multipart/form-data body is required"})
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "avatar file is required"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if part.FormName() != avatarFormField || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		avatar, err := h.profileService.UploadAvatar(c.Request.Context(), principal.UserID, part)
+		part.Close()
+		if err != nil {
+			respondProfileError(c, err)
+			return
+		}
+
+		response, err := newAvatarResponse(avatar, h.profileService)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+}
+
+// DeleteAvatar godoc
+// @Summary Удаление аватара
+// @Description Удаляет аватар текущего пользователя и файлы всех его вариантов
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 204 "No Content"
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/avatar [delete]
+func (h *ProfileHandler) DeleteAvatar(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	if err := h.profileService.DeleteAvatar(c.Request.Context(), principal.UserID); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddPortfolioLink godoc
+// @Summary Добавление ссылки в портфолио
+// @Description Добавляет ссылку в портфолио текущего пользователя. Допускаются адреса http и https; вид ссылки (github, behance или website) определяется по адресу
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body PortfolioLinkRequest true "Ссылка"
+// @Success 201 {object} PortfolioLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/portfolio/links [post]
+func (h *ProfileHandler) AddPortfolioLink(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req PortfolioLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	link, err := h.profileService.AddLink(principal.UserID, req.URL, req.Title)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newPortfolioLinkResponse(*link))
+}
+
+// UpdatePortfolioLink godoc
+// @Summary Изменение ссылки в портфолио
+// @Description Заменяет адрес и название ссылки в портфолио текущего пользователя
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID ссылки"
+// @Param request body PortfolioLinkRequest true "Ссылка"
+// @Success 200 {object} PortfolioLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/portfolio/links/{id} [put]
+func (h *ProfileHandler) UpdatePortfolioLink(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	linkID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid link ID"})
+		return
+	}
+
+	var req PortfolioLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	link, err := h.profileService.UpdateLink(principal.UserID, uint(linkID), req.URL, req.Title)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newPortfolioLinkResponse(*link))
+}
+
+// DeletePortfolioLink godoc
+// @Summary Удаление ссылки из портфолио
+// @Description Удаляет ссылку из портфолио текущего пользователя
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID ссылки"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/portfolio/links/{id} [delete]
+func (h *ProfileHandler) DeletePortfolioLink(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	linkID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid link ID"})
+		return
+	}
+
+	if err := h.profileService.DeleteLink(principal.UserID, uint(linkID)); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UploadPortfolioFile godoc
+// @Summary Загрузка файла в портфолио
+// @Description Добавляет файл в портфолио текущего пользователя, например резюме. Тип файла определяется по содержимому: допускаются документы PDF. Размер файла и число файлов в портфолио ограничены настройками сервера. Поле title должно идти в форме перед файлом
+// @Tags users
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param title formData string false "Название файла"
+// @Param file formData file true "Файл"
+// @Success 201 {object} PortfolioFileResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/portfolio/files [post]
+func (h *ProfileHandler) UploadPortfolioFile(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	// Файл читается из тела запроса потоком, поэтому название должно прийти раньше него
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "multipart/form-data body is required"})
+		return
+	}
+	var title string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "file is required"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if part.FormName() == portfolioTitleFormField && part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxTitleFieldSize+1))
+			part.Close()
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+				return
+			}
+			if len(value) > maxTitleFieldSize {
+				respondProfileError(c, service.ErrPortfolioTitleTooLong)
+				return
+			}
+			title = string(value)
+			continue
+		}
+		if part.FormName() != portfolioFileFormField || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		file, err := h.profileService.UploadFile(c.Request.Context(), principal.UserID, part.FileName(), title, part)
+		part.Close()
+		if err != nil {
+			respondProfileError(c, err)
+			return
+		}
+
+		response, err := newPortfolioFileResponse(*file, h.profileService)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, response)
+		return
+	}
+}
+
+// DeletePortfolioFile godoc
+// @Summary Удаление файла из портфолио
+// @Description Удаляет файл из портфолио текущего пользователя и из хранилища
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID файла"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/portfolio/files/{id} [delete]
+func (h *ProfileHandler) DeletePortfolioFile(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+	fileID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid file ID"})
+		return
+	}
+
+	if err := h.profileService.DeleteFile(c.Request.Context(), principal.UserID, uint(fileID)); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -2,12 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/repository"
 	"github.com/levstremilov/shance-app/internal/service"
 	"gorm.io/gorm"
 )
@@ -15,6 +19,7 @@ import (
 // ProjectHandler представляет обработчик для работы с проектами
 type ProjectHandler struct {
 	projectService service.ProjectServiceInterface
+	photoService   service.ProjectPhotoServiceInterface
 }
 
 // CreateProjectRequest представляет запрос на создание проекта
@@ -25,6 +30,34 @@ type CreateProjectRequest struct {
 	Description string   `json:"description"`
 	Photo       string   `json:"photo"`
 	Tags        []string `json:"tags"`
+	// Status — начальный статус проекта, по умолчанию active
+	Status string `json:"status" example:"active" enums:"draft,recruiting,active"`
+	// Visibility — уровень доступа к проекту, по умолчанию public
+	Visibility string     `json:"visibility" example:"public" enums:"public,unlisted,private"`
+	StartDate  *time.Time `json:"start_date" example:"2024-04-01T00:00:00Z"`
+	EndDate    *time.Time `json:"end_date" example:"2024-09-30T00:00:00Z"`
+	// AcceptsJoinRequests — принимает ли проект заявки на вступление, по умолчанию true
+	AcceptsJoinRequests *bool `json:"accepts_join_requests" example:"true"`
+}
+
+// TransitionProjectRequest представляет запрос на смену статуса проекта.
+// Не переданные даты остаются прежними.
+type TransitionProjectRequest struct {
+	Status    string     `json:"status" binding:"required" example:"recruiting" enums:"draft,recruiting,active,paused,completed,archived"`
+	StartDate *time.Time `json:"start_date" example:"2024-04-01T00:00:00Z"`
+	EndDate   *time.Time `json:"end_date" example:"2024-09-30T00:00:00Z"`
+	Comment   string     `json:"comment" example:"Ищем дизайнера и двух разработчиков"`
+}
+
+// ProjectTransitionResponse представляет запись истории смены статуса проекта
+type ProjectTransitionResponse struct {
+	ID         uint   `json:"id" example:"1"`
+	FromStatus string `json:"from_status" example:"draft"`
+	ToStatus   string `json:"to_status" example:"recruiting"`
+	// UserID пустой, если статус сменила система
+	UserID    *uint     `json:"user_id" example:"1"`
+	Comment   string    `json:"comment" example:"Ищем дизайнера и двух разработчиков"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // UpdateProjectRequest представляет запрос на обновление проекта
@@ -35,20 +68,37 @@ type UpdateProjectRequest struct {
 	Description string   `json:"description" example:"Новое описание"`
 	Photo       []string `json:"photo" example:"['new_photo1.jpg', 'new_photo2.jpg']"`
 	Tags        []string `json:"tags" example:"new_tag1,new_tag2"`
+	// Visibility — новый уровень доступа к проекту; пустое значение оставляет прежний
+	Visibility string `json:"visibility" example:"unlisted" enums:"public,unlisted,private"`
+	// AcceptsJoinRequests включает или выключает прием заявок на вступление; пустое значение оставляет прежнее
+	AcceptsJoinRequests *bool `json:"accepts_join_requests" example:"false"`
 }
 
 // ProjectResponse представляет ответ API для проекта
 type ProjectResponse struct {
-	ID          uint         `json:"id" example:"1"`
-	Name        string       `json:"name" example:"Новый проект"`
-	Title       string       `json:"title" example:"Заголовок проекта"`
-	Subtitle    string       `json:"subtitle" example:"Подзаголовок проекта"`
-	Description string       `json:"description" example:"Описание проекта"`
-	Photo       []string     `json:"photo" example:"['photo1.jpg', 'photo2.jpg']"`
-	Tags        []string     `json:"tags" example:"tag1,tag2"`
-	UserID      uint         `json:"user_id" example:"1"`
-	User        UserResponse `json:"user"`
-	CreatedAt   time.Time    `json:"created_at" example:"2024-03-20T12:00:00Z"`
+	ID          uint            `json:"id" example:"1"`
+	Name        string          `json:"name" example:"Новый проект"`
+	Title       string          `json:"title" example:"Заголовок проекта"`
+	Subtitle    string          `json:"subtitle" example:"Подзаголовок проекта"`
+	Description string          `json:"description" example:"Описание проекта"`
+	Photo       []PhotoResponse `json:"photo"`
+	Tags        []string        `json:"tags" example:"tag1,tag2"`
+	Status      string          `json:"status" example:"active" enums:"draft,recruiting,active,paused,completed,archived"`
+	Visibility  string          `json:"visibility" example:"public" enums:"public,unlisted,private"`
+	// AcceptsJoinRequests — можно ли подать заявку на вступление в проект
+	AcceptsJoinRequests bool         `json:"accepts_join_requests" example:"true"`
+	StartDate           *time.Time   `json:"start_date,omitempty" example:"2024-04-01T00:00:00Z"`
+	EndDate             *time.Time   `json:"end_date,omitempty" example:"2024-09-30T00:00:00Z"`
+	UserID              uint         `json:"user_id" example:"1"`
+	User                UserResponse `json:"user"`
+	CreatedAt           time.Time    `json:"created_at" example:"2024-03-20T12:00:00Z"`
+}
+
+// ProjectSearchResponse — найденный проект с релевантностью и фрагментом текста
+type ProjectSearchResponse struct {
+	ProjectResponse
+	Rank     float64 `json:"rank" example:"0.6079271"`
+	Headline string  `json:"headline" example:"Платформа для поиска <mark>команды</mark> на pet-проекты"`
 }
 
 type UserResponse struct {
@@ -58,12 +108,6 @@ type UserResponse struct {
 	Email     string `json:"email" example:"ivan@example.com"`
 }
 
-// InviteMemberRequest представляет запрос на приглашение участника
-type InviteMemberRequest struct {
-	Email string `json:"email" binding:"required" example:"user@example.com"`
-	Role  string `json:"role" binding:"required" example:"member"`
-}
-
 // ProjectMemberResponse представляет ответ с информацией об участнике
 type ProjectMemberResponse struct {
 	ID        uint   `json:"id" example:"1"`
@@ -73,91 +117,190 @@ type ProjectMemberResponse struct {
 	Role      string `json:"role" example:"member"`
 }
 
+// newProjectResponses преобразует проекты с тегами, авторами и фотографиями в ответ API
+func newProjectResponses(projects []models.Project, photos service.ProjectPhotoServiceInterface) ([]ProjectResponse, error) {
+	response := make([]ProjectResponse, len(projects))
+	for i, p := range projects {
+		tags := make([]string, len(p.Tags))
+		for j, t := range p.Tags {
+			tags[j] = t.Name
+		}
+
+		// Загруженные фотографии с вариантами; у проектов без них — имена файлов,
+		// которые хранятся JSON строкой
+		photoArray, err := newPhotoResponses(p.Photos, photos)
+		if err != nil {
+			return nil, err
+		}
+		if len(p.Photos) == 0 && p.Photo != "" {
+			var names []string
+			if err := json.Unmarshal([]byte(p.Photo), &names); err != nil {
+				return nil, errors.New("error processing photo data")
+			}
+			for _, name := range names {
+				photoArray = append(photoArray, PhotoResponse{URL: name, Variants: []PhotoVariantResponse{}})
+			}
+		}
+
+		response[i] = ProjectResponse{
+			ID:                  p.ID,
+			Name:                p.Name,
+			Title:               p.Title,
+			Subtitle:            p.Subtitle,
+			Description:         p.Description,
+			Photo:               photoArray,
+			Tags:                tags,
+			Status:              p.Status,
+			Visibility:          p.Visibility,
+			AcceptsJoinRequests: p.AcceptsJoinRequests == nil || *p.AcceptsJoinRequests,
+			StartDate:           optionalTime(p.StartDate),
+			EndDate:             optionalTime(p.EndDate),
+			UserID:              p.UserID,
+			User: UserResponse{
+				ID:        p.User.ID,
+				FirstName: p.User.FirstName,
+				LastName:  p.User.LastName,
+				Email:     p.User.Email,
+			},
+			CreatedAt: p.CreatedAt,
+		}
+	}
+	return response, nil
+}
+
+// viewerID возвращает ID пользователя запроса или 0 для анонимного посетителя
+func viewerID(c *gin.Context) uint {
+	if principal, ok := auth.FromContext(c); ok {
+		return principal.UserID
+	}
+	return 0
+}
+
+// optionalTime возвращает nil для незаданной (нулевой) даты
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 // NewProjectHandler создает новый экземпляр ProjectHandler
-func NewProjectHandler(projectService service.ProjectServiceInterface) *ProjectHandler {
+func NewProjectHandler(projectService service.ProjectServiceInterface, photoService service.ProjectPhotoServiceInterface) *ProjectHandler {
 	return &ProjectHandler{
 		projectService: projectService,
+		photoService:   photoService,
 	}
 }
 
 // GetProjects godoc
-// @Summary Получение всех проектов
-// @Description Возвращает список всех проектов
+// @Summary Получение списка проектов
+// @Description Возвращает страницу проектов с фильтрами и сортировкой. Страница выбирается по номеру или по курсору. Черновики показываются только их автору при фильтре owner, равном ID текущего пользователя. Проекты по ссылке (unlisted) и закрытые проекты (private) показываются только их участникам
 // @Tags projects
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.SwaggerProject
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы, не больше 100" default(20)
+// @Param cursor query string false "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору"
+// @Param sort query string false "Поле сортировки: created_at, name, popularity; префикс - задает обратный порядок" default(-created_at)
+// @Param status query string false "Статус проекта" Enums(draft,recruiting,active,paused,completed,archived)
+// @Param tag query string false "Название тега"
+// @Param owner query int false "ID автора проекта"
+// @Param country query string false "Страна автора проекта"
+// @Param created_from query string false "Создан не раньше (YYYY-MM-DD или RFC 3339)"
+// @Param created_to query string false "Создан не позже (YYYY-MM-DD или RFC 3339)"
+// @Success 200 {object} models.SwaggerListResponse{results=[]ProjectResponse}
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /projects [get]
 func (h *ProjectHandler) GetProjects(c *gin.Context) {
-	projects, err := h.projectService.GetAll()
+	params, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	response := make([]ProjectResponse, len(projects))
-	for i, p := range projects {
-		tags := make([]string, len(p.Tags))
-		for j, t := range p.Tags {
-			tags[j] = t.Name
-		}
-
-		// Преобразуем строку обратно в массив для ответа
-		var photoArray []string
-		if err := json.Unmarshal([]byte(p.Photo), &photoArray); err != nil {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error processing photo data"})
+	filter := repository.ProjectFilter{
+		Status:       c.Query("status"),
+		Tag:          c.Query("tag"),
+		OwnerCountry: c.Query("country"),
+	}
+	if owner := c.Query("owner"); owner != "" {
+		ownerID, err := strconv.ParseUint(owner, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid owner ID"})
 			return
 		}
+		filter.OwnerID = uint(ownerID)
+	}
+	if filter.Status != "" && !service.IsProjectStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: service.ErrInvalidProjectStatus.Error()})
+		return
+	}
+	filter.ViewerID = viewerID(c)
+	// Автор видит в списке своих проектов и черновики
+	if filter.OwnerID != 0 && filter.OwnerID == filter.ViewerID {
+		filter.IncludeDrafts = true
+	}
+	filter.CreatedFrom, filter.CreatedTo, err = parseCreatedRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
 
-		// TODO делаешь метод project.toResponse(user)
-		// в нем пшишешь все что ниже - в методе GetOwnProjects переиспользуешь
-		response[i] = ProjectResponse{
-			ID:          p.ID,
-			Name:        p.Name,
-			Title:       p.Title,
-			Subtitle:    p.Subtitle,
-			Description: p.Description,
-			Photo:       photoArray,
-			Tags:        tags,
-			UserID:      p.UserID,
-			User: UserResponse{
-				ID:        p.User.ID,
-				FirstName: p.User.FirstName,
-				LastName:  p.User.LastName,
-				Email:     p.User.Email,
-			},
-			CreatedAt: p.CreatedAt,
-		}
+	projects, page, err := h.projectService.ListPage(filter, params)
+	if err != nil {
+		respondListError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	response, err := newProjectResponses(projects, h.photoService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newListResponse(c, params, page, response))
 }
 
 // GetProject godoc
 // @Summary Получение информации о проекте
-// @Description Возвращает информацию о проекте по его ID
+// @Description Возвращает информацию о проекте по его ID. Закрытый проект доступен только участникам, для остальных он не существует
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Param id path int true "ID проекта"
-// @Success 200 {object} models.SwaggerProject
+// @Success 200 {object} ProjectResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /projects/{id} [get]
 func (h *ProjectHandler) GetProject(c *gin.Context) {
-	id := c.Param("id")
-	project, err := h.projectService.GetByID(id)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+	project, err := h.projectService.GetVisible(uint(id), viewerID(c))
+	if err != nil {
+		if errors.Is(err, policy.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	response, err := newProjectResponses([]models.Project{*project}, h.photoService)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "project not found"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, project)
+
+	c.JSON(http.StatusOK, response[0])
 }
 
 // CreateProject godoc
 // @Summary Создание нового проекта
-// @Description Создает новый проект для текущего пользователя
+// @Description Создает новый проект для текущего пользователя. Проект создается в статусе draft, recruiting или active (по умолчанию active); черновики не показываются в списках и поиске. Даты проверяются так же, как при смене статуса. Уровень доступа: public — проект виден всем, unlisted — открывается по ссылке, но не показывается в списках и поиске, private — виден только участникам
 // @Tags projects
 // @Accept json
 // @Produce json
@@ -166,10 +309,11 @@ func (h *ProjectHandler) GetProject(c *gin.Context) {
 // @Success 201 {object} models.SwaggerProject
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /projects [post]
 func (h *ProjectHandler) CreateProject(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	principal, exists := auth.FromContext(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
 		return
@@ -182,12 +326,21 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	}
 
 	project := &models.Project{
-		Name:        req.Name,
-		Title:       req.Title,
-		Subtitle:    req.Subtitle,
-		Description: req.Description,
-		Photo:       req.Photo,
-		UserID:      userID.(uint),
+		Name:                req.Name,
+		Title:               req.Title,
+		Subtitle:            req.Subtitle,
+		Description:         req.Description,
+		Photo:               req.Photo,
+		Status:              req.Status,
+		Visibility:          req.Visibility,
+		UserID:              principal.UserID,
+		AcceptsJoinRequests: req.AcceptsJoinRequests,
+	}
+	if req.StartDate != nil {
+		project.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		project.EndDate = *req.EndDate
 	}
 
 	if len(req.Tags) > 0 {
@@ -199,6 +352,15 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	}
 
 	if err := h.projectService.Create(project); err != nil {
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "confirm your email before creating projects"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidProjectStatus) || errors.Is(err, service.ErrInvalidProjectDates) ||
+			errors.Is(err, service.ErrInvalidProjectVisibility) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -212,10 +374,12 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 // @Tags projects
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path int true "ID проекта"
 // @Param request body UpdateProjectRequest true "Данные проекта"
 // @Success 200 {object} ProjectResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /projects/{id} [put]
 func (h *ProjectHandler) UpdateProject(c *gin.Context) {
@@ -239,44 +403,37 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 	}
 
 	project := &models.Project{
-		Model:       gorm.Model{ID: uint(id)},
-		Name:        req.Name,
-		Title:       req.Title,
-		Subtitle:    req.Subtitle,
-		Description: req.Description,
-		Photo:       string(photoJSON),
+		Model:               gorm.Model{ID: uint(id)},
+		Name:                req.Name,
+		Title:               req.Title,
+		Subtitle:            req.Subtitle,
+		Description:         req.Description,
+		Photo:               string(photoJSON),
+		Visibility:          req.Visibility,
+		AcceptsJoinRequests: req.AcceptsJoinRequests,
 	}
 
 	if err := h.projectService.Update(project); err != nil {
+		if errors.Is(err, service.ErrInvalidProjectVisibility) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	tags := make([]string, len(project.Tags))
-	for i, t := range project.Tags {
-		tags[i] = t.Name
-	}
-
-	// Преобразуем строку обратно в массив для ответа
-	var photoArray []string
-	if err := json.Unmarshal([]byte(project.Photo), &photoArray); err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error processing photo data"})
+	updated, err := h.projectService.GetByID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
-
-	response := ProjectResponse{
-		ID:          project.ID,
-		Name:        project.Name,
-		Title:       project.Title,
-		Subtitle:    project.Subtitle,
-		Description: project.Description,
-		Photo:       photoArray,
-		Tags:        tags,
-		UserID:      project.UserID,
-		CreatedAt:   project.CreatedAt,
+	response, err := newProjectResponses([]models.Project{*updated}, h.photoService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, response[0])
 }
 
 // DeleteProject godoc
@@ -285,9 +442,11 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 // @Tags projects
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path int true "ID проекта"
 // @Success 204 "No Content"
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /projects/{id} [delete]
 func (h *ProjectHandler) DeleteProject(c *gin.Context) {
@@ -297,7 +456,7 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.Delete(string(id)); err != nil {
+	if err := h.projectService.Delete(strconv.FormatUint(id, 10)); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -306,13 +465,15 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 }
 
 // SearchProjects godoc
-// @Summary Поиск проектов по названию
-// @Description Возвращает список проектов, названия которых содержат поисковый запрос
+// @Summary Полнотекстовый поиск проектов
+// @Description Ищет проекты по названию, заголовку, подзаголовку, тегам и описанию с учетом словоформ русского и английского языков. Результаты упорядочены по релевантности, совпадения во фрагменте выделены тегом <mark>. Проекты по ссылке и закрытые проекты находятся только для их участников
 // @Tags projects
 // @Accept json
 // @Produce json
-// @Param q query string true "Поисковый запрос"
-// @Success 200 {array} ProjectResponse
+// @Param q query string true "Поисковый запрос: слова, фразы в двойных кавычках, -слово для исключения, or между вариантами"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы, не больше 100" default(20)
+// @Success 200 {object} models.SwaggerListResponse{results=[]ProjectSearchResponse}
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /projects/search [get]
@@ -323,46 +484,38 @@ func (h *ProjectHandler) SearchProjects(c *gin.Context) {
 		return
 	}
 
-	projects, err := h.projectService.Search(query)
+	params, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	response := make([]ProjectResponse, len(projects))
-	for i, p := range projects {
-		tags := make([]string, len(p.Tags))
-		for j, t := range p.Tags {
-			tags[j] = t.Name
-		}
+	results, page, err := h.projectService.Search(query, viewerID(c), params)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
 
-		// Преобразуем строку обратно в массив для ответа
-		var photoArray []string
-		if err := json.Unmarshal([]byte(p.Photo), &photoArray); err != nil {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error processing photo data"})
-			return
-		}
+	projects := make([]models.Project, len(results))
+	for i, r := range results {
+		projects[i] = r.Project
+	}
+	converted, err := newProjectResponses(projects, h.photoService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
 
-		response[i] = ProjectResponse{
-			ID:          p.ID,
-			Name:        p.Name,
-			Title:       p.Title,
-			Subtitle:    p.Subtitle,
-			Description: p.Description,
-			Photo:       photoArray,
-			Tags:        tags,
-			UserID:      p.UserID,
-			User: UserResponse{
-				ID:        p.User.ID,
-				FirstName: p.User.FirstName,
-				LastName:  p.User.LastName,
-				Email:     p.User.Email,
-			},
-			CreatedAt: p.CreatedAt,
+	response := make([]ProjectSearchResponse, len(results))
+	for i, r := range results {
+		response[i] = ProjectSearchResponse{
+			ProjectResponse: converted[i],
+			Rank:            r.Rank,
+			Headline:        r.Headline,
 		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, newListResponse(c, params, page, response))
 }
 
 // ListProjects godoc
@@ -383,112 +536,146 @@ func (h *ProjectHandler) ListProjects(c *gin.Context) {
 	c.JSON(http.StatusOK, projects)
 }
 
-// InviteMember godoc
-// @Summary Приглашение участника в проект
-// @Description Приглашает пользователя в проект по email
+// GetProjectMembers godoc
+// @Summary Получение списка участников проекта
+// @Description Возвращает список всех участников проекта
 // @Tags projects
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path int true "ID проекта"
-// @Param request body InviteMemberRequest true "Данные приглашения"
-// @Success 200 {object} ProjectMemberResponse
+// @Success 200 {array} ProjectMemberResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
-// @Failure 403 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
 // @Failure 404 {object} ErrorResponse
-// @Router /projects/{id}/invite [post]
-func (h *ProjectHandler) InviteMember(c *gin.Context) {
+// @Router /projects/{id}/members [get]
+func (h *ProjectHandler) GetProjectMembers(c *gin.Context) {
 	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
 		return
 	}
 
-	var req InviteMemberRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	// Получаем список участников
+	members, err := h.projectService.GetProjectMembers(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Проверяем права доступа
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
-		return
+	response := make([]ProjectMemberResponse, len(members))
+	for i, member := range members {
+		response[i] = ProjectMemberResponse{
+			ID:        member.User.ID,
+			Email:     member.User.Email,
+			FirstName: member.User.FirstName,
+			LastName:  member.User.LastName,
+			Role:      member.Role,
+		}
 	}
 
-	// Проверяем, является ли пользователь владельцем проекта
-	isOwner, err := h.projectService.IsProjectOwner(uint(projectID), userID.(uint))
+	c.JSON(http.StatusOK, response)
+}
+
+// TransitionProject godoc
+// @Summary Смена статуса проекта
+// @Description Переводит проект в новый статус и записывает переход в историю. Допустимые переходы: draft → recruiting, active, archived; recruiting → draft, active, paused, archived; active → recruiting, paused, completed, archived; paused → recruiting, active, completed, archived; completed → active, archived. Архивный проект не меняет статус. Активный и приостановленный проект уже начался и не закончился, завершенный — начался и закончился; недостающая дата начала при переходе в active и дата окончания при переходе в completed равны текущему времени. Доступно только автору проекта
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param request body TransitionProjectRequest true "Новый статус"
+// @Success 200 {object} ProjectResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /projects/{id}/transitions [post]
+func (h *ProjectHandler) TransitionProject(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
 		return
 	}
-	if !isOwner {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: "only project owner can invite members"})
+
+	var req TransitionProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	// Приглашаем пользователя
-	member, err := h.projectService.InviteMember(uint(projectID), req.Email, req.Role)
+	principal, _ := auth.FromContext(c)
+	project, err := h.projectService.Transition(uint(projectID), principal.UserID, service.ProjectTransitionInput{
+		Status:    req.Status,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Comment:   req.Comment,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		switch {
+		case errors.Is(err, policy.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrTransitionNotAllowed), errors.Is(err, service.ErrProjectStatusChanged):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, service.ErrInvalidProjectStatus),
+			errors.Is(err, service.ErrInvalidProjectDates),
+			errors.Is(err, service.ErrTransitionCommentLong):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
 		return
 	}
 
-	response := ProjectMemberResponse{
-		ID:        member.User.ID,
-		Email:     member.User.Email,
-		FirstName: member.User.FirstName,
-		LastName:  member.User.LastName,
-		Role:      member.Role,
+	response, err := newProjectResponses([]models.Project{*project}, h.photoService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
 	}
-
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, response[0])
 }
 
-// GetProjectMembers godoc
-// @Summary Получение списка участников проекта
-// @Description Возвращает список всех участников проекта
+// ListTransitions godoc
+// @Summary История статусов проекта
+// @Description Возвращает переходы между статусами проекта, начиная с последних
 // @Tags projects
-// @Accept json
 // @Produce json
 // @Param id path int true "ID проекта"
-// @Success 200 {array} ProjectMemberResponse
+// @Success 200 {array} ProjectTransitionResponse
 // @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
-// @Router /projects/{id}/members [get]
-func (h *ProjectHandler) GetProjectMembers(c *gin.Context) {
+// @Failure 500 {object} ErrorResponse
+// @Router /projects/{id}/transitions [get]
+func (h *ProjectHandler) ListTransitions(c *gin.Context) {
 	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
 		return
 	}
 
-	// Проверяем аутентификацию
-	_, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
-		return
-	}
-
-	// Получаем список участников
-	members, err := h.projectService.GetProjectMembers(uint(projectID))
+	transitions, err := h.projectService.ListTransitions(uint(projectID), viewerID(c))
 	if err != nil {
+		if errors.Is(err, policy.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	response := make([]ProjectMemberResponse, len(members))
-	for i, member := range members {
-		response[i] = ProjectMemberResponse{
-			ID:        member.User.ID,
-			Email:     member.User.Email,
-			FirstName: member.User.FirstName,
-			LastName:  member.User.LastName,
-			Role:      member.Role,
+	response := make([]ProjectTransitionResponse, len(transitions))
+	for i, t := range transitions {
+		response[i] = ProjectTransitionResponse{
+			ID:         t.ID,
+			FromStatus: t.FromStatus,
+			ToStatus:   t.ToStatus,
+			UserID:     t.UserID,
+			Comment:    t.Comment,
+			CreatedAt:  t.CreatedAt,
 		}
 	}
-
 	c.JSON(http.StatusOK, response)
 }
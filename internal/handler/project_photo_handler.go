@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+// photoFormField — поле multipart формы с файлом фотографии
+const photoFormField = "photo"
+
+// PhotoResponse представляет загруженную фотографию проекта
+type PhotoResponse struct {
+	ID          uint   `json:"id" example:"1"`
+	URL         string `json:"url" example:"http://localhost:8000/uploads/projects/1/8hPVJ7y2zK1mQ3xW9aLc0g.jpg"`
+	ContentType string `json:"content_type" example:"image/jpeg"`
+	Size        int64  `json:"size" example:"482113"`
+	Width       int    `json:"width" example:"1920"`
+	Height      int    `json:"height" example:"1080"`
+	// Blurhash — компактное размытое превью для показа до загрузки изображения
+	Blurhash  string                 `json:"blurhash" example:"LEHV6nWB2yk8pyo0adR*.7kCMdnj"`
+	Variants  []PhotoVariantResponse `json:"variants"`
+	Position  int                    `json:"position" example:"0"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// PhotoVariantResponse представляет уменьшенный вариант фотографии
+type PhotoVariantResponse struct {
+	Name   string `json:"name" example:"thumb" enums:"thumb,medium,large"`
+	Format string `json:"format" example:"webp" enums:"webp,jpeg,png"`
+	URL    string `json:"url" example:"http://localhost:8000/uploads/projects/1/8hPVJ7y2zK1mQ3xW9aLc0g_thumb.webp"`
+	Width  int    `json:"width" example:"320"`
+	Height int    `json:"height" example:"180"`
+}
+
+// ReorderPhotosRequest задает новый порядок фотографий проекта
+type ReorderPhotosRequest struct {
+	PhotoIDs []uint `json:"photo_ids" binding:"required" example:"3,1,2"`
+}
+
+// newPhotoResponses преобразует фотографии проекта и их варианты в ответ API со ссылками
+func newPhotoResponses(photos []models.ProjectPhoto, photoService service.ProjectPhotoServiceInterface) ([]PhotoResponse, error) {
+	response := make([]PhotoResponse, len(photos))
+	for i, photo := range photos {
+		url, err := photoService.URL(photo.Key)
+		if err != nil {
+			return nil, err
+		}
+		variants := make([]PhotoVariantResponse, len(photo.Variants))
+		for j, variant := range photo.Variants {
+			variantURL, err := photoService.URL(variant.Key)
+			if err != nil {
+				return nil, err
+			}
+			variants[j] = PhotoVariantResponse{
+				Name:   variant.Name,
+				Format: variant.Format,
+				URL:    variantURL,
+				Width:  variant.Width,
+				Height: variant.Height,
+			}
+		}
+		response[i] = PhotoResponse{
+			ID:          photo.ID,
+			URL:         url,
+			ContentType: photo.ContentType,
+			Size:        photo.Size,
+			Width:       photo.Width,
+			Height:      photo.Height,
+			Blurhash:    photo.Blurhash,
+			Variants:    variants,
+			Position:    photo.Position,
+			CreatedAt:   photo.CreatedAt,
+		}
+	}
+	return response, nil
+}
+
+// respondPhotoError отвечает на ошибку работы с фотографиями проекта
+func respondPhotoError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, policy.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "project not found"})
+	case errors.Is(err, service.ErrPhotoNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrPhotoTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrUnsupportedPhotoType):
+		c.JSON(http.StatusUnsupportedMediaType, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTooManyPhotos):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrInvalidPhotoOrder):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+// ListPhotos godoc
+// @Summary Фотографии проекта
+// @Description Возвращает фотографии проекта в заданном порядке со ссылками для скачивания
+// @Tags projects
+// @Produce json
+// @Param id path int true "ID проекта"
+// @Success 200 {array} PhotoResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /projects/{id}/photos [get]
+func (h *ProjectHandler) ListPhotos(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+
+	photos, err := h.photoService.List(uint(projectID), viewerID(c))
+	if err != nil {
+		respondPhotoError(c, err)
+		return
+	}
+
+	response, err := newPhotoResponses(photos, h.photoService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UploadPhoto godoc
+// @Summary Загрузка фотографии проекта
+// @Description Добавляет фотографию в конец списка фотографий проекта. Тип файла определяется по содержимому: допускаются JPEG, PNG и WebP. Размер файла и число фотографий в проекте ограничены настройками сервера. Метаданные EXIF удаляются, изображение поворачивается по ориентации из EXIF, для него создаются уменьшенные варианты thumb, medium и large в WebP и JPEG (PNG для изображений с прозрачностью) и blurhash превью
+// @Tags projects
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param photo formData file true "Файл фотографии"
+// @Success 201 {object} PhotoResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /projects/{id}/photos [post]
+func (h *ProjectHandler) UploadPhoto(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+
+	// Файл читается из тела запроса потоком, без сохранения формы во временные файлы
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "multipart/form-data body is required"})
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "photo file is required"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if part.FormName() != photoFormField || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		photo, err := h.photoService.Upload(c.Request.Context(), uint(projectID), part)
+		part.Close()
+		if err != nil {
+			respondPhotoError(c, err)
+			return
+		}
+
+		response, err := newPhotoResponses([]models.ProjectPhoto{*photo}, h.photoService)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, response[0])
+		return
+	}
+}
+
+// ReorderPhotos godoc
+// @Summary Порядок фотографий проекта
+// @Description Задает порядок фотографий проекта. Список должен содержать каждую фотографию проекта ровно один раз
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param request body ReorderPhotosRequest true "ID фотографий в новом порядке"
+// @Success 200 {array} PhotoResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /projects/{id}/photos/order [put]
+func (h *ProjectHandler) ReorderPhotos(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+
+	var req ReorderPhotosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	photos, err := h.photoService.Reorder(uint(projectID), req.PhotoIDs)
+	if err != nil {
+		respondPhotoError(c, err)
+		return
+	}
+
+	response, err := newPhotoResponses(photos, h.photoService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeletePhoto godoc
+// @Summary Удаление фотографии проекта
+// @Description Удаляет фотографию проекта и файлы всех ее вариантов из хранилища
+// @Tags projects
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID проекта"
+// @Param photo_id path int true "ID фотографии"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /projects/{id}/photos/{photo_id} [delete]
+func (h *ProjectHandler) DeletePhoto(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid project ID"})
+		return
+	}
+	photoID, err := strconv.ParseUint(c.Param("photo_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid photo ID"})
+		return
+	}
+
+	if err := h.photoService.Delete(c.Request.Context(), uint(projectID), uint(photoID)); err != nil {
+		respondPhotoError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -1,29 +1,129 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
 	"github.com/levstremilov/shance-app/internal/service"
 )
 
+// VacancyTermsRequest описывает условия работы по вакансии; пустые значения и нули — условие не указано
+type VacancyTermsRequest struct {
+	Seniority       string `json:"seniority" example:"middle" enums:"junior,middle,senior,lead"`
+	HoursPerWeek    int    `json:"hours_per_week" example:"20"`
+	EmploymentType  string `json:"employment_type" example:"paid" enums:"volunteer,paid,equity,internship"`
+	RemotePolicy    string `json:"remote_policy" example:"hybrid" enums:"remote,hybrid,onsite"`
+	Location        string `json:"location" example:"Москва"` // обязательно для hybrid и onsite
+	CompensationMin int64  `json:"compensation_min" example:"100000"`
+	CompensationMax int64  `json:"compensation_max" example:"150000"`
+	Currency        string `json:"currency" example:"RUB"` // обязательна, если указано вознаграждение
+	Openings        int    `json:"openings" example:"2"`   // по умолчанию 1
+}
+
 // CreateProjectVacancyRequest описывает тело запроса на создание вакансии
 type CreateProjectVacancyRequest struct {
 	Title        string `json:"title" binding:"required"`
 	Description  string `json:"description" binding:"required"`
 	Technologies []uint `json:"technologies" binding:"required"` // id технологий
+	// Role — роль в проекте, которую получает принятый кандидат, по умолчанию member
+	Role string `json:"role" example:"member" enums:"maintainer,member,viewer"`
+	// ExpiresAt — когда вакансия закроется автоматически; без значения вакансия бессрочная
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-06-01T00:00:00Z"`
+	VacancyTermsRequest
+}
+
+// ReplaceVacancyRequest описывает вакансию целиком; не переданные срок и условия работы снимаются
+type ReplaceVacancyRequest struct {
+	Title        string     `json:"title" binding:"required"`
+	Description  string     `json:"description" binding:"required"`
+	Technologies []uint     `json:"technologies" binding:"required"` // id технологий
+	Role         string     `json:"role" binding:"required" example:"member" enums:"maintainer,member,viewer"`
+	ExpiresAt    *time.Time `json:"expires_at" example:"2024-06-01T00:00:00Z"`
+	VacancyTermsRequest
+}
+
+// UpdateVacancyRequest описывает изменения вакансии; не переданные поля не меняются
+type UpdateVacancyRequest struct {
+	Title        *string    `json:"title" binding:"omitempty,min=1"`
+	Description  *string    `json:"description" binding:"omitempty,min=1"`
+	Technologies *[]uint    `json:"technologies"` // id технологий
+	Role         *string    `json:"role" example:"member" enums:"maintainer,member,viewer"`
+	ExpiresAt    *time.Time `json:"expires_at" example:"2024-06-01T00:00:00Z"`
+	// Status закрывает вакансию или открывает ее снова
+	Status *string `json:"status" example:"closed" enums:"open,closed,filled"`
+
+	// Условия работы; пустая строка или ноль снимает условие
+	Seniority       *string `json:"seniority" example:"middle" enums:"junior,middle,senior,lead"`
+	HoursPerWeek    *int    `json:"hours_per_week" example:"20"`
+	EmploymentType  *string `json:"employment_type" example:"paid" enums:"volunteer,paid,equity,internship"`
+	RemotePolicy    *string `json:"remote_policy" example:"hybrid" enums:"remote,hybrid,onsite"`
+	Location        *string `json:"location" example:"Москва"`
+	CompensationMin *int64  `json:"compensation_min" example:"100000"`
+	CompensationMax *int64  `json:"compensation_max" example:"150000"`
+	Currency        *string `json:"currency" example:"RUB"`
+	Openings        *int    `json:"openings" example:"2"`
 }
 
 type VacancyResponse struct {
-	ID              uint      `json:"id"`
-	ProjectID       uint      `json:"project_id"`
-	Title           string    `json:"title"`
-	Description     string    `json:"description"`
-	TechnologyNames []string  `json:"technology_names"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              uint       `json:"id"`
+	ProjectID       uint       `json:"project_id"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	Role            string     `json:"role" example:"member" enums:"maintainer,member,viewer"`
+	Status          string     `json:"status" example:"open" enums:"open,closed,filled"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" example:"2024-06-01T00:00:00Z"`
+	Seniority       string     `json:"seniority,omitempty" example:"middle" enums:"junior,middle,senior,lead"`
+	HoursPerWeek    int        `json:"hours_per_week,omitempty" example:"20"`
+	EmploymentType  string     `json:"employment_type,omitempty" example:"paid" enums:"volunteer,paid,equity,internship"`
+	RemotePolicy    string     `json:"remote_policy,omitempty" example:"hybrid" enums:"remote,hybrid,onsite"`
+	Location        string     `json:"location,omitempty" example:"Москва"`
+	CompensationMin int64      `json:"compensation_min,omitempty" example:"100000"`
+	CompensationMax int64      `json:"compensation_max,omitempty" example:"150000"`
+	Currency        string     `json:"currency,omitempty" example:"RUB"`
+	Openings        int        `json:"openings" example:"2"`
+	TechnologyNames []string   `json:"technology_names"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// VacancySearchResponse — найденная вакансия с релевантностью и фрагментом описания
+type VacancySearchResponse struct {
+	VacancyResponse
+	Rank     float64 `json:"rank" example:"0.6079271"`
+	Headline string  `json:"headline" example:"Ищем <mark>разработчика</mark> на Go"`
+}
+
+func newVacancyResponse(v models.ProjectVacancy) VacancyResponse {
+	var techNames []string
+	for _, t := range v.Technologies {
+		techNames = append(techNames, t.Name)
+	}
+	return VacancyResponse{
+		ID:              v.ID,
+		ProjectID:       v.ProjectID,
+		Title:           v.Title,
+		Description:     v.Description,
+		Role:            v.Role,
+		Status:          v.Status,
+		ExpiresAt:       v.ExpiresAt,
+		Seniority:       v.Seniority,
+		HoursPerWeek:    v.HoursPerWeek,
+		EmploymentType:  v.EmploymentType,
+		RemotePolicy:    v.RemotePolicy,
+		Location:        v.Location,
+		CompensationMin: v.CompensationMin,
+		CompensationMax: v.CompensationMax,
+		Currency:        v.Currency,
+		Openings:        v.Openings,
+		TechnologyNames: techNames,
+		CreatedAt:       v.CreatedAt,
+	}
 }
 
 type CreateTechnologyRequest struct {
@@ -44,10 +144,12 @@ func NewProjectVacancyHandler(service *service.ProjectVacancyService) *ProjectVa
 // @Tags vacancies
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path int true "ID проекта"
 // @Param request body CreateProjectVacancyRequest true "Данные вакансии"
 // @Success 201 {object} models.SwaggerProjectVacancy
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /projects/{id}/vacancy [post]
 func (h *ProjectVacancyHandler) CreateProjectVacancy(c *gin.Context) {
@@ -63,23 +165,33 @@ func (h *ProjectVacancyHandler) CreateProjectVacancy(c *gin.Context) {
 		return
 	}
 
-	var technologies []models.Technology
-	if len(req.Technologies) > 0 {
-		if err := h.service.DB().Find(&technologies, req.Technologies).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+	technologies, err := h.findTechnologies(req.Technologies)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
 	vacancy := models.ProjectVacancy{
 		ProjectID:    uint(projectID),
 		Title:        req.Title,
 		Description:  req.Description,
+		Role:         req.Role,
+		ExpiresAt:    req.ExpiresAt,
 		Technologies: technologies,
+
+		Seniority:       req.Seniority,
+		HoursPerWeek:    req.HoursPerWeek,
+		EmploymentType:  req.EmploymentType,
+		RemotePolicy:    req.RemotePolicy,
+		Location:        req.Location,
+		CompensationMin: req.CompensationMin,
+		CompensationMax: req.CompensationMax,
+		Currency:        req.Currency,
+		Openings:        req.Openings,
 	}
 
 	if err := h.service.Create(&vacancy); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondVacancyError(c, err)
 		return
 	}
 
@@ -88,12 +200,28 @@ func (h *ProjectVacancyHandler) CreateProjectVacancy(c *gin.Context) {
 
 // GetProjectVacancies godoc
 // @Summary Получить вакансии проекта
-// @Description Получает список вакансий, привязанных к проекту
+// @Description Получает страницу вакансий, привязанных к проекту. По умолчанию показываются только открытые вакансии, срок которых не истек. Вакансии закрытого проекта видят только его участники
 // @Tags vacancies
 // @Accept json
 // @Produce json
 // @Param id path int true "ID проекта"
-// @Success 200 {array} models.SwaggerProjectVacancy
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы, не больше 100" default(20)
+// @Param cursor query string false "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору"
+// @Param sort query string false "Поле сортировки: created_at, name; префикс - задает обратный порядок" default(-created_at)
+// @Param status query string false "Состояние вакансии; all — вакансии во всех состояниях" Enums(open,closed,filled,all) default(open)
+// @Param technology query string false "Название технологии"
+// @Param seniority query string false "Уровень квалификации" Enums(junior,middle,senior,lead)
+// @Param employment_type query string false "Вид занятости" Enums(volunteer,paid,equity,internship)
+// @Param remote_policy query string false "Формат работы" Enums(remote,hybrid,onsite)
+// @Param location query string false "Часть названия места работы"
+// @Param max_hours_per_week query int false "Занятость не больше стольких часов в неделю; вакансии без указанной занятости не подходят"
+// @Param min_compensation query int false "Вознаграждение может достичь этой суммы; требует currency"
+// @Param currency query string false "Код валюты вознаграждения ISO 4217" example(RUB)
+// @Param min_openings query int false "Не меньше стольких мест"
+// @Param created_from query string false "Создана не раньше (YYYY-MM-DD или RFC 3339)"
+// @Param created_to query string false "Создана не позже (YYYY-MM-DD или RFC 3339)"
+// @Success 200 {object} models.SwaggerListResponse{results=[]VacancyResponse}
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /projects/{id}/vacancies [get]
@@ -104,29 +232,407 @@ func (h *ProjectVacancyHandler) GetProjectVacancies(c *gin.Context) {
 		return
 	}
 
-	vacancies, err := h.service.GetAll(uint(projectID))
+	// Вакансии черновика и проекта по ссылке доступны на странице проекта, но не в общем списке
+	h.listVacancies(c, uint(projectID), true)
+}
+
+// ListVacancies godoc
+// @Summary Список вакансий
+// @Description Получает страницу вакансий всех проектов, кроме черновиков и проектов по ссылке. По умолчанию показываются только открытые вакансии, срок которых не истек. Вакансии закрытых проектов видят только их участники
+// @Tags vacancies
+// @Accept json
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы, не больше 100" default(20)
+// @Param cursor query string false "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору"
+// @Param sort query string false "Поле сортировки: created_at, name; префикс - задает обратный порядок" default(-created_at)
+// @Param project query int false "ID проекта"
+// @Param status query string false "Состояние вакансии; all — вакансии во всех состояниях" Enums(open,closed,filled,all) default(open)
+// @Param technology query string false "Название технологии"
+// @Param seniority query string false "Уровень квалификации" Enums(junior,middle,senior,lead)
+// @Param employment_type query string false "Вид занятости" Enums(volunteer,paid,equity,internship)
+// @Param remote_policy query string false "Формат работы" Enums(remote,hybrid,onsite)
+// @Param location query string false "Часть названия места работы"
+// @Param max_hours_per_week query int false "Занятость не больше стольких часов в неделю; вакансии без указанной занятости не подходят"
+// @Param min_compensation query int false "Вознаграждение может достичь этой суммы; требует currency"
+// @Param currency query string false "Код валюты вознаграждения ISO 4217" example(RUB)
+// @Param min_openings query int false "Не меньше стольких мест"
+// @Param created_from query string false "Создана не раньше (YYYY-MM-DD или RFC 3339)"
+// @Param created_to query string false "Создана не позже (YYYY-MM-DD или RFC 3339)"
+// @Success 200 {object} models.SwaggerListResponse{results=[]VacancyResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /vacancies [get]
+func (h *ProjectVacancyHandler) ListVacancies(c *gin.Context) {
+	var projectID uint64
+	if project := c.Query("project"); project != "" {
+		var err error
+		projectID, err = strconv.ParseUint(project, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project id"})
+			return
+		}
+	}
+
+	h.listVacancies(c, uint(projectID), false)
+}
+
+// listVacancies отвечает страницей вакансий; projectPage включает вакансии черновиков
+// и проектов по ссылке, которые не показываются в общем списке
+func (h *ProjectVacancyHandler) listVacancies(c *gin.Context, projectID uint, projectPage bool) {
+	params, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	filter := repository.VacancyFilter{
+		ViewerID:        viewerID(c),
+		ProjectID:       projectID,
+		Technology:      c.Query("technology"),
+		IncludeDrafts:   projectPage,
+		IncludeUnlisted: projectPage,
+	}
+	switch status := c.Query("status"); {
+	case status == "all":
+		filter.IncludeClosed = true
+	case status == "" || service.IsVacancyStatus(status):
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "status must be one of: open, closed, filled, all"})
+		return
+	}
+	if err := parseVacancyTermsFilter(c, &filter); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	filter.CreatedFrom, filter.CreatedTo, err = parseCreatedRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	vacancies, page, err := h.service.ListPage(filter, params)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	resp := make([]VacancyResponse, len(vacancies))
+	for i, v := range vacancies {
+		resp[i] = newVacancyResponse(v)
+	}
+
+	c.JSON(http.StatusOK, newListResponse(c, params, page, resp))
+}
+
+// parseVacancyTermsFilter читает из запроса фильтры по условиям работы
+func parseVacancyTermsFilter(c *gin.Context, filter *repository.VacancyFilter) error {
+	filter.Seniority = c.Query("seniority")
+	if filter.Seniority != "" && !service.IsVacancySeniority(filter.Seniority) {
+		return service.ErrInvalidSeniority
+	}
+	filter.EmploymentType = c.Query("employment_type")
+	if filter.EmploymentType != "" && !service.IsEmploymentType(filter.EmploymentType) {
+		return service.ErrInvalidEmploymentType
+	}
+	filter.RemotePolicy = c.Query("remote_policy")
+	if filter.RemotePolicy != "" && !service.IsRemotePolicy(filter.RemotePolicy) {
+		return service.ErrInvalidRemotePolicy
+	}
+	filter.Location = c.Query("location")
+
+	filter.Currency = strings.ToUpper(c.Query("currency"))
+	if filter.Currency != "" && !service.IsCurrency(filter.Currency) {
+		return errors.New("currency must be a three-letter ISO 4217 code")
+	}
+
+	var err error
+	if filter.MaxHoursPerWeek, err = positiveIntQuery(c, "max_hours_per_week"); err != nil {
+		return err
+	}
+	if filter.MinOpenings, err = positiveIntQuery(c, "min_openings"); err != nil {
+		return err
+	}
+	minCompensation, err := positiveIntQuery(c, "min_compensation")
+	if err != nil {
+		return err
+	}
+	if minCompensation > 0 && filter.Currency == "" {
+		return errors.New("min_compensation requires currency")
+	}
+	filter.MinCompensation = int64(minCompensation)
+	return nil
+}
+
+// positiveIntQuery читает положительное целое из параметра запроса name; 0 — параметр не передан
+func positiveIntQuery(c *gin.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return n, nil
+}
+
+// SearchVacancies godoc
+// @Summary Полнотекстовый поиск вакансий
+// @Description Ищет вакансии по названию, технологиям и описанию с учетом словоформ русского и английского языков. Результаты упорядочены по релевантности, совпадения во фрагменте выделены тегом <mark>
+// @Tags vacancies
+// @Accept json
+// @Produce json
+// @Param q query string true "Поисковый запрос: слова, фразы в двойных кавычках, -слово для исключения, or между вариантами"
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы, не больше 100" default(20)
+// @Success 200 {object} models.SwaggerListResponse{results=[]VacancySearchResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /vacancies/search [get]
+func (h *ProjectVacancyHandler) SearchVacancies(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Search query is required"})
+		return
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	results, page, err := h.service.Search(query, viewerID(c), params)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	resp := make([]VacancySearchResponse, len(results))
+	for i, r := range results {
+		resp[i] = VacancySearchResponse{
+			VacancyResponse: newVacancyResponse(r.Vacancy),
+			Rank:            r.Rank,
+			Headline:        r.Headline,
+		}
+	}
+
+	c.JSON(http.StatusOK, newListResponse(c, params, page, resp))
+}
+
+// GetVacancy godoc
+// @Summary Получить вакансию
+// @Description Получает вакансию в любом состоянии. Вакансию закрытого проекта видят только его участники
+// @Tags vacancies
+// @Produce json
+// @Param id path int true "ID вакансии"
+// @Success 200 {object} VacancyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /vacancies/{id} [get]
+func (h *ProjectVacancyHandler) GetVacancy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vacancy id"})
+		return
+	}
+
+	vacancy, err := h.service.Get(uint(id), viewerID(c))
+	if err != nil {
+		respondVacancyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newVacancyResponse(*vacancy))
+}
+
+// ReplaceVacancy godoc
+// @Summary Изменить вакансию целиком
+// @Description Заменяет название, описание, технологии, роль, срок и условия работы вакансии; если срок не передан, вакансия становится бессрочной, не переданные условия работы снимаются. Состояние вакансии не меняется. Доступно автору и сопровождающим проекта
+// @Tags vacancies
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID вакансии"
+// @Param request body ReplaceVacancyRequest true "Данные вакансии"
+// @Success 200 {object} VacancyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /vacancies/{id} [put]
+func (h *ProjectVacancyHandler) ReplaceVacancy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vacancy id"})
+		return
+	}
+
+	var req ReplaceVacancyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	technologies, err := h.findTechnologies(req.Technologies)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	var resp []VacancyResponse
-	for _, v := range vacancies {
-		var techNames []string
-		for _, t := range v.Technologies {
-			techNames = append(techNames, t.Name)
+	vacancy, err := h.service.Update(uint(id), service.VacancyUpdate{
+		Title:        &req.Title,
+		Description:  &req.Description,
+		Role:         &req.Role,
+		Technologies: &technologies,
+		ExpiresAt:    req.ExpiresAt,
+		ClearExpiry:  req.ExpiresAt == nil,
+
+		Seniority:       &req.Seniority,
+		HoursPerWeek:    &req.HoursPerWeek,
+		EmploymentType:  &req.EmploymentType,
+		RemotePolicy:    &req.RemotePolicy,
+		Location:        &req.Location,
+		CompensationMin: &req.CompensationMin,
+		CompensationMax: &req.CompensationMax,
+		Currency:        &req.Currency,
+		Openings:        &req.Openings,
+	})
+	if err != nil {
+		respondVacancyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newVacancyResponse(*vacancy))
+}
+
+// UpdateVacancy godoc
+// @Summary Изменить вакансию
+// @Description Меняет переданные поля вакансии. Через status вакансию можно закрыть (closed), отметить заполненной (filled) или открыть снова (open); вакансию с истекшим сроком можно открыть, только передав новый срок. Открытая вакансия закрывается автоматически после expires_at. Доступно автору и сопровождающим проекта
+// @Tags vacancies
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID вакансии"
+// @Param request body UpdateVacancyRequest true "Изменения вакансии"
+// @Success 200 {object} VacancyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /vacancies/{id} [patch]
+func (h *ProjectVacancyHandler) UpdateVacancy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vacancy id"})
+		return
+	}
+
+	var req UpdateVacancyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := service.VacancyUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+		Role:        req.Role,
+		Status:      req.Status,
+		ExpiresAt:   req.ExpiresAt,
+
+		Seniority:       req.Seniority,
+		HoursPerWeek:    req.HoursPerWeek,
+		EmploymentType:  req.EmploymentType,
+		RemotePolicy:    req.RemotePolicy,
+		Location:        req.Location,
+		CompensationMin: req.CompensationMin,
+		CompensationMax: req.CompensationMax,
+		Currency:        req.Currency,
+		Openings:        req.Openings,
+	}
+	if req.Technologies != nil {
+		technologies, err := h.findTechnologies(*req.Technologies)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		update.Technologies = &technologies
+	}
+
+	vacancy, err := h.service.Update(uint(id), update)
+	if err != nil {
+		respondVacancyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newVacancyResponse(*vacancy))
+}
+
+// DeleteVacancy godoc
+// @Summary Удалить вакансию
+// @Description Удаляет вакансию, на которую еще никто не откликнулся. Вакансию с откликами можно только закрыть. Доступно автору и сопровождающим проекта
+// @Tags vacancies
+// @Security ApiKeyAuth
+// @Param id path int true "ID вакансии"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /vacancies/{id} [delete]
+func (h *ProjectVacancyHandler) DeleteVacancy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid vacancy id"})
+		return
+	}
+
+	if err := h.service.Delete(uint(id)); err != nil {
+		respondVacancyError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// findTechnologies возвращает технологии с переданными id; неизвестные id пропускаются
+func (h *ProjectVacancyHandler) findTechnologies(ids []uint) ([]models.Technology, error) {
+	technologies := []models.Technology{}
+	if len(ids) > 0 {
+		if err := h.service.DB().Find(&technologies, ids).Error; err != nil {
+			return nil, err
 		}
-		resp = append(resp, VacancyResponse{
-			ID:              v.ID,
-			ProjectID:       v.ProjectID,
-			Title:           v.Title,
-			Description:     v.Description,
-			TechnologyNames: techNames,
-			CreatedAt:       v.CreatedAt,
-		})
 	}
+	return technologies, nil
+}
 
-	c.JSON(http.StatusOK, resp)
+func respondVacancyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrVacancyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrVacancyHasApplications):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidProjectRole),
+		errors.Is(err, service.ErrInvalidVacancyStatus),
+		errors.Is(err, service.ErrVacancyExpiryPast),
+		errors.Is(err, service.ErrVacancyExpired),
+		errors.Is(err, service.ErrInvalidSeniority),
+		errors.Is(err, service.ErrInvalidEmploymentType),
+		errors.Is(err, service.ErrInvalidRemotePolicy),
+		errors.Is(err, service.ErrVacancyLocationInvalid),
+		errors.Is(err, service.ErrInvalidHoursPerWeek),
+		errors.Is(err, service.ErrInvalidCompensation),
+		errors.Is(err, service.ErrInvalidCurrency),
+		errors.Is(err, service.ErrInvalidOpenings):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
 
 // CreateTechnology godoc
@@ -135,9 +641,11 @@ func (h *ProjectVacancyHandler) GetProjectVacancies(c *gin.Context) {
 // @Tags technologies
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param request body CreateTechnologyRequest true "Данные технологии"
 // @Success 201 {object} models.Technology
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /technologies [post]
 func (h *ProjectVacancyHandler) CreateTechnology(c *gin.Context) {
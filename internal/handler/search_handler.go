@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+type SearchHandler struct {
+	searchService  service.SearchServiceInterface
+	photoService   service.ProjectPhotoServiceInterface
+	profileService service.ProfileServiceInterface
+}
+
+func NewSearchHandler(searchService service.SearchServiceInterface, photoService service.ProjectPhotoServiceInterface, profileService service.ProfileServiceInterface) *SearchHandler {
+	return &SearchHandler{
+		searchService:  searchService,
+		photoService:   photoService,
+		profileService: profileService,
+	}
+}
+
+// SearchHitResponse — найденная запись; заполнено поле, соответствующее type
+type SearchHitResponse struct {
+	Type     string               `json:"type" example:"project" enums:"project,vacancy,user,tag"`
+	Rank     float64              `json:"rank" example:"0.6079271"`
+	Headline string               `json:"headline" example:"Сервис для поиска <mark>команды</mark>"`
+	Project  *ProjectResponse     `json:"project,omitempty"`
+	Vacancy  *VacancyResponse     `json:"vacancy,omitempty"`
+	User     *UserSummaryResponse `json:"user,omitempty"`
+	Tag      *TagResponse         `json:"tag,omitempty"`
+}
+
+// SearchFacetsResponse — число найденных записей по значениям атрибутов
+type SearchFacetsResponse struct {
+	Types        []repository.FacetCount `json:"types"`
+	Tags         []repository.FacetCount `json:"tags"`
+	Technologies []repository.FacetCount `json:"technologies"`
+	Countries    []repository.FacetCount `json:"countries"`
+	Statuses     []repository.FacetCount `json:"statuses"`
+}
+
+// SearchResponse — страница общего поиска с фасетами
+type SearchResponse struct {
+	models.SwaggerListResponse
+	Facets SearchFacetsResponse `json:"facets"`
+}
+
+func newSearchHitResponse(hit service.SearchHit, photos service.ProjectPhotoServiceInterface, profiles service.ProfileServiceInterface) (SearchHitResponse, error) {
+	response := SearchHitResponse{
+		Type:     hit.Type,
+		Rank:     hit.Rank,
+		Headline: hit.Headline,
+	}
+	switch {
+	case hit.Project != nil:
+		projects, err := newProjectResponses([]models.Project{*hit.Project}, photos)
+		if err != nil {
+			return response, err
+		}
+		response.Project = &projects[0]
+	case hit.Vacancy != nil:
+		vacancy := newVacancyResponse(*hit.Vacancy)
+		response.Vacancy = &vacancy
+	case hit.User != nil:
+		user, err := newUserSummaryResponse(*hit.User, profiles)
+		if err != nil {
+			return SearchHitResponse{}, err
+		}
+		response.User = &user
+	case hit.Tag != nil:
+		response.Tag = &TagResponse{ID: hit.Tag.ID, Name: hit.Tag.Name}
+	}
+	return response, nil
+}
+
+// Search godoc
+// @Summary Общий поиск
+// @Description Ищет одновременно проекты, вакансии, пользователей и теги и возвращает их одним списком по убыванию релевантности. Фасеты показывают, сколько найдено записей каждого типа и с каждым значением тега, технологии, страны и статуса проекта. Фильтр по атрибуту оставляет только типы, у которых этот атрибут есть: tag — проекты и пользователи, technology — вакансии, country — проекты и пользователи, status — проекты и вакансии. Проекты по ссылке и закрытые проекты и их вакансии находятся только для участников этих проектов. Доступны первые 1000 результатов
+// @Tags search
+// @Produce json
+// @Param q query string true "Поисковый запрос"
+// @Param type query string false "Тип записей" Enums(project, vacancy, user, tag)
+// @Param tag query string false "Тег проекта или пользователя"
+// @Param technology query string false "Технология вакансии"
+// @Param country query string false "Страна пользователя или автора проекта"
+// @Param status query string false "Статус проекта" Enums(active, archived)
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы, не больше 100" default(20)
+// @Success 200 {object} SearchResponse{results=[]SearchHitResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := service.SearchQuery{
+		ViewerID:   viewerID(c),
+		Query:      c.Query("q"),
+		Type:       c.Query("type"),
+		Tag:        c.Query("tag"),
+		Technology: c.Query("technology"),
+		Country:    c.Query("country"),
+		Status:     c.Query("status"),
+	}
+	if query.Query == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Search query is required"})
+		return
+	}
+
+	params, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	hits, page, facets, err := h.searchService.Search(query, params)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearchType) || errors.Is(err, service.ErrSearchWindowTooBig) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		respondListError(c, err)
+		return
+	}
+
+	results := make([]SearchHitResponse, len(hits))
+	for i, hit := range hits {
+		results[i], err = newSearchHitResponse(hit, h.photoService, h.profileService)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, SearchResponse{
+		SwaggerListResponse: newListResponse(c, params, page, results),
+		Facets: SearchFacetsResponse{
+			Types:        facets.Types,
+			Tags:         facets.Tags,
+			Technologies: facets.Technologies,
+			Countries:    facets.Countries,
+			Statuses:     facets.Statuses,
+		},
+	})
+}
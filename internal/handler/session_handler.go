@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionServiceInterface
+}
+
+func NewSessionHandler(sessionService service.SessionServiceInterface) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+// SessionResponse представляет вход пользователя с устройства
+type SessionResponse struct {
+	ID         uint      `json:"id" example:"1"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64)"`
+	IP         string    `json:"ip" example:"203.0.113.10"`
+	Current    bool      `json:"current" example:"true"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// ListSessions godoc
+// @Summary Список активных сессий
+// @Description Возвращает устройства, на которых выполнен вход в аккаунт текущего пользователя
+// @Tags sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} SessionResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	sessions, err := h.sessionService.List(principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		response[i] = SessionResponse{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			Current:    s.ID == principal.SessionID,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteSession godoc
+// @Summary Завершение сессии
+// @Description Выполняет выход на выбранном устройстве: отзывает refresh токены сессии и перестает принимать ее access токены
+// @Tags sessions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID сессии"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/sessions/{id} [delete]
+func (h *SessionHandler) DeleteSession(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid session ID"})
+		return
+	}
+
+	if err := h.sessionService.Revoke(principal.UserID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if uint(id) == principal.SessionID {
+		c.SetSameSite(http.SameSiteLaxMode)
+		c.SetCookie("access_token", "", -1, "/", "", false, true)
+	}
+
+	c.Status(http.StatusNoContent)
+}
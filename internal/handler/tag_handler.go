@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
 	"github.com/levstremilov/shance-app/internal/service"
 	"gorm.io/gorm"
 )
@@ -39,9 +40,11 @@ type TagResponse struct {
 // @Tags tags
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param request body CreateTagRequest true "Данные тега"
 // @Success 201 {object} TagResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tags [post]
 func (h *TagHandler) CreateTag(c *gin.Context) {
@@ -75,10 +78,12 @@ func (h *TagHandler) CreateTag(c *gin.Context) {
 // @Tags tags
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path int true "ID тега"
 // @Param request body UpdateTagRequest true "Данные тега"
 // @Success 200 {object} TagResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tags/{id} [put]
@@ -119,9 +124,11 @@ func (h *TagHandler) UpdateTag(c *gin.Context) {
 // @Tags tags
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path int true "ID тега"
 // @Success 204 "No Content"
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tags/{id} [delete]
@@ -142,17 +149,29 @@ func (h *TagHandler) DeleteTag(c *gin.Context) {
 
 // ListTags godoc
 // @Summary Получение списка тегов
-// @Description Возвращает список всех тегов
+// @Description Возвращает страницу тегов. Популярность тега — число проектов с ним
 // @Tags tags
 // @Accept json
 // @Produce json
-// @Success 200 {array} TagResponse
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы, не больше 100" default(20)
+// @Param cursor query string false "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору"
+// @Param sort query string false "Поле сортировки: created_at, name, popularity; префикс - задает обратный порядок" default(-created_at)
+// @Param q query string false "Часть названия тега"
+// @Success 200 {object} models.SwaggerListResponse{results=[]TagResponse}
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tags [get]
 func (h *TagHandler) ListTags(c *gin.Context) {
-	tags, err := h.tagService.List()
+	params, err := parseListParams(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tags, page, err := h.tagService.ListPage(repository.TagFilter{Query: c.Query("q")}, params)
+	if err != nil {
+		respondListError(c, err)
 		return
 	}
 
@@ -164,7 +183,7 @@ func (h *TagHandler) ListTags(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, newListResponse(c, params, page, response))
 }
 
 // SearchTags godoc
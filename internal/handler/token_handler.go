@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+type TokenHandler struct {
+	tokenService service.PersonalAccessTokenServiceInterface
+}
+
+func NewTokenHandler(tokenService service.PersonalAccessTokenServiceInterface) *TokenHandler {
+	return &TokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// CreateTokenRequest представляет запрос на создание персонального токена
+type CreateTokenRequest struct {
+	Name      string     `json:"name" binding:"required" example:"CI"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1" example:"projects:write,vacancies:read"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2025-12-31T00:00:00Z"`
+}
+
+// PersonalAccessTokenResponse представляет персональный токен без его значения
+type PersonalAccessTokenResponse struct {
+	ID         uint       `json:"id" example:"1"`
+	Name       string     `json:"name" example:"CI"`
+	Prefix     string     `json:"prefix" example:"shp_a1b2c3"`
+	Scopes     []string   `json:"scopes" example:"projects:write,vacancies:read"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedTokenResponse содержит значение токена, которое показывается только при создании
+type CreatedTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token" example:"shp_a1b2c3..."`
+}
+
+// ListTokens godoc
+// @Summary Список персональных токенов
+// @Description Возвращает персональные токены текущего пользователя
+// @Tags tokens
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} PersonalAccessTokenResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/tokens [get]
+func (h *TokenHandler) ListTokens(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	tokens, err := h.tokenService.List(principal.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := make([]PersonalAccessTokenResponse, len(tokens))
+	for i, t := range tokens {
+		response[i] = newPersonalAccessTokenResponse(&t)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateToken godoc
+// @Summary Создание персонального токена
+// @Description Создает токен для скриптов и интеграций. Значение токена возвращается только один раз
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body CreateTokenRequest true "Данные токена"
+// @Success 201 {object} CreatedTokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/tokens [post]
+func (h *TokenHandler) CreateToken(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	token, value, err := h.tokenService.Create(principal.UserID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidScope) || errors.Is(err, service.ErrInvalidTokenExpiry) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedTokenResponse{
+		PersonalAccessTokenResponse: newPersonalAccessTokenResponse(token),
+		Token:                       value,
+	})
+}
+
+// DeleteToken godoc
+// @Summary Отзыв персонального токена
+// @Description Удаляет персональный токен текущего пользователя
+// @Tags tokens
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "ID токена"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/tokens/{id} [delete]
+func (h *TokenHandler) DeleteToken(c *gin.Context) {
+	principal, exists := auth.FromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid token ID"})
+		return
+	}
+
+	if err := h.tokenService.Revoke(principal.UserID, uint(id)); err != nil {
+		if errors.Is(err, service.ErrPersonalAccessTokenMissing) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func newPersonalAccessTokenResponse(t *models.PersonalAccessToken) PersonalAccessTokenResponse {
+	return PersonalAccessTokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     t.Scopes,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
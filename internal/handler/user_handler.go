@@ -1,23 +1,31 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
-	_ "gorm.io/gorm"
+	"gorm.io/gorm"
 
 	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
 	"github.com/levstremilov/shance-app/internal/service"
 )
 
 type UserHandler struct {
-	userService service.UserServiceInterface
+	userService    service.UserServiceInterface
+	photoService   service.ProjectPhotoServiceInterface
+	profileService service.ProfileServiceInterface
 }
 
-func NewUserHandler(userService service.UserServiceInterface) *UserHandler {
+func NewUserHandler(userService service.UserServiceInterface, photoService service.ProjectPhotoServiceInterface, profileService service.ProfileServiceInterface) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:    userService,
+		photoService:   photoService,
+		profileService: profileService,
 	}
 }
 
@@ -25,7 +33,6 @@ type UpdateUserRequest struct {
 	FirstName *string   `json:"name" example:"Новое имя"`
 	LastName  *string   `json:"title" example:"Новая фамилия"`
 	Phone     *string   `json:"subtitle" example:"Новый номер телефона"`
-	Role      *string   `json:"description" example:"Новая роль"`
 	Tags      *[]string `json:"photo" example:"new_photo1.jpg, new_photo2.jpg"`
 	Country   *string   `json:"tags" example:"РА СИ Я"`
 	City      *string   `json:"city" example:"Санкт-Петербург"`
@@ -42,7 +49,7 @@ type UpdateUserRequest struct {
 // @Failure 401 {object} ErrorResponse
 // @Router /users/me [get]
 func (h *UserHandler) GetMe(c *gin.Context) {
-	_, exists := c.Get("user_id")
+	_, exists := auth.FromContext(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
 		return
@@ -55,16 +62,114 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// UserSummaryResponse — пользователь в списке пользователей
+type UserSummaryResponse struct {
+	ID        uint            `json:"id" example:"1"`
+	FirstName string          `json:"first_name" example:"Иван"`
+	LastName  string          `json:"last_name" example:"Иванов"`
+	Country   string          `json:"country" example:"Россия"`
+	City      string          `json:"city" example:"Санкт-Петербург"`
+	Tags      []string        `json:"tags" example:"go,backend"`
+	Avatar    *AvatarResponse `json:"avatar"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// UserProfileResponse — публичный профиль пользователя
+type UserProfileResponse struct {
+	UserSummaryResponse
+	Portfolio PortfolioResponse `json:"portfolio"`
+}
+
+// newUserSummaryResponse оставляет только публичные поля пользователя
+func newUserSummaryResponse(u models.User, profiles service.ProfileServiceInterface) (UserSummaryResponse, error) {
+	tags := make([]string, len(u.Tags))
+	for i, t := range u.Tags {
+		tags[i] = t.Name
+	}
+	avatar, err := newAvatarResponse(u.Avatar, profiles)
+	if err != nil {
+		return UserSummaryResponse{}, err
+	}
+	return UserSummaryResponse{
+		ID:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Country:   u.Country,
+		City:      u.City,
+		Tags:      tags,
+		Avatar:    avatar,
+		CreatedAt: u.CreatedAt,
+	}, nil
+}
+
+// ListUsers godoc
+// @Summary Получение списка пользователей
+// @Description Возвращает страницу пользователей с фильтрами и сортировкой. Популярность пользователя — число проектов, в которых он участвует
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param page_size query int false "Размер страницы, не больше 100" default(20)
+// @Param cursor query string false "Курсор из ссылки next; пустое значение запрашивает первую страницу выборки по курсору"
+// @Param sort query string false "Поле сортировки: created_at, name, popularity; префикс - задает обратный порядок" default(-created_at)
+// @Param country query string false "Страна"
+// @Param city query string false "Город"
+// @Param tag query string false "Название тега"
+// @Param role query string false "Глобальная роль"
+// @Param created_from query string false "Зарегистрирован не раньше (YYYY-MM-DD или RFC 3339)"
+// @Param created_to query string false "Зарегистрирован не позже (YYYY-MM-DD или RFC 3339)"
+// @Success 200 {object} models.SwaggerListResponse{results=[]UserSummaryResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	params, err := parseListParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	filter := repository.UserFilter{
+		Country: c.Query("country"),
+		City:    c.Query("city"),
+		Tag:     c.Query("tag"),
+		Role:    c.Query("role"),
+	}
+	filter.CreatedFrom, filter.CreatedTo, err = parseCreatedRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	users, page, err := h.userService.ListPage(filter, params)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	response := make([]UserSummaryResponse, len(users))
+	for i, u := range users {
+		response[i], err = newUserSummaryResponse(u, h.profileService)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, newListResponse(c, params, page, response))
+}
+
 // GetUser godoc
-// @Summary Получение информации о пользователе
-// @Description Возвращает информацию о пользователе по его ID
+// @Summary Получение профиля пользователя
+// @Description Возвращает публичный профиль пользователя по его ID: аватар и портфолио со ссылками и файлами. Контактные данные в профиль не входят
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param id path int true "ID пользователя"
-// @Success 200 {object} models.SwaggerUser
+// @Success 200 {object} UserProfileResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
 // @Router /users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -73,12 +178,27 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetByID(uint(id))
+	user, err := h.profileService.GetProfile(uint(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "user not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, user)
+
+	summary, err := newUserSummaryResponse(*user, h.profileService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	portfolio, err := newPortfolioResponse(*user, h.profileService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, UserProfileResponse{UserSummaryResponse: summary, Portfolio: portfolio})
 }
 
 // UpdateMe godoc
@@ -95,7 +215,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Failure 500 {object} ErrorResponse
 // @Router /users/me [patch]
 func (h *UserHandler) UpdateMe(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+	principal, exists := auth.FromContext(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
 		return
@@ -107,29 +227,32 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 		return
 	}
 
-	currentUser, err := h.userService.GetByID(userID.(uint))
+	currentUser, err := h.userService.GetByID(principal.UserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	var fields []string
 	if req.FirstName != nil {
 		currentUser.FirstName = *req.FirstName
+		fields = append(fields, "first_name")
 	}
 	if req.LastName != nil {
 		currentUser.LastName = *req.LastName
+		fields = append(fields, "last_name")
 	}
 	if req.Phone != nil {
 		currentUser.Phone = *req.Phone
-	}
-	if req.Role != nil {
-		currentUser.Role = *req.Role
+		fields = append(fields, "phone")
 	}
 	if req.Country != nil {
 		currentUser.Country = *req.Country
+		fields = append(fields, "country")
 	}
 	if req.City != nil {
 		currentUser.City = *req.City
+		fields = append(fields, "city")
 	}
 	if req.Tags != nil {
 		tags := make([]models.Tag, len(*req.Tags))
@@ -137,9 +260,10 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 			tags[i] = models.Tag{Name: tagName}
 		}
 		currentUser.Tags = tags
+		fields = append(fields, "Tags")
 	}
 
-	if err := h.userService.Update(currentUser); err != nil {
+	if err := h.userService.Update(currentUser, fields...); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -147,53 +271,83 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 	c.JSON(http.StatusOK, currentUser)
 }
 
-// GetOwnProjects godoc
-// @Summary Получение проектов пользователя
-// @Description Возвращает список проектов текущего пользователя
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required" example:"moderator" enums:"user,moderator,admin"`
+}
+
+// UpdateRole godoc
+// @Summary Изменение роли пользователя
+// @Description Назначает пользователю глобальную роль и завершает все его сессии, чтобы токены со старой ролью перестали действовать. Доступно только администраторам
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
-// @Success 200 {array} ProjectResponse
+// @Param id path int true "ID пользователя"
+// @Param request body UpdateRoleRequest true "Новая роль"
+// @Success 200 {object} models.SwaggerUser
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ForbiddenResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
-// @Router /users/{user_id}/projects [get]
-func (h *UserHandler) GetOwnProjects(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "user not authenticated"})
+// @Router /users/{id}/role [put]
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
 		return
 	}
 
-	userIDUint, ok := userID.(uint)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "invalid user id type"})
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	projects, err := h.userService.GetOwnProjects(userIDUint)
+	user, err := h.userService.UpdateRole(uint(id), req.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		switch {
+		case errors.Is(err, service.ErrInvalidRole):
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "user not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
 		return
 	}
 
-	response := make([]ProjectResponse, len(projects))
-	for i, p := range projects {
-		tags := make([]string, len(p.Tags))
-		for j, t := range p.Tags {
-			tags[j] = t.Name
-		}
+	c.JSON(http.StatusOK, user)
+}
 
-		response[i] = ProjectResponse{
-			ID:          p.ID,
-			Name:        p.Name,
-			Title:       p.Title,
-			Subtitle:    p.Subtitle,
-			Description: p.Description,
-			Photo:       []string{p.Photo},
-			Tags:        tags,
-			UserID:      p.UserID,
-		}
+// GetOwnProjects godoc
+// @Summary Получение проектов пользователя
+// @Description Возвращает список проектов пользователя. Черновики видны только самому пользователю, проекты по ссылке и закрытые проекты — только участникам
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "ID пользователя"
+// @Success 200 {array} ProjectResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{id}/projects [get]
+func (h *UserHandler) GetOwnProjects(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	projects, err := h.userService.GetOwnProjects(uint(userID), viewerID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response, err := newProjectResponses(projects, h.photoService)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
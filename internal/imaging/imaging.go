@@ -0,0 +1,281 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/buckket/go-blurhash"
+	"github.com/gen2brain/webp"
+	"golang.org/x/image/draw"
+)
+
+// Форматы, в которых сохраняются изображения
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatWebP = "webp"
+)
+
+// Имя варианта с исходным размером изображения
+const OriginalVariant = "original"
+
+const (
+	jpegQuality = 85
+	webpQuality = 80
+	// Максимальная сторона изображения, от которого считается blurhash
+	blurhashSize        = 32
+	blurhashXComponents = 4
+	blurhashYComponents = 3
+	// Ограничение на число пикселей защищает от изображений, которые
+	// занимают мало места в файле, но огромны после декодирования
+	maxPixels = 50_000_000
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image dimensions are too large")
+)
+
+var contentTypes = map[string]string{
+	FormatJPEG: "image/jpeg",
+	FormatPNG:  "image/png",
+	FormatWebP: "image/webp",
+}
+
+// Size — вариант изображения, уменьшенный так, чтобы его большая сторона
+// не превышала MaxSide. Изображения меньшего размера не увеличиваются.
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+// ProjectPhotoSizes — варианты фотографий проектов
+var ProjectPhotoSizes = []Size{
+	{Name: "thumb", MaxSide: 320},
+	{Name: "medium", MaxSide: 800},
+	{Name: "large", MaxSide: 1600},
+}
+
+// AvatarSizes — варианты аватаров пользователей
+var AvatarSizes = []Size{
+	{Name: "small", MaxSide: 64},
+	{Name: "medium", MaxSide: 256},
+	{Name: "large", MaxSide: 512},
+}
+
+// Variant — закодированный вариант изображения
+type Variant struct {
+	Name        string
+	Format      string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// Result — результат обработки изображения
+type Result struct {
+	// Width и Height — размеры изображения с учетом ориентации из EXIF
+	Width    int
+	Height   int
+	Blurhash string
+	// Variants содержит уменьшенные варианты в формате WebP и в JPEG или PNG
+	// для клиентов без WebP, а после Process — и исходное изображение
+	// (OriginalVariant) в исходном формате
+	Variants []Variant
+}
+
+// Process декодирует изображение, поворачивает его по ориентации из EXIF и
+// кодирует заново, поэтому ни один вариант не содержит метаданных исходного файла,
+// в том числе координат съемки.
+func Process(data []byte, sizes []Size) (*Result, error) {
+	img, format, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := describe(img)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := encode(img, format)
+	if err != nil {
+		return nil, err
+	}
+	result.Variants = append(result.Variants, newVariant(OriginalVariant, format, img, original))
+
+	variants, err := encodeSizes(img, sizes)
+	if err != nil {
+		return nil, err
+	}
+	result.Variants = append(result.Variants, variants...)
+	return result, nil
+}
+
+// ProcessSquare работает как Process, но сначала обрезает изображение по центру
+// до квадрата и не сохраняет исходное изображение: остаются только уменьшенные варианты
+func ProcessSquare(data []byte, sizes []Size) (*Result, error) {
+	img, _, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	img = cropSquare(img)
+	result, err := describe(img)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Variants, err = encodeSizes(img, sizes)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decode декодирует изображение и применяет ориентацию из EXIF
+func decode(data []byte) (*image.NRGBA, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if _, ok := contentTypes[format]; !ok {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	img := toNRGBA(decoded)
+	if format == FormatJPEG {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+	return img, format, nil
+}
+
+// describe возвращает размеры и blurhash изображения
+func describe(img *image.NRGBA) (*Result, error) {
+	bounds := img.Bounds()
+	result := &Result{Width: bounds.Dx(), Height: bounds.Dy()}
+
+	var err error
+	result.Blurhash, err = blurhash.Encode(blurhashXComponents, blurhashYComponents, resize(img, blurhashSize))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// encodeSizes кодирует уменьшенные варианты изображения
+func encodeSizes(img *image.NRGBA, sizes []Size) ([]Variant, error) {
+	// Для клиентов без поддержки WebP: JPEG, а для изображений с прозрачностью — PNG
+	fallback := FormatJPEG
+	if !img.Opaque() {
+		fallback = FormatPNG
+	}
+
+	var variants []Variant
+	for _, size := range sizes {
+		scaled := resize(img, size.MaxSide)
+		for _, f := range []string{FormatWebP, fallback} {
+			encoded, err := encode(scaled, f)
+			if err != nil {
+				return nil, err
+			}
+			variants = append(variants, newVariant(size.Name, f, scaled, encoded))
+		}
+	}
+	return variants, nil
+}
+
+// ContentType возвращает MIME тип формата
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Extension возвращает расширение файла формата
+func Extension(format string) string {
+	if format == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+func newVariant(name, format string, img *image.NRGBA, data []byte) Variant {
+	return Variant{
+		Name:        name,
+		Format:      format,
+		ContentType: contentTypes[format],
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		Data:        data,
+	}
+}
+
+// encode кодирует изображение в формате format
+func encode(img *image.NRGBA, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG:
+		err = png.Encode(&buf, img)
+	case FormatWebP:
+		err = webp.Encode(&buf, img, webp.Options{Quality: webpQuality})
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s image: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Bounds().Min == (image.Point{}) {
+		return nrgba
+	}
+	bounds := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+	return dst
+}
+
+// resize уменьшает изображение так, чтобы большая сторона не превышала maxSide
+func resize(img *image.NRGBA, maxSide int) *image.NRGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// cropSquare вырезает из центра изображения квадрат со стороной, равной меньшей стороне
+func cropSquare(img *image.NRGBA) *image.NRGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	side := min(width, height)
+	if width == height {
+		return img
+	}
+	x0, y0 := (width-side)/2, (height-side)/2
+	dst := image.NewNRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), img, image.Pt(x0, y0), draw.Src)
+	return dst
+}
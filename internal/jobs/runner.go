@@ -0,0 +1,128 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+)
+
+// Handler выполняет задачу и возвращает ее результат
+type Handler func(ctx context.Context, job *models.Job) (string, error)
+
+// Config задает параметры выполнения задач
+type Config struct {
+	// PollInterval — как часто проверять очередь, когда она пуста
+	PollInterval time.Duration
+	// Lease — сколько задача может выполняться, прежде чем другой экземпляр
+	// приложения посчитает ее брошенной и запустит снова
+	Lease time.Duration
+	// MaxAttempts — после стольких неудачных запусков задача помечается failed
+	MaxAttempts int
+	// RetryDelay — пауза перед повторным запуском, растет с каждой попыткой
+	RetryDelay time.Duration
+}
+
+// Runner забирает задачи из таблицы jobs и выполняет зарегистрированные для них обработчики.
+// Несколько экземпляров приложения могут работать с одной очередью одновременно.
+type Runner struct {
+	repo     *repository.JobRepository
+	cfg      Config
+	handlers map[string]Handler
+	types    []string
+}
+
+func NewRunner(repo *repository.JobRepository, cfg Config) *Runner {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &Runner{
+		repo:     repo,
+		cfg:      cfg,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register назначает обработчик задачам типа jobType
+func (r *Runner) Register(jobType string, handler Handler) {
+	if _, exists := r.handlers[jobType]; !exists {
+		r.types = append(r.types, jobType)
+	}
+	r.handlers[jobType] = handler
+}
+
+// Run выполняет задачи, пока не будет отменен ctx
+func (r *Runner) Run(ctx context.Context) {
+	if len(r.types) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Пока в очереди есть задачи, выполняем их без паузы
+		for ctx.Err() == nil && r.runNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNext выполняет одну задачу и сообщает, была ли она в очереди
+func (r *Runner) runNext(ctx context.Context) bool {
+	now := time.Now()
+	job, err := r.repo.Claim(r.types, now, now.Add(-r.cfg.Lease))
+	if err != nil {
+		log.Printf("Failed to claim job: %v", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	result, err := r.execute(ctx, job)
+	if err != nil {
+		r.fail(job, err)
+		return true
+	}
+
+	if err := r.repo.Complete(job.ID, result, time.Now()); err != nil {
+		log.Printf("Failed to complete job %d: %v", job.ID, err)
+	}
+	return true
+}
+
+func (r *Runner) execute(ctx context.Context, job *models.Job) (result string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Lease)
+	defer cancel()
+
+	return r.handlers[job.Type](ctx, job)
+}
+
+func (r *Runner) fail(job *models.Job, cause error) {
+	log.Printf("Job %d (%s) failed on attempt %d: %v", job.ID, job.Type, job.Attempts, cause)
+
+	var err error
+	if job.Attempts < r.cfg.MaxAttempts {
+		err = r.repo.Retry(job.ID, cause.Error(), time.Now().Add(time.Duration(job.Attempts)*r.cfg.RetryDelay))
+	} else {
+		err = r.repo.Fail(job.ID, cause.Error(), time.Now())
+	}
+	if err != nil {
+		log.Printf("Failed to update job %d: %v", job.ID, err)
+	}
+}
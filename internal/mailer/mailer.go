@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/levstremilov/shance-app/internal/config"
+)
+
+// Message описывает письмо, отправляемое пользователю
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New создает Mailer в соответствии с MAIL_DRIVER: smtp или log.
+// Драйвер log пишет в лог письма целиком, вместе с токенами из ссылок,
+// поэтому вне ENV=dev он не используется.
+func New(cfg *config.Config) (Mailer, error) {
+	switch cfg.Mail.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUser, cfg.Mail.SMTPPassword, cfg.Mail.From), nil
+	case "log", "":
+		if cfg.Server.Env != "dev" {
+			return nil, errors.New("the log mail driver is allowed only with ENV=dev, set MAIL_DRIVER=smtp")
+		}
+		return NewLogMailer(cfg.Mail.Dir), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", cfg.Mail.Driver)
+	}
+}
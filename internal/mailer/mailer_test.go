@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"mime"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/levstremilov/shance-app/internal/config"
+)
+
+func TestSMTPMailerEncodesSubject(t *testing.T) {
+	m := NewSMTPMailer("localhost", "587", "", "", "Shance <no-reply@shance.local>")
+	raw := m.build(Message{To: "user@example.com", Subject: "Подтверждение email", Body: "Перейдите по ссылке"})
+
+	header, _, _ := strings.Cut(string(raw), "\r\n\r\n")
+	for _, line := range strings.Split(header, "\r\n") {
+		for _, r := range line {
+			if r > 127 {
+				t.Fatalf("header line is not ASCII: %q", line)
+			}
+		}
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Подтверждение email" {
+		t.Fatalf("decoded subject = %q, %v", subject, err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "text/plain" || params["charset"] != "utf-8" {
+		t.Fatalf("Content-Type = %q", msg.Header.Get("Content-Type"))
+	}
+	if msg.Header.Get("MIME-Version") != "1.0" {
+		t.Fatalf("MIME-Version = %q", msg.Header.Get("MIME-Version"))
+	}
+}
+
+func TestNewRefusesLogDriverOutsideDev(t *testing.T) {
+	cfg := &config.Config{}
+	for _, driver := range []string{"log", ""} {
+		cfg.Mail.Driver = driver
+
+		cfg.Server.Env = "prod"
+		if _, err := New(cfg); err == nil {
+			t.Errorf("MAIL_DRIVER=%q accepted with ENV=prod", driver)
+		}
+
+		cfg.Server.Env = "dev"
+		if m, err := New(cfg); err != nil {
+			t.Errorf("MAIL_DRIVER=%q with ENV=dev: %v", driver, err)
+		} else if _, ok := m.(*LogMailer); !ok {
+			t.Errorf("MAIL_DRIVER=%q with ENV=dev created %T", driver, m)
+		}
+	}
+}
@@ -1,46 +1,169 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/auth"
 	"github.com/levstremilov/shance-app/internal/service"
 )
 
-func AuthMiddleware(authService service.AuthServiceInterface) gin.HandlerFunc {
+var (
+	errMalformedAuthorization = errors.New("invalid authorization header format")
+	errInvalidToken           = errors.New("invalid token")
+	// errInvalidCookie — недействительный или просроченный токен из cookie access_token
+	errInvalidCookie = errors.New("invalid token")
+)
+
+// Authenticator определяет пользователя запроса. Учетные данные ищутся в порядке:
+// заголовок Authorization: Bearer (JWT или персональный токен), заголовок X-API-Key
+// (персональный токен), cookie access_token (JWT).
+type Authenticator struct {
+	authService    service.AuthServiceInterface
+	tokenService   service.PersonalAccessTokenServiceInterface
+	sessionService service.SessionServiceInterface
+}
+
+func NewAuthenticator(authService service.AuthServiceInterface, tokenService service.PersonalAccessTokenServiceInterface, sessionService service.SessionServiceInterface) *Authenticator {
+	return &Authenticator{
+		authService:    authService,
+		tokenService:   tokenService,
+		sessionService: sessionService,
+	}
+}
+
+// Required отклоняет запросы без действительных учетных данных
+func (a *Authenticator) Required() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		accessToken, err := c.Cookie("access_token")
+		principal, err := a.resolve(c)
 		if err != nil {
-			fmt.Printf("Cookie error: %v\n", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-			c.Abort()
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
+		if principal == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		auth.SetPrincipal(c, principal)
+		c.Next()
+	}
+}
 
-		fmt.Printf("Got access token: %s\n", accessToken)
+// Optional пропускает анонимные запросы. Недействительные заголовки Authorization
+// и X-API-Key отклоняются, а cookie с просроченным токеном игнорируется: браузер,
+// который не успел обновить токен, видит публичные страницы анонимно.
+func (a *Authenticator) Optional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := a.resolve(c)
+		if err != nil && !errors.Is(err, errInvalidCookie) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if principal != nil {
+			auth.SetPrincipal(c, principal)
+		}
+
+		c.Next()
+	}
+}
+
+// resolve возвращает nil без ошибки, если учетные данные не переданы
+func (a *Authenticator) resolve(c *gin.Context) (*auth.Principal, error) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, credentials, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || credentials == "" {
+			return nil, errMalformedAuthorization
+		}
+		if strings.HasPrefix(credentials, service.PersonalAccessTokenPrefix) {
+			return a.fromPersonalAccessToken(credentials)
+		}
+		return a.fromAccessToken(credentials)
+	}
 
-		// Убираем префикс "Bearer " если он есть
-		accessToken = strings.TrimPrefix(accessToken, "Bearer ")
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return a.fromPersonalAccessToken(apiKey)
+	}
 
-		claims, err := authService.ValidateToken(accessToken)
+	if accessToken, err := c.Cookie("access_token"); err == nil && accessToken != "" {
+		principal, err := a.fromAccessToken(accessToken)
 		if err != nil {
-			fmt.Printf("Token validation error: %v\n", err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			c.Abort()
+			return nil, errInvalidCookie
+		}
+		return principal, nil
+	}
+
+	return nil, nil
+}
+
+func (a *Authenticator) fromAccessToken(accessToken string) (*auth.Principal, error) {
+	claims, err := a.authService.ValidateToken(accessToken)
+	if err != nil {
+		return nil, errInvalidToken
+	}
+
+	// Каждый access токен привязан к сессии: без нее токен нельзя отозвать
+	if claims.SessionID == 0 {
+		return nil, errInvalidToken
+	}
+	if err := a.sessionService.Touch(claims.SessionID); err != nil {
+		return nil, errInvalidToken
+	}
+
+	return &auth.Principal{
+		UserID:         claims.UserID,
+		Email:          claims.Email,
+		Role:           claims.Role,
+		Method:         auth.MethodSession,
+		SessionID:      claims.SessionID,
+		ImpersonatorID: claims.ImpersonatorID,
+	}, nil
+}
+
+func (a *Authenticator) fromPersonalAccessToken(value string) (*auth.Principal, error) {
+	token, err := a.tokenService.Authenticate(value)
+	if err != nil {
+		return nil, errInvalidToken
+	}
+
+	return &auth.Principal{
+		UserID:  token.UserID,
+		Email:   token.User.Email,
+		Role:    token.User.Role,
+		Method:  auth.MethodToken,
+		Scopes:  token.Scopes,
+		TokenID: token.ID,
+	}, nil
+}
+
+// RequireScope пропускает запросы с персональным токеном, только если у токена есть scope.
+// Запросы с сессией пользователя и анонимные запросы не ограничиваются.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal, ok := auth.FromContext(c); ok && !principal.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":  "token does not have required scope: " + scope,
+				"reason": "insufficient_scope",
+			})
 			return
 		}
 
-		// Устанавливаем данные пользователя в контекст
-		c.Set("user_id", claims.UserID)
-		c.Set("user_role", claims.Role)
-		c.Set("user", &models.User{
-			ID:    claims.UserID,
-			Email: claims.Email,
-			Role:  claims.Role,
-		})
+		c.Next()
+	}
+}
+
+// RequireSession запрещает доступ по персональному токену
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal, ok := auth.FromContext(c); ok && principal.Method != auth.MethodSession {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":  "this action is not available with a personal access token",
+				"reason": "session_required",
+			})
+			return
+		}
 
 		c.Next()
 	}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+const (
+	validAccessToken = "valid-access-token"
+	// sessionlessToken — действительный токен без sid, выданный до появления сессий
+	sessionlessToken = "sessionless-access-token"
+)
+
+type stubAuthService struct {
+	service.AuthServiceInterface
+}
+
+func (stubAuthService) ValidateToken(token string) (*service.Claims, error) {
+	switch token {
+	case validAccessToken:
+		return &service.Claims{UserID: 1, SessionID: 10}, nil
+	case sessionlessToken:
+		return &service.Claims{UserID: 1}, nil
+	default:
+		return nil, errors.New("token is expired")
+	}
+}
+
+type stubTokenService struct {
+	service.PersonalAccessTokenServiceInterface
+}
+
+func (stubTokenService) Authenticate(string) (*models.PersonalAccessToken, error) {
+	return nil, service.ErrInvalidPersonalAccessToken
+}
+
+type stubSessionService struct {
+	service.SessionServiceInterface
+}
+
+func (stubSessionService) Touch(uint) error { return nil }
+
+// serveWithAuth выполняет запрос через middleware и возвращает код ответа
+// и ID пользователя, которого увидел обработчик (0 — анонимный запрос)
+func serveWithAuth(middleware func(*Authenticator) gin.HandlerFunc, prepare func(*http.Request)) (int, uint) {
+	gin.SetMode(gin.TestMode)
+	authenticator := NewAuthenticator(stubAuthService{}, stubTokenService{}, stubSessionService{})
+
+	var userID uint
+	r := gin.New()
+	r.GET("/", middleware(authenticator), func(c *gin.Context) {
+		if principal, ok := auth.FromContext(c); ok {
+			userID = principal.UserID
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	prepare(req)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code, userID
+}
+
+func withCookie(value string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: "access_token", Value: value})
+	}
+}
+
+func withHeader(name, value string) func(*http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set(name, value)
+	}
+}
+
+func TestOptionalAuth(t *testing.T) {
+	cases := []struct {
+		name     string
+		prepare  func(*http.Request)
+		status   int
+		expectID uint
+	}{
+		{"anonymous", func(*http.Request) {}, http.StatusOK, 0},
+		{"valid cookie", withCookie(validAccessToken), http.StatusOK, 1},
+		{"expired cookie", withCookie("expired"), http.StatusOK, 0},
+		{"valid bearer", withHeader("Authorization", "Bearer "+validAccessToken), http.StatusOK, 1},
+		{"expired bearer", withHeader("Authorization", "Bearer expired"), http.StatusUnauthorized, 0},
+		{"bearer without session", withHeader("Authorization", "Bearer "+sessionlessToken), http.StatusUnauthorized, 0},
+		{"cookie without session", withCookie(sessionlessToken), http.StatusOK, 0},
+		{"malformed authorization", withHeader("Authorization", "Basic abc"), http.StatusUnauthorized, 0},
+		{"invalid api key", withHeader("X-API-Key", service.PersonalAccessTokenPrefix+"unknown"), http.StatusUnauthorized, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, userID := serveWithAuth((*Authenticator).Optional, tc.prepare)
+			if status != tc.status || userID != tc.expectID {
+				t.Fatalf("got status %d, user %d; want status %d, user %d", status, userID, tc.status, tc.expectID)
+			}
+		})
+	}
+}
+
+func TestRequiredAuthRejectsExpiredCookie(t *testing.T) {
+	if status, _ := serveWithAuth((*Authenticator).Required, withCookie("expired")); status != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", status, http.StatusUnauthorized)
+	}
+	if status, userID := serveWithAuth((*Authenticator).Required, withCookie(validAccessToken)); status != http.StatusOK || userID != 1 {
+		t.Fatalf("valid cookie: status %d, user %d", status, userID)
+	}
+}
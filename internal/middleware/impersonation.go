@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/service"
+)
+
+// ForbidImpersonation закрывает действия с учетными данными пользователя
+// для администратора, работающего от его имени
+func ForbidImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if principal, ok := auth.FromContext(c); ok && principal.Impersonated() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":  "this action is not available while impersonating a user",
+				"reason": policy.ReasonImpersonation,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuditImpersonation записывает в журнал аудита каждый запрос,
+// выполненный администратором от имени пользователя
+func AuditImpersonation(auditService service.AuditServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		principal, ok := auth.FromContext(c)
+		if !ok || !principal.Impersonated() {
+			return
+		}
+
+		err := auditService.Record(service.AuditEntry{
+			Action:  service.AuditImpersonatedRequest,
+			UserID:  &principal.UserID,
+			ActorID: &principal.ImpersonatorID,
+			IP:      c.ClientIP(),
+			Metadata: map[string]interface{}{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+				"route":  c.FullPath(),
+				"status": c.Writer.Status(),
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to audit impersonated request: %v", err)
+		}
+	}
+}
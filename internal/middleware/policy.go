@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
+	"github.com/levstremilov/shance-app/internal/policy"
+)
+
+// Require пропускает запрос, если глобальная роль пользователя дает право permission
+func Require(engine *policy.Engine, permission policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := auth.FromContext(c)
+		decision := engine.Authorize(principal, permission)
+		if !decision.Allowed {
+			abortForbidden(c, decision)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireProject проверяет право permission в проекте, ID которого передан в параметре пути param
+func RequireProject(engine *policy.Engine, permission policy.Permission, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+
+		authorizeProject(c, engine, permission, uint(projectID))
+	}
+}
+
+// RequireProjectOf проверяет право permission в проекте, к которому относится объект
+// с ID из параметра пути param, например вакансия. projectOf возвращает ID проекта объекта;
+// для несуществующего объекта — ошибку notFound, которая отдается со статусом 404.
+func RequireProjectOf(engine *policy.Engine, permission policy.Permission, param string, projectOf func(id uint) (uint, error), notFound error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+
+		projectID, err := projectOf(uint(id))
+		if err != nil {
+			if errors.Is(err, notFound) {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		authorizeProject(c, engine, permission, projectID)
+	}
+}
+
+func authorizeProject(c *gin.Context, engine *policy.Engine, permission policy.Permission, projectID uint) {
+	principal, _ := auth.FromContext(c)
+	decision, err := engine.AuthorizeProject(principal, projectID, permission)
+	if err != nil {
+		if errors.Is(err, policy.ErrProjectNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !decision.Allowed {
+		abortForbidden(c, decision)
+		return
+	}
+
+	c.Next()
+}
+
+func abortForbidden(c *gin.Context, decision policy.Decision) {
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"error":      "forbidden",
+		"reason":     decision.Reason,
+		"permission": decision.Permission,
+	})
+}
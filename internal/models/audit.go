@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// AuditLog — запись журнала событий безопасности
+type AuditLog struct {
+	ID     uint   `gorm:"primaryKey"`
+	Action string `gorm:"index;not null"`
+	// UserID — пользователь, которого касается событие
+	UserID *uint `gorm:"index"`
+	// ActorID — пользователь, выполнивший действие, если это не сам UserID
+	ActorID   *uint
+	IP        string
+	Metadata  string    `gorm:"type:jsonb"`
+	CreatedAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RefreshToken хранит хеш выданного refresh токена.
+// Все токены, полученные друг из друга через ротацию, имеют общий FamilyID.
+type RefreshToken struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"index;not null"`
+	User       User   `gorm:"foreignKey:UserID"`
+	FamilyID   string `gorm:"index;not null"`
+	TokenHash  string `gorm:"uniqueIndex;not null"`
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *uint
+	CreatedAt  time.Time
+}
+
+// Session — вход пользователя с конкретного устройства. Сессия соответствует
+// семейству refresh токенов и отзывается вместе с ним.
+type Session struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"index;not null"`
+	User       User   `gorm:"foreignKey:UserID"`
+	FamilyID   string `gorm:"uniqueIndex;not null"`
+	UserAgent  string
+	IP         string
+	ExpiresAt  time.Time
+	LastSeenAt time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeAccountUnlock     = "account_unlock"
+)
+
+// OneTimeToken — одноразовый токен с ограниченным сроком действия,
+// отправляемый пользователю по почте (сброс пароля, подтверждение email).
+type OneTimeToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index;not null"`
+	User      User   `gorm:"foreignKey:UserID"`
+	Purpose   string `gorm:"index;not null"`
+	TokenHash string `gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// UserIdentity связывает пользователя с аккаунтом у внешнего провайдера входа
+type UserIdentity struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index;not null"`
+	User      User   `gorm:"foreignKey:UserID"`
+	Provider  string `gorm:"uniqueIndex:idx_user_identities_provider_subject;not null"`
+	Subject   string `gorm:"uniqueIndex:idx_user_identities_provider_subject;not null"`
+	Email     string
+	CreatedAt time.Time
+}
+
+// OAuthState хранит параметры начатого входа через внешнего провайдера
+// до возврата пользователя на callback.
+type OAuthState struct {
+	ID           uint   `gorm:"primaryKey"`
+	StateHash    string `gorm:"uniqueIndex;not null"`
+	Provider     string `gorm:"not null"`
+	CodeVerifier string `gorm:"not null"`
+	RedirectURI  string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// MFARecoveryCode — одноразовый код восстановления для входа без приложения-аутентификатора
+type MFARecoveryCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index;not null"`
+	CodeHash  string `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// PersonalAccessToken — долгоживущий токен для скриптов и интеграций.
+// Хранится только хеш; Prefix позволяет узнать токен в списке.
+type PersonalAccessToken struct {
+	ID         uint           `gorm:"primaryKey"`
+	UserID     uint           `gorm:"index;not null"`
+	User       User           `gorm:"foreignKey:UserID"`
+	Name       string         `gorm:"not null"`
+	Prefix     string         `gorm:"not null"`
+	TokenHash  string         `gorm:"uniqueIndex;not null"`
+	Scopes     pq.StringArray `gorm:"type:text[]"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// ThrottleEntry — счетчик неудачных попыток входа для аккаунта или IP адреса
+type ThrottleEntry struct {
+	Bucket       string `gorm:"primaryKey"`
+	Failures     int    `gorm:"not null;default:0"`
+	LastFailure  time.Time
+	BlockedUntil time.Time
+	Locked       bool      `gorm:"not null;default:false"`
+	ExpiresAt    time.Time `gorm:"index"`
+	UpdatedAt    time.Time
+}
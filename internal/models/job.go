@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const (
+	JobTypeDataExport      = "data_export"
+	JobTypeAccountDeletion = "account_deletion"
+	// JobTypeVacancyExpiry закрывает вакансии с истекшим сроком и планирует свой
+	// следующий запуск; задача не относится к пользователю, ее UserID равен 0
+	JobTypeVacancyExpiry = "vacancy_expiry"
+	// JobTypeExportCleanup удаляет архивы выгрузок с истекшим сроком хранения
+	// и тоже планирует свой следующий запуск
+	JobTypeExportCleanup = "export_cleanup"
+	// JobTypeAttachmentMove однократно переносит вложения откликов из общего
+	// хранилища, которое раздается статически, в закрытое
+	JobTypeAttachmentMove = "attachment_move"
+)
+
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+// Job — фоновая задача, выполняемая вне запроса пользователя.
+// Задача запускается не раньше RunAt; Result хранит результат выполнения,
+// например путь к архиву с данными.
+type Job struct {
+	ID         uint      `gorm:"primaryKey"`
+	Type       string    `gorm:"index;not null"`
+	UserID     uint      `gorm:"index;not null"`
+	Status     string    `gorm:"index;not null"`
+	Attempts   int       `gorm:"not null;default:0"`
+	RunAt      time.Time `gorm:"index"`
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	Result     string
+	Error      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
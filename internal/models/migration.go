@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// DataMigration — выполненное однократное изменение данных. Схему обновляет
+// AutoMigrate при каждом запуске, а изменения данных должны применяться один раз.
+type DataMigration struct {
+	Name      string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
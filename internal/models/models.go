@@ -16,22 +16,92 @@ type Tag struct {
 	Name     string    `gorm:"uniqueIndex;not null"`
 	Users    []User    `gorm:"many2many:user_tags;"`
 	Projects []Project `gorm:"many2many:project_tags;"`
+	// Popularity — число проектов с тегом; заполняется только при выборке списка
+	Popularity int64 `gorm:"->;-:migration" json:"-"`
 }
 
+// Статусы жизненного цикла проекта
+const (
+	ProjectStatusDraft      = "draft"
+	ProjectStatusRecruiting = "recruiting"
+	ProjectStatusActive     = "active"
+	ProjectStatusPaused     = "paused"
+	ProjectStatusCompleted  = "completed"
+	ProjectStatusArchived   = "archived"
+)
+
+// Уровни доступа к проекту: открытый проект виден всем, проект по ссылке открывается
+// по ID, но не показывается в списках и поиске, закрытый виден только участникам
+const (
+	ProjectVisibilityPublic   = "public"
+	ProjectVisibilityUnlisted = "unlisted"
+	ProjectVisibilityPrivate  = "private"
+)
+
 type Project struct {
 	gorm.Model
 	Name        string `gorm:"not null"`
 	Title       string
 	Subtitle    string
 	Description string
-	Photo       string `gorm:"type:jsonb"`
+	Photo       string `gorm:"type:jsonb"` // имена файлов от клиента; загруженные фотографии — в Photos
 	Status      string `gorm:"default:active"`
+	Visibility  string `gorm:"not null;default:public;index"`
 	StartDate   time.Time
 	EndDate     time.Time
 	UserID      uint
 	User        User   `gorm:"foreignKey:UserID"`
 	Tags        []Tag  `gorm:"many2many:project_tags;"`
 	Members     []User `gorm:"many2many:project_members;"`
+	Photos      []ProjectPhoto
+	// AcceptsJoinRequests — принимает ли проект заявки на вступление; пустое значение
+	// при создании означает true
+	AcceptsJoinRequests *bool `gorm:"not null;default:true"`
+	// Popularity — число участников проекта; заполняется только при выборке списка
+	Popularity int64 `gorm:"->;-:migration" json:"-"`
+	// SearchVector поддерживается триггером базы данных (см. database.setupSearch)
+	SearchVector string `gorm:"type:tsvector;index:idx_projects_search_vector,type:gin;->:false;<-:false" json:"-"`
+}
+
+// ProjectTransition — запись истории смены статуса проекта. UserID пустой,
+// если статус сменила система, например при удалении аккаунта автора.
+type ProjectTransition struct {
+	ID         uint   `gorm:"primaryKey"`
+	ProjectID  uint   `gorm:"index;not null"`
+	FromStatus string `gorm:"not null"`
+	ToStatus   string `gorm:"not null"`
+	UserID     *uint
+	Comment    string
+	CreatedAt  time.Time
+}
+
+// ProjectPhoto — загруженная фотография проекта. Key — ключ файла в хранилище,
+// Position задает порядок фотографий в проекте.
+type ProjectPhoto struct {
+	gorm.Model
+	ProjectID   uint   `gorm:"index;not null"`
+	Key         string `gorm:"not null"`
+	ContentType string
+	Size        int64
+	Position    int
+	Width       int
+	Height      int
+	// Blurhash — размытое превью, которое клиент показывает до загрузки фотографии
+	Blurhash string
+	Variants []ProjectPhotoVariant `gorm:"foreignKey:PhotoID"`
+}
+
+// ProjectPhotoVariant — уменьшенная копия фотографии в одном из форматов
+type ProjectPhotoVariant struct {
+	ID          uint   `gorm:"primaryKey"`
+	PhotoID     uint   `gorm:"index;not null"`
+	Name        string `gorm:"not null"`
+	Format      string `gorm:"not null"`
+	Key         string `gorm:"not null"`
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
 }
 
 type ProjectMember struct {
@@ -44,6 +114,64 @@ type ProjectMember struct {
 	gorm.Model
 }
 
+// Состояния приглашения в проект
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+// ProjectInvitation — приглашение в проект по email. Приглашенный становится участником,
+// только приняв приглашение по ссылке из письма. InviteeID заполняется, когда у адреса
+// есть аккаунт; TokenID — идентификатор последней отправленной ссылки, прежние ссылки
+// перестают действовать при повторной отправке. У проекта может быть только одно
+// ожидающее приглашение на адрес.
+type ProjectInvitation struct {
+	ID          uint    `gorm:"primaryKey"`
+	ProjectID   uint    `gorm:"not null;uniqueIndex:idx_project_invitations_pending,where:status = 'pending'"`
+	Project     Project `gorm:"foreignKey:ProjectID"`
+	Email       string  `gorm:"not null;index;uniqueIndex:idx_project_invitations_pending,where:status = 'pending'"`
+	InviteeID   *uint   `gorm:"index"`
+	InviterID   uint    `gorm:"not null"`
+	Inviter     User    `gorm:"foreignKey:InviterID"`
+	Role        string  `gorm:"not null"`
+	Status      string  `gorm:"not null;default:pending"`
+	TokenID     string  `gorm:"not null"`
+	ExpiresAt   time.Time
+	RespondedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Состояния заявки на вступление в проект
+const (
+	JoinRequestStatusPending   = "pending"
+	JoinRequestStatusApproved  = "approved"
+	JoinRequestStatusRejected  = "rejected"
+	JoinRequestStatusWithdrawn = "withdrawn"
+)
+
+// ProjectJoinRequest — заявка пользователя на вступление в проект без привязки к вакансии.
+// Заявку рассматривают владелец или мейнтейнер проекта; при одобрении пользователь
+// становится участником. У пользователя может быть только одна ожидающая заявка в проект.
+type ProjectJoinRequest struct {
+	ID         uint    `gorm:"primaryKey"`
+	ProjectID  uint    `gorm:"not null;index;uniqueIndex:idx_project_join_requests_pending,where:status = 'pending'"`
+	Project    Project `gorm:"foreignKey:ProjectID"`
+	UserID     uint    `gorm:"not null;index;uniqueIndex:idx_project_join_requests_pending,where:status = 'pending'"`
+	User       User    `gorm:"foreignKey:UserID"`
+	Message    string
+	Status     string `gorm:"not null;default:pending"`
+	ReviewerID *uint
+	// Reason — причина отказа, которую видит автор заявки
+	Reason     string
+	ReviewedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
 type UserTag struct {
 	UserID uint
 	TagID  uint
@@ -68,12 +196,117 @@ type VacancyTechnology struct {
 	gorm.Model
 }
 
+// Состояния вакансии: открытая принимает отклики, закрытая снята с публикации
+// вручную или по истечении срока, заполненная закрыта принятием кандидата
+const (
+	VacancyStatusOpen   = "open"
+	VacancyStatusClosed = "closed"
+	VacancyStatusFilled = "filled"
+)
+
+// Уровни квалификации, на которые рассчитана вакансия
+const (
+	VacancySeniorityJunior = "junior"
+	VacancySeniorityMiddle = "middle"
+	VacancySenioritySenior = "senior"
+	VacancySeniorityLead   = "lead"
+)
+
+// Виды занятости по вакансии: волонтерство, оплачиваемая работа, работа за долю в проекте, стажировка
+const (
+	EmploymentTypeVolunteer  = "volunteer"
+	EmploymentTypePaid       = "paid"
+	EmploymentTypeEquity     = "equity"
+	EmploymentTypeInternship = "internship"
+)
+
+// Форматы работы по вакансии. Для гибридного и офисного формата указывается место работы.
+const (
+	RemotePolicyRemote = "remote"
+	RemotePolicyHybrid = "hybrid"
+	RemotePolicyOnsite = "onsite"
+)
+
 type ProjectVacancy struct {
 	ID           uint         `gorm:"primaryKey" json:"id"`
 	ProjectID    uint         `json:"project_id"`
 	Project      Project      `gorm:"foreignKey:ProjectID"`
 	Title        string       `json:"title"`
 	Description  string       `json:"description"`
+	Role         string       `gorm:"not null;default:member" json:"role"` // роль в проекте принятого кандидата
+	Status       string       `gorm:"not null;default:open;index" json:"status"`
+	ExpiresAt    *time.Time   `gorm:"index" json:"expires_at"` // когда открытая вакансия закрывается автоматически; nil — без срока
 	Technologies []Technology `gorm:"many2many:vacancy_technologies;" json:"technologies"`
 	CreatedAt    time.Time    `json:"created_at"`
+
+	// Условия работы; пустая строка или ноль — условие не указано
+	Seniority       string `gorm:"index" json:"seniority"`
+	HoursPerWeek    int    `json:"hours_per_week"`
+	EmploymentType  string `gorm:"index" json:"employment_type"`
+	RemotePolicy    string `gorm:"index" json:"remote_policy"`
+	Location        string `json:"location"`
+	CompensationMin int64  `json:"compensation_min"`
+	CompensationMax int64  `json:"compensation_max"`
+	Currency        string `json:"currency"`                           // код валюты ISO 4217
+	Openings        int    `gorm:"not null;default:1" json:"openings"` // сколько кандидатов ищет проект
+
+	// SearchVector поддерживается триггером базы данных (см. database.setupSearch)
+	SearchVector string `gorm:"type:tsvector;index:idx_project_vacancies_search_vector,type:gin;->:false;<-:false" json:"-"`
+}
+
+// Этапы отбора откликов на вакансию. Принятый, отклоненный и отозванный
+// отклики больше не меняют этап.
+const (
+	ApplicationStageNew       = "new"
+	ApplicationStageScreening = "screening"
+	ApplicationStageInterview = "interview"
+	ApplicationStageOffer     = "offer"
+	ApplicationStageAccepted  = "accepted"
+	ApplicationStageRejected  = "rejected"
+	ApplicationStageWithdrawn = "withdrawn"
+)
+
+// VacancyApplication — отклик пользователя на вакансию с сопроводительным письмом
+// и необязательным вложением. ProjectID дублирует проект вакансии для выборок
+// владельца. У пользователя может быть только один рассматриваемый отклик на вакансию.
+type VacancyApplication struct {
+	ID          uint           `gorm:"primaryKey"`
+	VacancyID   uint           `gorm:"not null;index;uniqueIndex:idx_vacancy_applications_active,where:stage <> 'accepted' AND stage <> 'rejected' AND stage <> 'withdrawn'"`
+	Vacancy     ProjectVacancy `gorm:"foreignKey:VacancyID"`
+	ProjectID   uint           `gorm:"not null;index"`
+	UserID      uint           `gorm:"not null;index;uniqueIndex:idx_vacancy_applications_active,where:stage <> 'accepted' AND stage <> 'rejected' AND stage <> 'withdrawn'"`
+	User        User           `gorm:"foreignKey:UserID"`
+	CoverLetter string
+	// Attachment* описывают вложение в хранилище; пустой AttachmentKey — отклик без вложения
+	AttachmentKey         string
+	AttachmentName        string
+	AttachmentContentType string
+	AttachmentSize        int64
+	Stage                 string                   `gorm:"not null;default:new;index"`
+	Notes                 []ApplicationNote        `gorm:"foreignKey:ApplicationID"`
+	History               []ApplicationStageChange `gorm:"foreignKey:ApplicationID"`
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// ApplicationNote — заметка команды проекта к отклику; кандидат заметок не видит
+type ApplicationNote struct {
+	ID            uint   `gorm:"primaryKey"`
+	ApplicationID uint   `gorm:"index;not null"`
+	AuthorID      uint   `gorm:"not null"`
+	Author        User   `gorm:"foreignKey:AuthorID"`
+	Body          string `gorm:"not null"`
+	CreatedAt     time.Time
+}
+
+// ApplicationStageChange — запись истории смены этапа отклика. UserID пустой,
+// если этап сменила система, например при удалении аккаунта кандидата.
+type ApplicationStageChange struct {
+	ID            uint   `gorm:"primaryKey"`
+	ApplicationID uint   `gorm:"index;not null"`
+	FromStage     string `gorm:"not null"`
+	ToStage       string `gorm:"not null"`
+	UserID        *uint
+	Comment       string
+	CreatedAt     time.Time
 }
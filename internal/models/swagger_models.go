@@ -18,16 +18,19 @@ type SwaggerUser struct {
 
 // SwaggerProject представляет проект для Swagger документации
 type SwaggerProject struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Title       string    `json:"title"`
-	Subtitle    string    `json:"subtitle"`
-	Description string    `json:"description"`
-	Photo       string    `json:"photo"`
-	Status      string    `json:"status"`
-	StartDate   time.Time `json:"start_date"`
-	EndDate     time.Time `json:"end_date"`
-	UserID      uint      `json:"user_id"`
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Subtitle    string `json:"subtitle"`
+	Description string `json:"description"`
+	Photo       string `json:"photo"`
+	Status      string `json:"status"`
+	Visibility  string `json:"visibility"`
+	// AcceptsJoinRequests — принимает ли проект заявки на вступление
+	AcceptsJoinRequests bool      `json:"accepts_join_requests"`
+	StartDate           time.Time `json:"start_date"`
+	EndDate             time.Time `json:"end_date"`
+	UserID              uint      `json:"user_id"`
 }
 
 // SwaggerTag представляет тег для Swagger документации
@@ -52,10 +55,22 @@ type SwaggerTechnology struct {
 }
 
 type SwaggerProjectVacancy struct {
-	ID              uint      `json:"id"`
-	ProjectID       uint      `json:"project_id"`
-	Title           string    `json:"title"`
-	Description     string    `json:"description"`
-	TechnologyNames []string  `json:"technology_names"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              uint       `json:"id"`
+	ProjectID       uint       `json:"project_id"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	Role            string     `json:"role"`
+	Status          string     `json:"status"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	Seniority       string     `json:"seniority"`
+	HoursPerWeek    int        `json:"hours_per_week"`
+	EmploymentType  string     `json:"employment_type"`
+	RemotePolicy    string     `json:"remote_policy"`
+	Location        string     `json:"location"`
+	CompensationMin int64      `json:"compensation_min"`
+	CompensationMax int64      `json:"compensation_max"`
+	Currency        string     `json:"currency"`
+	Openings        int        `json:"openings"`
+	TechnologyNames []string   `json:"technology_names"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
@@ -7,19 +7,84 @@ import (
 )
 
 type User struct {
-	ID           uint           `json:"id" gorm:"primaryKey"`
-	Email        string         `json:"email" gorm:"unique;not null"`
-	PasswordHash string         `json:"-" gorm:"not null"`
-	FirstName    string         `json:"first_name"`
-	LastName     string         `json:"last_name"`
-	Role         string         `json:"role" gorm:"default:user"`
-	LastLogin    time.Time      `json:"last_login"`
-	Phone        string         `json:"phone"`
-	Country      string         `json:"country"`
-	City         string         `json:"city"`
-	Tags         []Tag          `json:"tags" gorm:"many2many:user_tags;"`
-	Projects     []Project      `json:"projects" gorm:"many2many:project_members;"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Email           string     `json:"email" gorm:"unique;not null"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PasswordHash    string     `json:"-" gorm:"not null"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Role            string     `json:"role" gorm:"default:user"`
+	LastLogin       time.Time  `json:"last_login"`
+	MFAEnabled      bool       `json:"mfa_enabled" gorm:"default:false"`
+	TOTPSecret      string     `json:"-"`
+	TOTPLastStep    int64      `json:"-"`
+	Phone           string     `json:"phone"`
+	Country         string     `json:"country"`
+	City            string     `json:"city"`
+	Tags            []Tag      `json:"tags" gorm:"many2many:user_tags;"`
+	Projects        []Project  `json:"projects" gorm:"many2many:project_members;"`
+	// Аватар и портфолио отдаются в публичном профиле со ссылками на файлы
+	Avatar         *UserAvatar     `json:"-"`
+	PortfolioLinks []PortfolioLink `json:"-"`
+	PortfolioFiles []PortfolioFile `json:"-"`
+	// DeletionScheduledAt — время, после которого аккаунт будет обезличен
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+	// Popularity — число проектов, в которых участвует пользователь; заполняется только при выборке списка
+	Popularity int64          `json:"-" gorm:"->;-:migration"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// UserAvatar — аватар пользователя, обрезанный до квадрата. Исходный файл
+// не хранится: аватар доступен только в уменьшенных вариантах.
+type UserAvatar struct {
+	gorm.Model
+	UserID uint `gorm:"uniqueIndex;not null"`
+	Width  int
+	Height int
+	// Blurhash — размытое превью, которое клиент показывает до загрузки аватара
+	Blurhash string
+	Variants []UserAvatarVariant `gorm:"foreignKey:AvatarID"`
+}
+
+// UserAvatarVariant — уменьшенная копия аватара в одном из форматов
+type UserAvatarVariant struct {
+	ID          uint   `gorm:"primaryKey"`
+	AvatarID    uint   `gorm:"index;not null"`
+	Name        string `gorm:"not null"`
+	Format      string `gorm:"not null"`
+	Key         string `gorm:"not null"`
+	ContentType string
+	Width       int
+	Height      int
+	Size        int64
+}
+
+// Виды ссылок портфолио; вид определяется по адресу ссылки
+const (
+	PortfolioLinkGitHub  = "github"
+	PortfolioLinkBehance = "behance"
+	PortfolioLinkWebsite = "website"
+)
+
+// PortfolioLink — ссылка в портфолио пользователя
+type PortfolioLink struct {
+	gorm.Model
+	UserID uint   `gorm:"index;not null"`
+	Kind   string `gorm:"not null"`
+	URL    string `gorm:"not null"`
+	Title  string
+}
+
+// PortfolioFile — файл в портфолио пользователя, например резюме в PDF.
+// Key — ключ файла в хранилище, Name — имя файла при загрузке.
+type PortfolioFile struct {
+	gorm.Model
+	UserID      uint   `gorm:"index;not null"`
+	Key         string `gorm:"not null"`
+	Name        string
+	Title       string
+	ContentType string
+	Size        int64
 }
@@ -0,0 +1,255 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+const (
+	KindOIDC   = "oidc"
+	KindGitHub = "github"
+)
+
+// ProviderConfig описывает внешнего провайдера входа.
+// Для OIDC провайдеров эндпоинты берутся из discovery документа издателя,
+// если не заданы явно.
+type ProviderConfig struct {
+	Name         string
+	Kind         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+}
+
+// UserInfo — данные пользователя, полученные от провайдера
+type UserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+// Provider выполняет authorization code flow с PKCE для одного провайдера
+type Provider struct {
+	name        string
+	kind        string
+	config      *oauth2.Config
+	userInfoURL string
+	client      *http.Client
+}
+
+// NewProvider создает провайдера. Для OIDC без явно заданных эндпоинтов
+// выполняет запрос к {issuer}/.well-known/openid-configuration.
+func NewProvider(ctx context.Context, cfg ProviderConfig, client *http.Client) (*Provider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	p := &Provider{
+		name:        cfg.Name,
+		kind:        cfg.Kind,
+		userInfoURL: cfg.UserInfoURL,
+		client:      client,
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Scopes:       cfg.Scopes,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  cfg.AuthURL,
+				TokenURL: cfg.TokenURL,
+			},
+		},
+	}
+
+	switch cfg.Kind {
+	case KindGitHub:
+		if p.config.Endpoint.AuthURL == "" {
+			p.config.Endpoint = github.Endpoint
+		}
+		if p.userInfoURL == "" {
+			p.userInfoURL = "https://api.github.com/user"
+		}
+		if len(p.config.Scopes) == 0 {
+			p.config.Scopes = []string{"read:user", "user:email"}
+		}
+	case KindOIDC:
+		if p.config.Endpoint.AuthURL == "" || p.config.Endpoint.TokenURL == "" || p.userInfoURL == "" {
+			if err := p.discover(ctx, cfg.Issuer); err != nil {
+				return nil, err
+			}
+		}
+		if len(p.config.Scopes) == 0 {
+			p.config.Scopes = []string{"openid", "email", "profile"}
+		}
+	default:
+		return nil, fmt.Errorf("unknown oauth provider kind: %s", cfg.Kind)
+	}
+
+	return p, nil
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL возвращает адрес страницы авторизации провайдера
+func (p *Provider) AuthCodeURL(state, verifier string) string {
+	return p.config.AuthCodeURL(state, oauth2.AccessTypeOnline, oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange обменивает код авторизации на токен и загружает данные пользователя
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*UserInfo, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	client := p.config.Client(ctx, token)
+	if p.kind == KindGitHub {
+		return p.githubUserInfo(client)
+	}
+	return p.oidcUserInfo(client)
+}
+
+func (p *Provider) discover(ctx context.Context, issuer string) error {
+	if issuer == "" {
+		return errors.New("oidc provider requires an issuer")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return err
+	}
+
+	var doc struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	}
+	if err := p.getJSON(p.client, req, &doc); err != nil {
+		return fmt.Errorf("oidc discovery failed: %w", err)
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return fmt.Errorf("oidc discovery returned issuer %q, expected %q", doc.Issuer, issuer)
+	}
+
+	if p.config.Endpoint.AuthURL == "" {
+		p.config.Endpoint.AuthURL = doc.AuthorizationEndpoint
+	}
+	if p.config.Endpoint.TokenURL == "" {
+		p.config.Endpoint.TokenURL = doc.TokenEndpoint
+	}
+	if p.userInfoURL == "" {
+		p.userInfoURL = doc.UserinfoEndpoint
+	}
+	return nil
+}
+
+func (p *Provider) oidcUserInfo(client *http.Client) (*UserInfo, error) {
+	req, err := http.NewRequest(http.MethodGet, p.userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims struct {
+		Subject       string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+	}
+	if err := p.getJSON(client, req, &claims); err != nil {
+		return nil, fmt.Errorf("failed to load userinfo: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("userinfo response has no subject")
+	}
+
+	return &UserInfo{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		FirstName:     claims.GivenName,
+		LastName:      claims.FamilyName,
+	}, nil
+}
+
+func (p *Provider) githubUserInfo(client *http.Client) (*UserInfo, error) {
+	req, err := http.NewRequest(http.MethodGet, p.userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := p.getJSON(client, req, &user); err != nil {
+		return nil, fmt.Errorf("failed to load github user: %w", err)
+	}
+
+	req, err = http.NewRequest(http.MethodGet, strings.TrimSuffix(p.userInfoURL, "/user")+"/user/emails", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.getJSON(client, req, &emails); err != nil {
+		return nil, fmt.Errorf("failed to load github emails: %w", err)
+	}
+
+	info := &UserInfo{Subject: strconv.FormatInt(user.ID, 10)}
+	for _, e := range emails {
+		if e.Primary {
+			info.Email = e.Email
+			info.EmailVerified = e.Verified
+			break
+		}
+	}
+
+	info.FirstName, info.LastName, _ = strings.Cut(user.Name, " ")
+	if info.FirstName == "" {
+		info.FirstName = user.Login
+	}
+
+	return info, nil
+}
+
+func (p *Provider) getJSON(client *http.Client, req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, req.URL)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,80 @@
+package oauth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/levstremilov/shance-app/internal/oauth"
+	"github.com/levstremilov/shance-app/internal/oauth/oauthtest"
+)
+
+func newTestProvider(t *testing.T, srv *oauthtest.Server) *oauth.Provider {
+	t.Helper()
+
+	provider, err := oauth.NewProvider(context.Background(), oauth.ProviderConfig{
+		Name:        "test",
+		Kind:        oauth.KindOIDC,
+		Issuer:      srv.URL,
+		ClientID:    "client",
+		RedirectURL: "http://app.local/api/auth/oauth/test/callback",
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	return provider
+}
+
+func TestProviderExchange(t *testing.T) {
+	srv := oauthtest.NewServer(t, oauthtest.Claims{
+		Subject:       "42",
+		Email:         "ivan@example.com",
+		EmailVerified: true,
+		GivenName:     "Иван",
+		FamilyName:    "Петров",
+	})
+	provider := newTestProvider(t, srv)
+
+	authURL := provider.AuthCodeURL("state-1", "verifier-verifier-verifier-verifier-verifier")
+	if !strings.HasPrefix(authURL, srv.URL+"/authorize?") {
+		t.Fatalf("auth url %q does not use the discovered endpoint", authURL)
+	}
+
+	code, state := srv.Authorize(t, authURL)
+	if state != "state-1" {
+		t.Fatalf("state = %q, want state-1", state)
+	}
+
+	info, err := provider.Exchange(context.Background(), code, "verifier-verifier-verifier-verifier-verifier")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := oauth.UserInfo{Subject: "42", Email: "ivan@example.com", EmailVerified: true, FirstName: "Иван", LastName: "Петров"}
+	if *info != want {
+		t.Fatalf("user info = %+v, want %+v", *info, want)
+	}
+}
+
+func TestProviderExchangeRejectsWrongVerifier(t *testing.T) {
+	srv := oauthtest.NewServer(t, oauthtest.Claims{Subject: "42"})
+	provider := newTestProvider(t, srv)
+
+	code, _ := srv.Authorize(t, provider.AuthCodeURL("state", "verifier-verifier-verifier-verifier-verifier"))
+	if _, err := provider.Exchange(context.Background(), code, "another-verifier-another-verifier-another"); err == nil {
+		t.Fatal("Exchange succeeded with a verifier that does not match the challenge")
+	}
+}
+
+func TestNewProviderRejectsIssuerMismatch(t *testing.T) {
+	srv := oauthtest.NewServer(t, oauthtest.Claims{})
+	srv.Issuer = "https://evil.example.com"
+
+	_, err := oauth.NewProvider(context.Background(), oauth.ProviderConfig{
+		Name:   "test",
+		Kind:   oauth.KindOIDC,
+		Issuer: srv.URL,
+	}, srv.Client())
+	if err == nil {
+		t.Fatal("NewProvider accepted a discovery document for another issuer")
+	}
+}
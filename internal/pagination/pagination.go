@@ -0,0 +1,72 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort field")
+	// ErrCursorUnsupported возвращается для списков, которые выбираются только по номеру страницы
+	ErrCursorUnsupported = errors.New("cursor pagination is not supported for this list")
+)
+
+// Params описывает запрошенную страницу списка.
+// Если задан Cursor, страница выбирается по курсору, иначе по номеру Page.
+type Params struct {
+	Page     int
+	PageSize int
+	// Cursor — позиция, после которой начинается страница; пустая строка означает первую страницу
+	Cursor *string
+	Sort   string
+	Desc   bool
+}
+
+// UsesCursor сообщает, запрошена ли страница по курсору
+func (p Params) UsesCursor() bool {
+	return p.Cursor != nil
+}
+
+func (p Params) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// Page описывает полученную страницу
+type Page struct {
+	// Total — число записей, подходящих под фильтры
+	Total int64
+	// HasMore — есть ли записи после этой страницы
+	HasMore bool
+	// NextCursor — курсор следующей страницы при выборке по курсору
+	NextCursor string
+}
+
+// Cursor — значение поля сортировки и ID последней записи страницы
+type Cursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(value string) (Cursor, error) {
+	var cursor Cursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return cursor, ErrInvalidCursor
+	}
+	return cursor, nil
+}
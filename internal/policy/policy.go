@@ -0,0 +1,190 @@
+// Package policy описывает роли пользователей и проверку прав доступа.
+package policy
+
+import (
+	"errors"
+
+	"github.com/levstremilov/shance-app/internal/auth"
+)
+
+// Глобальные роли пользователя
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Роли участника проекта
+const (
+	ProjectRoleOwner      = "owner"
+	ProjectRoleMaintainer = "maintainer"
+	ProjectRoleMember     = "member"
+	ProjectRoleViewer     = "viewer"
+)
+
+type Permission string
+
+const (
+	PermProjectCreate        Permission = "project:create"
+	PermProjectUpdate        Permission = "project:update"
+	PermProjectDelete        Permission = "project:delete"
+	PermProjectStatusManage  Permission = "project:status:manage"
+	PermProjectMembersRead   Permission = "project:members:read"
+	PermProjectMembersManage Permission = "project:members:manage"
+	PermVacancyManage        Permission = "vacancy:manage"
+	PermTagManage            Permission = "tag:manage"
+	PermTechnologyManage     Permission = "technology:manage"
+	PermUserRolesManage      Permission = "user:roles:manage"
+	PermUserImpersonate      Permission = "user:impersonate"
+)
+
+// Причины отказа в доступе
+const (
+	ReasonUnauthenticated       = "unauthenticated"
+	ReasonRoleNotAllowed        = "role_not_allowed"
+	ReasonNotProjectMember      = "not_project_member"
+	ReasonProjectRoleNotAllowed = "project_role_not_allowed"
+	ReasonImpersonation         = "impersonation_not_allowed"
+)
+
+var ErrProjectNotFound = errors.New("project not found")
+
+// Права глобальных ролей действуют в том числе для любого проекта
+var globalPermissions = map[string][]Permission{
+	RoleUser: {
+		PermProjectCreate,
+	},
+	RoleModerator: {
+		PermProjectCreate,
+		PermProjectDelete,
+		PermProjectMembersRead,
+		PermTagManage,
+		PermTechnologyManage,
+	},
+	RoleAdmin: {
+		PermProjectCreate,
+		PermProjectUpdate,
+		PermProjectDelete,
+		PermProjectStatusManage,
+		PermProjectMembersRead,
+		PermProjectMembersManage,
+		PermVacancyManage,
+		PermTagManage,
+		PermTechnologyManage,
+		PermUserRolesManage,
+		PermUserImpersonate,
+	},
+}
+
+var projectPermissions = map[string][]Permission{
+	ProjectRoleOwner: {
+		PermProjectUpdate,
+		PermProjectDelete,
+		PermProjectStatusManage,
+		PermProjectMembersRead,
+		PermProjectMembersManage,
+		PermVacancyManage,
+	},
+	ProjectRoleMaintainer: {
+		PermProjectUpdate,
+		PermProjectMembersRead,
+		PermProjectMembersManage,
+		PermVacancyManage,
+	},
+	ProjectRoleMember: {
+		PermProjectMembersRead,
+	},
+	ProjectRoleViewer: {
+		PermProjectMembersRead,
+	},
+}
+
+// Decision — результат проверки прав. Reason заполняется при отказе.
+type Decision struct {
+	Allowed    bool
+	Reason     string
+	Permission Permission
+}
+
+// ProjectRoleResolver возвращает роль пользователя в проекте или пустую строку,
+// если пользователь не участвует в проекте. Для несуществующего проекта
+// возвращается ErrProjectNotFound.
+type ProjectRoleResolver interface {
+	ProjectRole(projectID, userID uint) (string, error)
+}
+
+type Engine struct {
+	projects ProjectRoleResolver
+}
+
+func NewEngine(projects ProjectRoleResolver) *Engine {
+	return &Engine{
+		projects: projects,
+	}
+}
+
+// Authorize проверяет право, выдаваемое глобальной ролью
+func (e *Engine) Authorize(principal *auth.Principal, permission Permission) Decision {
+	if principal == nil {
+		return deny(permission, ReasonUnauthenticated)
+	}
+	if !hasPermission(globalPermissions[GlobalRole(principal.Role)], permission) {
+		return deny(permission, ReasonRoleNotAllowed)
+	}
+	return Decision{Allowed: true, Permission: permission}
+}
+
+// AuthorizeProject проверяет право в проекте: по глобальной роли или по роли в проекте
+func (e *Engine) AuthorizeProject(principal *auth.Principal, projectID uint, permission Permission) (Decision, error) {
+	if principal == nil {
+		return deny(permission, ReasonUnauthenticated), nil
+	}
+
+	role, err := e.projects.ProjectRole(projectID, principal.UserID)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	if hasPermission(globalPermissions[GlobalRole(principal.Role)], permission) {
+		return Decision{Allowed: true, Permission: permission}, nil
+	}
+	if role == "" {
+		return deny(permission, ReasonNotProjectMember), nil
+	}
+	if !hasPermission(projectPermissions[role], permission) {
+		return deny(permission, ReasonProjectRoleNotAllowed), nil
+	}
+	return Decision{Allowed: true, Permission: permission}, nil
+}
+
+// GlobalRole приводит роль к одной из известных. Пустая и неизвестная роль
+// считаются ролью user: роль из базы или токена не дает прав сверх известных.
+func GlobalRole(role string) string {
+	if !IsGlobalRole(role) {
+		return RoleUser
+	}
+	return role
+}
+
+func IsGlobalRole(role string) bool {
+	_, ok := globalPermissions[role]
+	return ok
+}
+
+func IsProjectRole(role string) bool {
+	_, ok := projectPermissions[role]
+	return ok
+}
+
+func deny(permission Permission, reason string) Decision {
+	return Decision{Permission: permission, Reason: reason}
+}
+
+func hasPermission(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
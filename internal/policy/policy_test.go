@@ -0,0 +1,35 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/levstremilov/shance-app/internal/auth"
+)
+
+func TestGlobalRole(t *testing.T) {
+	cases := map[string]string{
+		"":          RoleUser,
+		RoleUser:    RoleUser,
+		RoleAdmin:   RoleAdmin,
+		"moderator": RoleModerator,
+		"superuser": RoleUser,
+		"Admin":     RoleUser,
+	}
+	for role, want := range cases {
+		if got := GlobalRole(role); got != want {
+			t.Errorf("GlobalRole(%q) = %q, want %q", role, got, want)
+		}
+	}
+}
+
+func TestAuthorizeUnknownRoleHasUserPermissions(t *testing.T) {
+	engine := NewEngine(nil)
+	principal := &auth.Principal{UserID: 1, Role: "superuser"}
+
+	if d := engine.Authorize(principal, PermProjectCreate); !d.Allowed {
+		t.Fatalf("unknown role lost user permission: %+v", d)
+	}
+	if d := engine.Authorize(principal, PermUserRolesManage); d.Allowed || d.Reason != ReasonRoleNotAllowed {
+		t.Fatalf("unknown role got admin permission: %+v", d)
+	}
+}
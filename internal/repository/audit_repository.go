@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+func (r *JobRepository) Create(job *models.Job) error {
+	return r.db.Create(job).Error
+}
+
+func (r *JobRepository) GetByUserAndID(userID, id uint) (*models.Job, error) {
+	var job models.Job
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindActive возвращает последнюю ожидающую или выполняющуюся задачу пользователя указанного типа
+func (r *JobRepository) FindActive(userID uint, jobType string) (*models.Job, error) {
+	var job models.Job
+	err := r.db.Where("user_id = ? AND type = ? AND status IN ?", userID, jobType, []string{models.JobStatusPending, models.JobStatusRunning}).
+		Order("id DESC").
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListByStatus возвращает задачи пользователя указанного типа и статуса
+func (r *JobRepository) ListByStatus(userID uint, jobType, status string) ([]models.Job, error) {
+	var jobs []models.Job
+	if err := r.db.Where("user_id = ? AND type = ? AND status = ?", userID, jobType, status).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ListExpiredResults возвращает завершенные задачи типа jobType, закончившиеся
+// раньше before, у которых еще сохранен результат
+func (r *JobRepository) ListExpiredResults(jobType string, before time.Time) ([]models.Job, error) {
+	var jobs []models.Job
+	err := r.db.Where("type = ? AND status = ? AND finished_at < ? AND result <> ''", jobType, models.JobStatusCompleted, before).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ClearResult удаляет результат задачи, например путь к удаленному архиву
+func (r *JobRepository) ClearResult(id uint) error {
+	return r.db.Model(&models.Job{}).Where("id = ?", id).Update("result", "").Error
+}
+
+// Claim забирает одну готовую к запуску задачу из types и помечает ее выполняющейся.
+// Задачи, выполнение которых началось раньше staleBefore, считаются брошенными
+// и запускаются снова. Возвращает nil, если задач нет.
+func (r *JobRepository) Claim(types []string, now, staleBefore time.Time) (*models.Job, error) {
+	var job models.Job
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("type IN ?", types).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND started_at < ?)",
+				models.JobStatusPending, now, models.JobStatusRunning, staleBefore).
+			Order("run_at").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		job.Status = models.JobStatusRunning
+		job.StartedAt = &now
+		job.Attempts++
+		return tx.Save(&job).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *JobRepository) Complete(id uint, result string, now time.Time) error {
+	return r.db.Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      models.JobStatusCompleted,
+			"result":      result,
+			"error":       "",
+			"finished_at": now,
+		}).Error
+}
+
+// Retry возвращает задачу в очередь после ошибки
+func (r *JobRepository) Retry(id uint, message string, runAt time.Time) error {
+	return r.db.Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobStatusRunning).
+		Updates(map[string]interface{}{
+			"status": models.JobStatusPending,
+			"error":  message,
+			"run_at": runAt,
+		}).Error
+}
+
+func (r *JobRepository) Fail(id uint, message string, now time.Time) error {
+	return r.db.Model(&models.Job{}).
+		Where("id = ? AND status = ?", id, models.JobStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      models.JobStatusFailed,
+			"error":       message,
+			"finished_at": now,
+		}).Error
+}
+
+// CancelPending отменяет еще не начатые задачи пользователя указанного типа
+func (r *JobRepository) CancelPending(userID uint, jobType string, now time.Time) error {
+	return r.db.Model(&models.Job{}).
+		Where("user_id = ? AND type = ? AND status = ?", userID, jobType, models.JobStatusPending).
+		Updates(map[string]interface{}{
+			"status":      models.JobStatusCancelled,
+			"finished_at": now,
+		}).Error
+}
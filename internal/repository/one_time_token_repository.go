@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type OneTimeTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewOneTimeTokenRepository(db *gorm.DB) *OneTimeTokenRepository {
+	return &OneTimeTokenRepository{db: db}
+}
+
+func (r *OneTimeTokenRepository) Create(token *models.OneTimeToken) error {
+	return r.db.Create(token).Error
+}
+
+// Consume помечает токен использованным и возвращает его.
+// Возвращает gorm.ErrRecordNotFound, если токен не найден, истек или уже использован.
+func (r *OneTimeTokenRepository) Consume(purpose, hash string) (*models.OneTimeToken, error) {
+	var token models.OneTimeToken
+	if err := r.db.Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := r.db.Model(&models.OneTimeToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", token.ID, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	token.UsedAt = &now
+	return &token, nil
+}
+
+// InvalidateForUser помечает использованными все активные токены пользователя с указанным назначением
+func (r *OneTimeTokenRepository) InvalidateForUser(userID uint, purpose string) error {
+	return r.db.Model(&models.OneTimeToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/pagination"
+
+	"gorm.io/gorm"
+)
+
+type sortKind int
+
+const (
+	sortTime sortKind = iota
+	sortString
+	sortInt
+)
+
+// sortField описывает поле, по которому можно сортировать список
+type sortField struct {
+	expr string
+	kind sortKind
+}
+
+// parse восстанавливает значение поля из курсора
+func (f sortField) parse(value string) (interface{}, error) {
+	switch f.kind {
+	case sortTime:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+		return t, nil
+	case sortInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, pagination.ErrInvalidCursor
+		}
+		return n, nil
+	default:
+		return value, nil
+	}
+}
+
+func formatTimeCursor(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func formatIntCursor(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+// whereCreatedBetween ограничивает выборку по времени создания записи
+func whereCreatedBetween(query *gorm.DB, column string, from, to *time.Time) *gorm.DB {
+	if from != nil {
+		query = query.Where(column+" >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where(column+" < ?", *to)
+	}
+	return query
+}
+
+// fetchPage выбирает страницу записей query, отсортированных по полю params.Sort и ID.
+// prepare добавляет к выборке то, что не нужно для подсчета записей: Select, Preload.
+// cursorOf возвращает курсор записи для перехода к следующей странице.
+func fetchPage[T any](
+	query *gorm.DB,
+	table string,
+	params pagination.Params,
+	fields map[string]sortField,
+	prepare func(*gorm.DB) *gorm.DB,
+	cursorOf func(item *T, sort string) pagination.Cursor,
+) ([]T, pagination.Page, error) {
+	var page pagination.Page
+
+	field, ok := fields[params.Sort]
+	if !ok {
+		return nil, page, pagination.ErrInvalidSort
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return nil, page, err
+	}
+
+	direction, comparison := "ASC", ">"
+	if params.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	q := query.Session(&gorm.Session{})
+	if prepare != nil {
+		q = prepare(q)
+	}
+	q = q.Order(fmt.Sprintf("%s %s, %s.id %s", field.expr, direction, table, direction))
+
+	if params.UsesCursor() {
+		if *params.Cursor != "" {
+			cursor, err := pagination.DecodeCursor(*params.Cursor)
+			if err != nil {
+				return nil, page, err
+			}
+			value, err := field.parse(cursor.Value)
+			if err != nil {
+				return nil, page, err
+			}
+			q = q.Where(fmt.Sprintf("(%s, %s.id) %s (?, ?)", field.expr, table, comparison), value, cursor.ID)
+		}
+	} else {
+		q = q.Offset(params.Offset())
+	}
+
+	// Лишняя запись показывает, есть ли следующая страница
+	var items []T
+	if err := q.Limit(params.PageSize + 1).Find(&items).Error; err != nil {
+		return nil, page, err
+	}
+	if len(items) > params.PageSize {
+		page.HasMore = true
+		items = items[:params.PageSize]
+	}
+
+	if params.UsesCursor() && page.HasMore {
+		page.NextCursor = pagination.EncodeCursor(cursorOf(&items[len(items)-1], params.Sort))
+	}
+	return items, page, nil
+}
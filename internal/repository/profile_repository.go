@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ProfileRepository хранит аватары и портфолио пользователей
+type ProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewProfileRepository(db *gorm.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+func (r *ProfileRepository) GetAvatar(userID uint) (*models.UserAvatar, error) {
+	var avatar models.UserAvatar
+	if err := r.db.Preload("Variants").Where("user_id = ?", userID).First(&avatar).Error; err != nil {
+		return nil, err
+	}
+	return &avatar, nil
+}
+
+// ReplaceAvatar сохраняет новый аватар пользователя вместо прежнего и возвращает
+// прежний аватар, чтобы удалить его файлы, или nil, если аватара не было
+func (r *ProfileRepository) ReplaceAvatar(avatar *models.UserAvatar) (*models.UserAvatar, error) {
+	var previous *models.UserAvatar
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Блокировка строки пользователя упорядочивает одновременные загрузки
+		if err := tx.Exec("SELECT id FROM users WHERE id = ? FOR UPDATE", avatar.UserID).Error; err != nil {
+			return err
+		}
+		var existing models.UserAvatar
+		err := tx.Preload("Variants").Where("user_id = ?", avatar.UserID).Limit(1).Find(&existing).Error
+		if err != nil {
+			return err
+		}
+		if existing.ID != 0 {
+			if err := deleteAvatar(tx, &existing); err != nil {
+				return err
+			}
+			previous = &existing
+		}
+		return tx.Create(avatar).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// DeleteAvatar удаляет запись об аватаре и его вариантах без возможности восстановления
+func (r *ProfileRepository) DeleteAvatar(avatar *models.UserAvatar) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return deleteAvatar(tx, avatar)
+	})
+}
+
+func deleteAvatar(tx *gorm.DB, avatar *models.UserAvatar) error {
+	if err := tx.Where("avatar_id = ?", avatar.ID).Delete(&models.UserAvatarVariant{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(avatar).Error
+}
+
+func (r *ProfileRepository) ListLinks(userID uint) ([]models.PortfolioLink, error) {
+	var links []models.PortfolioLink
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *ProfileRepository) CountLinks(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.PortfolioLink{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *ProfileRepository) GetLink(userID, id uint) (*models.PortfolioLink, error) {
+	var link models.PortfolioLink
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ProfileRepository) CreateLink(link *models.PortfolioLink) error {
+	return r.db.Create(link).Error
+}
+
+func (r *ProfileRepository) UpdateLink(link *models.PortfolioLink) error {
+	return r.db.Save(link).Error
+}
+
+func (r *ProfileRepository) DeleteLink(link *models.PortfolioLink) error {
+	return r.db.Unscoped().Delete(link).Error
+}
+
+func (r *ProfileRepository) ListFiles(userID uint) ([]models.PortfolioFile, error) {
+	var files []models.PortfolioFile
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&files).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (r *ProfileRepository) CountFiles(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.PortfolioFile{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *ProfileRepository) GetFile(userID, id uint) (*models.PortfolioFile, error) {
+	var file models.PortfolioFile
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&file).Error; err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (r *ProfileRepository) CreateFile(file *models.PortfolioFile) error {
+	return r.db.Create(file).Error
+}
+
+// DeleteFile удаляет запись о файле без возможности восстановления:
+// файл в хранилище удаляется вместе с ней
+func (r *ProfileRepository) DeleteFile(file *models.PortfolioFile) error {
+	return r.db.Unscoped().Delete(file).Error
+}
+
+// DeleteAll удаляет аватар и портфолио пользователя
+func (r *ProfileRepository) DeleteAll(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("avatar_id IN (?)", tx.Unscoped().Model(&models.UserAvatar{}).Select("id").Where("user_id = ?", userID)).
+			Delete(&models.UserAvatarVariant{}).Error
+		if err != nil {
+			return err
+		}
+		for _, model := range []interface{}{
+			&models.UserAvatar{},
+			&models.PortfolioLink{},
+			&models.PortfolioFile{},
+		} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// orderedByID упорядочивает записи портфолио при предварительной загрузке
+func orderedByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
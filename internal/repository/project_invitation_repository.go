@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ProjectInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectInvitationRepository(db *gorm.DB) *ProjectInvitationRepository {
+	return &ProjectInvitationRepository{db: db}
+}
+
+func (r *ProjectInvitationRepository) Create(invitation *models.ProjectInvitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *ProjectInvitationRepository) GetByID(id uint) (*models.ProjectInvitation, error) {
+	var invitation models.ProjectInvitation
+	if err := r.db.Preload("Project").Preload("Inviter").First(&invitation, id).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetPending возвращает ожидающее приглашение в проект на адрес email
+func (r *ProjectInvitationRepository) GetPending(projectID uint, email string) (*models.ProjectInvitation, error) {
+	var invitation models.ProjectInvitation
+	err := r.db.Where("project_id = ? AND email = ? AND status = ?", projectID, email, models.InvitationStatusPending).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// Reissue обновляет роль, автора и ссылку ожидающего приглашения
+func (r *ProjectInvitationRepository) Reissue(invitation *models.ProjectInvitation) error {
+	return r.db.Model(invitation).
+		Select("role", "inviter_id", "invitee_id", "token_id", "expires_at").
+		Updates(invitation).Error
+}
+
+// ListByProject возвращает приглашения в проект, начиная с последних
+func (r *ProjectInvitationRepository) ListByProject(projectID uint) ([]models.ProjectInvitation, error) {
+	var invitations []models.ProjectInvitation
+	if err := r.db.Preload("Inviter").Where("project_id = ?", projectID).Order("id DESC").Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// ListPendingForUser возвращает действующие приглашения, адресованные пользователю
+func (r *ProjectInvitationRepository) ListPendingForUser(userID uint, now time.Time) ([]models.ProjectInvitation, error) {
+	var invitations []models.ProjectInvitation
+	err := r.db.Preload("Project").Preload("Inviter").
+		Where("invitee_id = ? AND status = ? AND expires_at > ?", userID, models.InvitationStatusPending, now).
+		Order("id DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// ExpireStale помечает истекшими ожидающие приглашения в проект со сроком до now
+func (r *ProjectInvitationRepository) ExpireStale(projectID uint, now time.Time) error {
+	return r.db.Model(&models.ProjectInvitation{}).
+		Where("project_id = ? AND status = ? AND expires_at <= ?", projectID, models.InvitationStatusPending, now).
+		Update("status", models.InvitationStatusExpired).Error
+}
+
+// Resolve переводит ожидающее приглашение в состояние status. Если приглашение
+// уже не ожидает ответа, возвращается gorm.ErrRecordNotFound.
+func (r *ProjectInvitationRepository) Resolve(invitation *models.ProjectInvitation, status string, now time.Time) error {
+	result := r.db.Model(&models.ProjectInvitation{}).
+		Where("id = ? AND status = ?", invitation.ID, models.InvitationStatusPending).
+		Updates(map[string]interface{}{"status": status, "responded_at": now})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	invitation.Status = status
+	invitation.RespondedAt = &now
+	return nil
+}
+
+// Accept принимает приглашение от имени пользователя userID и добавляет его в участники
+// проекта, если он еще не участник. Если приглашение уже не ожидает ответа или истекло,
+// возвращается gorm.ErrRecordNotFound.
+func (r *ProjectInvitationRepository) Accept(invitation *models.ProjectInvitation, userID uint, now time.Time) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ProjectInvitation{}).
+			Where("id = ? AND status = ? AND expires_at > ?", invitation.ID, models.InvitationStatusPending, now).
+			Updates(map[string]interface{}{
+				"status":       models.InvitationStatusAccepted,
+				"invitee_id":   userID,
+				"responded_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		err := tx.Where("project_id = ? AND user_id = ?", invitation.ProjectID, userID).
+			Limit(1).
+			Find(&member).Error
+		if err != nil {
+			return err
+		}
+		if member.ID == 0 {
+			member = models.ProjectMember{
+				ProjectID: invitation.ProjectID,
+				UserID:    userID,
+				Role:      invitation.Role,
+				JoinedAt:  now,
+			}
+			if err := tx.Create(&member).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Preload("User").First(&member, member.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	invitation.Status = models.InvitationStatusAccepted
+	invitation.InviteeID = &userID
+	invitation.RespondedAt = &now
+	return &member, nil
+}
+
+// LinkInvitee связывает ожидающие приглашения на адрес email с аккаунтом userID
+func (r *ProjectInvitationRepository) LinkInvitee(email string, userID uint) error {
+	return r.db.Model(&models.ProjectInvitation{}).
+		Where("email = lower(?) AND status = ? AND invitee_id IS NULL", email, models.InvitationStatusPending).
+		Update("invitee_id", userID).Error
+}
+
+// IsMember сообщает, участвует ли в проекте пользователь с адресом email
+func (r *ProjectInvitationRepository) IsMember(projectID uint, email string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ProjectMember{}).
+		Joins("JOIN users ON users.id = project_members.user_id").
+		Where("project_members.project_id = ? AND lower(users.email) = lower(?)", projectID, email).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
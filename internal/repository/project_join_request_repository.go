@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ProjectJoinRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectJoinRequestRepository(db *gorm.DB) *ProjectJoinRequestRepository {
+	return &ProjectJoinRequestRepository{db: db}
+}
+
+func (r *ProjectJoinRequestRepository) Create(request *models.ProjectJoinRequest) error {
+	return r.db.Create(request).Error
+}
+
+func (r *ProjectJoinRequestRepository) GetByID(id uint) (*models.ProjectJoinRequest, error) {
+	var request models.ProjectJoinRequest
+	if err := r.db.Preload("Project").Preload("User").First(&request, id).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// HasPending сообщает, есть ли у пользователя ожидающая заявка в проект
+func (r *ProjectJoinRequestRepository) HasPending(projectID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ProjectJoinRequest{}).
+		Where("project_id = ? AND user_id = ? AND status = ?", projectID, userID, models.JoinRequestStatusPending).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListByProject возвращает заявки в проект, начиная с первых поданных, чтобы
+// рассматривать их по очереди. Пустой status означает заявки во всех состояниях.
+func (r *ProjectJoinRequestRepository) ListByProject(projectID uint, status string) ([]models.ProjectJoinRequest, error) {
+	var requests []models.ProjectJoinRequest
+	query := r.db.Preload("User").Where("project_id = ?", projectID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("id ASC").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ListByUser возвращает заявки пользователя, начиная с последних
+func (r *ProjectJoinRequestRepository) ListByUser(userID uint) ([]models.ProjectJoinRequest, error) {
+	var requests []models.ProjectJoinRequest
+	if err := r.db.Preload("Project").Where("user_id = ?", userID).Order("id DESC").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// Reject отклоняет ожидающую заявку с причиной reason. Если заявка уже
+// рассмотрена или отозвана, возвращается gorm.ErrRecordNotFound.
+func (r *ProjectJoinRequestRepository) Reject(request *models.ProjectJoinRequest, reviewerID uint, reason string, now time.Time) error {
+	result := r.db.Model(&models.ProjectJoinRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.JoinRequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":      models.JoinRequestStatusRejected,
+			"reviewer_id": reviewerID,
+			"reason":      reason,
+			"reviewed_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	request.Status = models.JoinRequestStatusRejected
+	request.ReviewerID = &reviewerID
+	request.Reason = reason
+	request.ReviewedAt = &now
+	return nil
+}
+
+// Withdraw отзывает ожидающую заявку. Если заявка уже рассмотрена или
+// отозвана, возвращается gorm.ErrRecordNotFound.
+func (r *ProjectJoinRequestRepository) Withdraw(request *models.ProjectJoinRequest) error {
+	result := r.db.Model(&models.ProjectJoinRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.JoinRequestStatusPending).
+		Update("status", models.JoinRequestStatusWithdrawn)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	request.Status = models.JoinRequestStatusWithdrawn
+	return nil
+}
+
+// Approve одобряет ожидающую заявку и добавляет ее автора в участники проекта
+// с ролью role, если он еще не участник. Если заявка уже рассмотрена или
+// отозвана, возвращается gorm.ErrRecordNotFound.
+func (r *ProjectJoinRequestRepository) Approve(request *models.ProjectJoinRequest, reviewerID uint, role string, now time.Time) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ProjectJoinRequest{}).
+			Where("id = ? AND status = ?", request.ID, models.JoinRequestStatusPending).
+			Updates(map[string]interface{}{
+				"status":      models.JoinRequestStatusApproved,
+				"reviewer_id": reviewerID,
+				"reviewed_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		err := tx.Where("project_id = ? AND user_id = ?", request.ProjectID, request.UserID).
+			Limit(1).
+			Find(&member).Error
+		if err != nil {
+			return err
+		}
+		if member.ID == 0 {
+			member = models.ProjectMember{
+				ProjectID: request.ProjectID,
+				UserID:    request.UserID,
+				Role:      role,
+				JoinedAt:  now,
+			}
+			if err := tx.Create(&member).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Preload("User").First(&member, member.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	request.Status = models.JoinRequestStatusApproved
+	request.ReviewerID = &reviewerID
+	request.ReviewedAt = &now
+	return &member, nil
+}
+
+// WithdrawAllForUser отзывает все ожидающие заявки пользователя
+func (r *ProjectJoinRequestRepository) WithdrawAllForUser(userID uint) error {
+	return r.db.Model(&models.ProjectJoinRequest{}).
+		Where("user_id = ? AND status = ?", userID, models.JoinRequestStatusPending).
+		Update("status", models.JoinRequestStatusWithdrawn).Error
+}
+
+// WithTx возвращает репозиторий, работающий в рамках транзакции tx.
+func (r *ProjectJoinRequestRepository) WithTx(tx *gorm.DB) *ProjectJoinRequestRepository {
+	return &ProjectJoinRequestRepository{db: tx}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ProjectPhotoRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectPhotoRepository(db *gorm.DB) *ProjectPhotoRepository {
+	return &ProjectPhotoRepository{db: db}
+}
+
+// Create добавляет фотографию вместе с ее вариантами в конец списка фотографий проекта
+func (r *ProjectPhotoRepository) Create(photo *models.ProjectPhoto) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Блокировка строки проекта упорядочивает одновременные загрузки
+		if err := tx.Exec("SELECT id FROM projects WHERE id = ? FOR UPDATE", photo.ProjectID).Error; err != nil {
+			return err
+		}
+		var position int
+		err := tx.Model(&models.ProjectPhoto{}).
+			Where("project_id = ?", photo.ProjectID).
+			Select("COALESCE(MAX(position) + 1, 0)").
+			Scan(&position).Error
+		if err != nil {
+			return err
+		}
+		photo.Position = position
+		return tx.Create(photo).Error
+	})
+}
+
+func (r *ProjectPhotoRepository) GetByProjectAndID(projectID, id uint) (*models.ProjectPhoto, error) {
+	var photo models.ProjectPhoto
+	if err := r.db.Preload("Variants").Where("id = ? AND project_id = ?", id, projectID).First(&photo).Error; err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// ListByProject возвращает фотографии проекта в заданном порядке
+func (r *ProjectPhotoRepository) ListByProject(projectID uint) ([]models.ProjectPhoto, error) {
+	var photos []models.ProjectPhoto
+	if err := r.db.Preload("Variants").Where("project_id = ?", projectID).Order("position, id").Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func (r *ProjectPhotoRepository) CountByProject(projectID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.ProjectPhoto{}).Where("project_id = ?", projectID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Reorder задает фотографиям проекта позиции в порядке ids
+func (r *ProjectPhotoRepository) Reorder(projectID uint, ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			err := tx.Model(&models.ProjectPhoto{}).
+				Where("id = ? AND project_id = ?", id, projectID).
+				UpdateColumn("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete удаляет запись о фотографии и ее вариантах без возможности восстановления:
+// файлы в хранилище удаляются вместе с ними
+func (r *ProjectPhotoRepository) Delete(photo *models.ProjectPhoto) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("photo_id = ?", photo.ID).Delete(&models.ProjectPhotoVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(photo).Error
+	})
+}
+
+// orderedPhotos упорядочивает фотографии при предварительной загрузке
+func orderedPhotos(db *gorm.DB) *gorm.DB {
+	return db.Order("project_photos.position, project_photos.id")
+}
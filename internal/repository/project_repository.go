@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
 
 	"gorm.io/gorm"
 )
@@ -18,38 +22,230 @@ func (r *ProjectRepository) Create(project *models.Project) error {
 	return r.db.Create(project).Error
 }
 
+// CreateWithOwner создает проект и добавляет автора в участники с ролью ownerRole
+func (r *ProjectRepository) CreateWithOwner(project *models.Project, ownerRole string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(project).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ProjectMember{
+			ProjectID: project.ID,
+			UserID:    project.UserID,
+			Role:      ownerRole,
+			JoinedAt:  project.CreatedAt,
+		}).Error
+	})
+}
+
 func (r *ProjectRepository) GetByID(id uint) (*models.Project, error) {
 	var project models.Project
-	if err := r.db.Preload("Tags").Preload("User").First(&project, id).Error; err != nil {
+	if err := r.db.Preload("Tags").Preload("User").Preload("Photos", orderedPhotos).Preload("Photos.Variants").First(&project, id).Error; err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetVisible возвращает проект, если пользователь viewerID может открыть его по ссылке.
+// Недоступный проект не отличается от несуществующего: возвращается gorm.ErrRecordNotFound.
+func (r *ProjectRepository) GetVisible(id, viewerID uint) (*models.Project, error) {
+	var project models.Project
+	access, args := projectAccess(viewerID, linkedVisibilities)
+	err := r.db.Preload("Tags").Preload("User").Preload("Photos", orderedPhotos).Preload("Photos.Variants").
+		Where(access, args...).
+		First(&project, id).Error
+	if err != nil {
 		return nil, err
 	}
 	return &project, nil
 }
 
+// IsVisible сообщает, может ли пользователь viewerID открыть проект по ссылке
+func (r *ProjectRepository) IsVisible(id, viewerID uint) (bool, error) {
+	var count int64
+	access, args := projectAccess(viewerID, linkedVisibilities)
+	if err := r.db.Model(&models.Project{}).Where("projects.id = ?", id).Where(access, args...).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Update сохраняет редактируемые поля проекта; пустой уровень доступа не меняется.
+// Статус и даты меняются только переходами между статусами (см. Transition).
 func (r *ProjectRepository) Update(project *models.Project) error {
-	return r.db.Save(project).Error
+	fields := []interface{}{"title", "subtitle", "description", "photo"}
+	if project.Visibility != "" {
+		fields = append(fields, "visibility")
+	}
+	if project.AcceptsJoinRequests != nil {
+		fields = append(fields, "accepts_join_requests")
+	}
+	return r.db.Model(project).Select("name", fields...).Updates(project).Error
 }
 
 func (r *ProjectRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Project{}, id).Error
 }
 
+// List возвращает все открытые проекты
 func (r *ProjectRepository) List() ([]models.Project, error) {
 	var projects []models.Project
-	if err := r.db.Preload("Tags").Preload("User").Find(&projects).Error; err != nil {
+	if err := r.db.Where("visibility = ?", models.ProjectVisibilityPublic).Preload("Tags").Preload("User").Preload("Photos", orderedPhotos).Preload("Photos.Variants").Find(&projects).Error; err != nil {
 		return nil, err
 	}
 	return projects, nil
 }
 
-func (r *ProjectRepository) Search(query string) ([]models.Project, error) {
+// Уровни доступа проектов, которые показываются в списках и поиске
+// и которые открываются по ссылке всем пользователям
+var (
+	listedVisibilities = []string{models.ProjectVisibilityPublic}
+	linkedVisibilities = []string{models.ProjectVisibilityPublic, models.ProjectVisibilityUnlisted}
+)
+
+// projectAccess возвращает условие на строку projects: проект с уровнем доступа
+// из visibilities доступен всем, остальные — только автору и участникам проекта.
+// viewerID 0 означает анонимного посетителя.
+func projectAccess(viewerID uint, visibilities []string) (string, []interface{}) {
+	if viewerID == 0 {
+		return "projects.visibility IN ?", []interface{}{visibilities}
+	}
+	return "(projects.visibility IN ? OR projects.user_id = ? OR EXISTS (SELECT 1 FROM project_members " +
+			"WHERE project_members.project_id = projects.id AND project_members.user_id = ? AND project_members.deleted_at IS NULL))",
+		[]interface{}{visibilities, viewerID, viewerID}
+}
+
+// ProjectFilter — условия выборки списка проектов
+type ProjectFilter struct {
+	// ViewerID — пользователь, которому показывается список; 0 — анонимный посетитель.
+	// Проекты по ссылке и закрытые проекты попадают в список только для их участников.
+	ViewerID uint
+	Status   string
+	// IncludeDrafts включает черновики, которые по умолчанию скрыты из списков
+	IncludeDrafts bool
+	Tag           string
+	OwnerID       uint
+	OwnerCountry  string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+}
+
+const projectPopularity = "(SELECT COUNT(*) FROM project_members WHERE project_members.project_id = projects.id AND project_members.deleted_at IS NULL)"
+
+var projectSortFields = map[string]sortField{
+	"created_at": {expr: "projects.created_at", kind: sortTime},
+	"name":       {expr: "projects.name", kind: sortString},
+	"popularity": {expr: projectPopularity, kind: sortInt},
+}
+
+// filtered возвращает выборку проектов, подходящих под filter
+func (r *ProjectRepository) filtered(filter ProjectFilter) *gorm.DB {
+	access, args := projectAccess(filter.ViewerID, listedVisibilities)
+	query := r.db.Model(&models.Project{}).Where(access, args...)
+	if !filter.IncludeDrafts {
+		query = query.Where("projects.status <> ?", models.ProjectStatusDraft)
+	}
+	if filter.Status != "" {
+		query = query.Where("projects.status = ?", filter.Status)
+	}
+	if filter.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM project_tags JOIN tags ON tags.id = project_tags.tag_id "+
+			"WHERE project_tags.project_id = projects.id AND project_tags.deleted_at IS NULL AND tags.deleted_at IS NULL AND tags.name = ?)", filter.Tag)
+	}
+	if filter.OwnerID != 0 {
+		query = query.Where("projects.user_id = ?", filter.OwnerID)
+	}
+	if filter.OwnerCountry != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM users WHERE users.id = projects.user_id AND users.country ILIKE ?)", filter.OwnerCountry)
+	}
+	return whereCreatedBetween(query, "projects.created_at", filter.CreatedFrom, filter.CreatedTo)
+}
+
+// ListPage возвращает страницу проектов, подходящих под filter
+func (r *ProjectRepository) ListPage(filter ProjectFilter, params pagination.Params) ([]models.Project, pagination.Page, error) {
+	return fetchPage(r.filtered(filter), "projects", params, projectSortFields,
+		func(q *gorm.DB) *gorm.DB {
+			return q.Select("projects.*, "+projectPopularity+" AS popularity").Preload("Tags").Preload("User").Preload("Photos", orderedPhotos).Preload("Photos.Variants")
+		},
+		func(p *models.Project, sort string) pagination.Cursor {
+			cursor := pagination.Cursor{ID: p.ID}
+			switch sort {
+			case "name":
+				cursor.Value = p.Name
+			case "popularity":
+				cursor.Value = formatIntCursor(p.Popularity)
+			default:
+				cursor.Value = formatTimeCursor(p.CreatedAt)
+			}
+			return cursor
+		},
+	)
+}
+
+// ProjectSearchResult — найденный проект, его релевантность и фрагмент описания
+type ProjectSearchResult struct {
+	Project  models.Project
+	Rank     float64
+	Headline string
+}
+
+var projectSearch = fullTextSpec("projects", "concat_ws(' ', projects.subtitle, projects.description)")
+
+// Search ищет проекты, подходящие под filter, по названию, заголовкам, тегам
+// и описанию с учетом словоформ
+func (r *ProjectRepository) Search(query string, filter ProjectFilter, params pagination.Params) ([]ProjectSearchResult, pagination.Page, error) {
+	hits, page, err := searchPage(r.filtered(filter), projectSearch, query, params)
+	if err != nil {
+		return nil, page, err
+	}
+
 	var projects []models.Project
-	if err := r.db.Where("name ILIKE ? OR description ILIKE ?", "%"+query+"%", "%"+query+"%").
-		Preload("Tags").Preload("User").
-		Find(&projects).Error; err != nil {
+	if len(hits) > 0 {
+		if err := r.db.Preload("Tags").Preload("User").Preload("Photos", orderedPhotos).Preload("Photos.Variants").Find(&projects, searchHitIDs(hits)).Error; err != nil {
+			return nil, page, err
+		}
+	}
+	byID := make(map[uint]models.Project, len(projects))
+	for _, p := range projects {
+		byID[p.ID] = p
+	}
+
+	results := make([]ProjectSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if project, ok := byID[hit.ID]; ok {
+			results = append(results, ProjectSearchResult{Project: project, Rank: hit.Rank, Headline: hit.Headline})
+		}
+	}
+	return results, page, nil
+}
+
+// ProjectFacets — распределение найденных проектов по тегам, странам авторов и статусам
+type ProjectFacets struct {
+	Tags      []FacetCount
+	Countries []FacetCount
+	Statuses  []FacetCount
+}
+
+// SearchFacets считает фасеты проектов, найденных Search с теми же запросом и фильтром
+func (r *ProjectRepository) SearchFacets(query string, filter ProjectFilter) (*ProjectFacets, error) {
+	matches := searchMatches(r.filtered(filter), projectSearch, query).Select("projects.id")
+
+	var facets ProjectFacets
+	var err error
+	facets.Tags, err = facetCounts(r.db, "SELECT tags.name AS value, COUNT(*) AS count FROM project_tags JOIN tags ON tags.id = project_tags.tag_id "+
+		"WHERE project_tags.project_id IN (?) AND project_tags.deleted_at IS NULL AND tags.deleted_at IS NULL GROUP BY tags.name", matches)
+	if err != nil {
 		return nil, err
 	}
-	return projects, nil
+	facets.Countries, err = facetCounts(r.db, "SELECT users.country AS value, COUNT(*) AS count FROM projects JOIN users ON users.id = projects.user_id "+
+		"WHERE projects.id IN (?) GROUP BY users.country", matches)
+	if err != nil {
+		return nil, err
+	}
+	facets.Statuses, err = facetCounts(r.db, "SELECT projects.status AS value, COUNT(*) AS count FROM projects WHERE projects.id IN (?) GROUP BY projects.status", matches)
+	if err != nil {
+		return nil, err
+	}
+	return &facets, nil
 }
 
 func (r *ProjectRepository) AddMember(projectID, userID uint, role string) error {
@@ -61,11 +257,99 @@ func (r *ProjectRepository) AddMember(projectID, userID uint, role string) error
 	return r.db.Create(&member).Error
 }
 
+// IsMember сообщает, участвует ли пользователь в проекте
+func (r *ProjectRepository) IsMember(projectID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ProjectMember{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *ProjectRepository) RemoveMember(projectID, userID uint) error {
 	return r.db.Where("project_id = ? AND user_id = ?", projectID, userID).
 		Delete(&models.ProjectMember{}).Error
 }
 
+// ListMemberships возвращает участие пользователя в проектах вместе с проектами
+func (r *ProjectRepository) ListMemberships(userID uint) ([]models.ProjectMember, error) {
+	var members []models.ProjectMember
+	if err := r.db.Preload("Project").Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// FindSuccessor ищет участника проекта, кроме excludeUserID, которому можно передать проект.
+// Роли перебираются в порядке roles, внутри роли выбирается участник с наибольшим стажем.
+func (r *ProjectRepository) FindSuccessor(projectID, excludeUserID uint, roles []string) (*models.ProjectMember, error) {
+	for _, role := range roles {
+		var member models.ProjectMember
+		err := r.db.Where("project_id = ? AND user_id <> ? AND role = ?", projectID, excludeUserID, role).
+			Order("joined_at, id").
+			First(&member).Error
+		if err == nil {
+			return &member, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// TransferOwnership делает участника userID автором проекта с ролью ownerRole
+func (r *ProjectRepository) TransferOwnership(projectID, userID uint, ownerRole string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Project{}).Where("id = ?", projectID).Update("user_id", userID).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.ProjectMember{}).
+			Where("project_id = ? AND user_id = ?", projectID, userID).
+			Update("role", ownerRole).Error
+	})
+}
+
+// Transition переводит проект из transition.FromStatus в transition.ToStatus с датами
+// startDate и endDate и записывает переход в историю. Если статус проекта уже
+// изменился, возвращается gorm.ErrRecordNotFound.
+func (r *ProjectRepository) Transition(transition *models.ProjectTransition, startDate, endDate time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Project{}).
+			Where("id = ? AND status = ?", transition.ProjectID, transition.FromStatus).
+			Updates(map[string]interface{}{
+				"status":     transition.ToStatus,
+				"start_date": startDate,
+				"end_date":   endDate,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(transition).Error
+	})
+}
+
+// ListTransitions возвращает историю смены статуса проекта, начиная с последних переходов
+func (r *ProjectRepository) ListTransitions(projectID uint) ([]models.ProjectTransition, error) {
+	var transitions []models.ProjectTransition
+	if err := r.db.Where("project_id = ?", projectID).Order("created_at DESC, id DESC").Find(&transitions).Error; err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// RemoveUserFromAll исключает пользователя из всех проектов
+func (r *ProjectRepository) RemoveUserFromAll(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.ProjectMember{}).Error
+}
+
 func (r *ProjectRepository) AddTag(projectID, tagID uint) error {
 	projectTag := models.ProjectTag{
 		ProjectID: projectID,
@@ -82,3 +366,8 @@ func (r *ProjectRepository) RemoveTag(projectID, tagID uint) error {
 func (r *ProjectRepository) GetDB() *gorm.DB {
 	return r.db
 }
+
+// WithTx возвращает репозиторий, работающий в рамках транзакции tx.
+func (r *ProjectRepository) WithTx(tx *gorm.DB) *ProjectRepository {
+	return &ProjectRepository{db: tx}
+}
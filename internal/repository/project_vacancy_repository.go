@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +20,69 @@ func (r *ProjectVacancyRepository) Create(vacancy *models.ProjectVacancy) error
 	return r.db.Create(vacancy).Error
 }
 
+func (r *ProjectVacancyRepository) GetByID(id uint) (*models.ProjectVacancy, error) {
+	var vacancy models.ProjectVacancy
+	if err := r.db.Preload("Project").Preload("Technologies").First(&vacancy, id).Error; err != nil {
+		return nil, err
+	}
+	return &vacancy, nil
+}
+
+// Update сохраняет редактируемые поля и состояние вакансии; с replaceTechnologies
+// технологии вакансии заменяются на vacancy.Technologies
+func (r *ProjectVacancyRepository) Update(vacancy *models.ProjectVacancy, replaceTechnologies bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(vacancy).
+			Select("title", "description", "role", "status", "expires_at",
+				"seniority", "hours_per_week", "employment_type", "remote_policy", "location",
+				"compensation_min", "compensation_max", "currency", "openings").
+			Updates(vacancy).Error
+		if err != nil {
+			return err
+		}
+		if !replaceTechnologies {
+			return nil
+		}
+		// У vacancy_technologies нет уникального ключа по паре, поэтому Replace
+		// продублировал бы оставшиеся привязки: старые удаляются целиком
+		if err := tx.Model(vacancy).Association("Technologies").Clear(); err != nil {
+			return err
+		}
+		if len(vacancy.Technologies) == 0 {
+			return nil
+		}
+		return tx.Model(vacancy).Association("Technologies").Append(vacancy.Technologies)
+	})
+}
+
+// HasApplications сообщает, есть ли у вакансии отклики в любом состоянии
+func (r *ProjectVacancyRepository) HasApplications(id uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.VacancyApplication{}).Where("vacancy_id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Delete удаляет вакансию вместе с привязками к технологиям
+func (r *ProjectVacancyRepository) Delete(vacancy *models.ProjectVacancy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(vacancy).Association("Technologies").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(&models.ProjectVacancy{}, vacancy.ID).Error
+	})
+}
+
+// CloseExpired закрывает открытые вакансии, срок которых истек к моменту now,
+// и возвращает число закрытых
+func (r *ProjectVacancyRepository) CloseExpired(now time.Time) (int64, error) {
+	result := r.db.Model(&models.ProjectVacancy{}).
+		Where("status = ? AND expires_at <= ?", models.VacancyStatusOpen, now).
+		Update("status", models.VacancyStatusClosed)
+	return result.RowsAffected, result.Error
+}
+
 func (r *ProjectVacancyRepository) FindByProjectID(projectID uint) ([]models.ProjectVacancy, error) {
 	var vacancies []models.ProjectVacancy
 	err := r.db.Preload("Technologies").Where("project_id = ?", projectID).Find(&vacancies).Error
@@ -26,6 +92,195 @@ func (r *ProjectVacancyRepository) FindByProjectID(projectID uint) ([]models.Pro
 	return vacancies, nil
 }
 
+// VacancyFilter — условия выборки списка вакансий
+type VacancyFilter struct {
+	// ViewerID — пользователь, которому показывается список; 0 — анонимный посетитель
+	ViewerID  uint
+	ProjectID uint
+	// ProjectStatus — статус проекта, к которому относится вакансия
+	ProjectStatus string
+	// IncludeDrafts включает вакансии черновиков проектов, которые по умолчанию скрыты из списков
+	IncludeDrafts bool
+	// IncludeUnlisted включает вакансии проектов, доступных по ссылке, — например,
+	// на странице самого проекта. Вакансии закрытых проектов видят только их участники.
+	IncludeUnlisted bool
+	// Status — состояние вакансии; пустое значение — открытые вакансии, срок которых не истек
+	Status string
+	// IncludeClosed включает вакансии во всех состояниях
+	IncludeClosed  bool
+	Technology     string
+	Seniority      string
+	EmploymentType string
+	RemotePolicy   string
+	// Location — часть названия места работы
+	Location string
+	// MaxHoursPerWeek — вакансии с указанной занятостью не больше стольких часов в неделю
+	MaxHoursPerWeek int
+	// MinCompensation — вакансии, вознаграждение по которым в валюте Currency может достичь этой суммы
+	MinCompensation int64
+	Currency        string
+	// MinOpenings — вакансии, на которые ищут не меньше стольких кандидатов
+	MinOpenings int
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+var vacancySortFields = map[string]sortField{
+	"created_at": {expr: "project_vacancies.created_at", kind: sortTime},
+	"name":       {expr: "project_vacancies.title", kind: sortString},
+}
+
+// filtered возвращает выборку вакансий неудаленных проектов, доступных
+// пользователю filter.ViewerID и подходящих под filter
+func (r *ProjectVacancyRepository) filtered(filter VacancyFilter) *gorm.DB {
+	visibilities := listedVisibilities
+	if filter.IncludeUnlisted {
+		visibilities = linkedVisibilities
+	}
+	access, args := projectAccess(filter.ViewerID, visibilities)
+	query := r.db.Model(&models.ProjectVacancy{}).
+		Where("EXISTS (SELECT 1 FROM projects WHERE projects.id = project_vacancies.project_id AND projects.deleted_at IS NULL AND "+access+")", args...)
+	if !filter.IncludeDrafts {
+		query = query.Where("EXISTS (SELECT 1 FROM projects WHERE projects.id = project_vacancies.project_id AND projects.status <> ?)", models.ProjectStatusDraft)
+	}
+	if filter.ProjectStatus != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM projects WHERE projects.id = project_vacancies.project_id AND projects.status = ?)", filter.ProjectStatus)
+	}
+	if filter.ProjectID != 0 {
+		query = query.Where("project_vacancies.project_id = ?", filter.ProjectID)
+	}
+	if filter.Status != "" && filter.Status != models.VacancyStatusOpen {
+		query = query.Where("project_vacancies.status = ?", filter.Status)
+	} else if !filter.IncludeClosed {
+		// Вакансия с истекшим сроком скрывается сразу, не дожидаясь, пока ее закроет фоновая задача
+		query = query.Where("project_vacancies.status = ? AND (project_vacancies.expires_at IS NULL OR project_vacancies.expires_at > ?)",
+			models.VacancyStatusOpen, time.Now())
+	}
+	if filter.Technology != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM vacancy_technologies JOIN technologies ON technologies.id = vacancy_technologies.technology_id "+
+			"WHERE vacancy_technologies.project_vacancy_id = project_vacancies.id AND vacancy_technologies.deleted_at IS NULL AND technologies.name ILIKE ?)", filter.Technology)
+	}
+	if filter.Seniority != "" {
+		query = query.Where("project_vacancies.seniority = ?", filter.Seniority)
+	}
+	if filter.EmploymentType != "" {
+		query = query.Where("project_vacancies.employment_type = ?", filter.EmploymentType)
+	}
+	if filter.RemotePolicy != "" {
+		query = query.Where("project_vacancies.remote_policy = ?", filter.RemotePolicy)
+	}
+	if filter.Location != "" {
+		query = query.Where("project_vacancies.location ILIKE ?", "%"+filter.Location+"%")
+	}
+	if filter.MaxHoursPerWeek > 0 {
+		query = query.Where("project_vacancies.hours_per_week > 0 AND project_vacancies.hours_per_week <= ?", filter.MaxHoursPerWeek)
+	}
+	if filter.Currency != "" {
+		query = query.Where("project_vacancies.currency = ?", filter.Currency)
+	}
+	if filter.MinCompensation > 0 {
+		// Верхняя граница не меньше нижней, поэтому достаточно сравнить обе
+		query = query.Where("(project_vacancies.compensation_max >= ? OR project_vacancies.compensation_min >= ?)", filter.MinCompensation, filter.MinCompensation)
+	}
+	if filter.MinOpenings > 0 {
+		query = query.Where("project_vacancies.openings >= ?", filter.MinOpenings)
+	}
+	return whereCreatedBetween(query, "project_vacancies.created_at", filter.CreatedFrom, filter.CreatedTo)
+}
+
+// ListPage возвращает страницу вакансий, подходящих под filter
+func (r *ProjectVacancyRepository) ListPage(filter VacancyFilter, params pagination.Params) ([]models.ProjectVacancy, pagination.Page, error) {
+	return fetchPage(r.filtered(filter), "project_vacancies", params, vacancySortFields,
+		func(q *gorm.DB) *gorm.DB {
+			return q.Preload("Technologies")
+		},
+		func(v *models.ProjectVacancy, sort string) pagination.Cursor {
+			cursor := pagination.Cursor{ID: v.ID}
+			switch sort {
+			case "name":
+				cursor.Value = v.Title
+			default:
+				cursor.Value = formatTimeCursor(v.CreatedAt)
+			}
+			return cursor
+		},
+	)
+}
+
+// VacancySearchResult — найденная вакансия, ее релевантность и фрагмент описания
+type VacancySearchResult struct {
+	Vacancy  models.ProjectVacancy
+	Rank     float64
+	Headline string
+}
+
+var vacancySearch = fullTextSpec("project_vacancies", "project_vacancies.description")
+
+// Search ищет вакансии, подходящие под filter, по названию, технологиям и описанию
+func (r *ProjectVacancyRepository) Search(query string, filter VacancyFilter, params pagination.Params) ([]VacancySearchResult, pagination.Page, error) {
+	hits, page, err := searchPage(r.filtered(filter), vacancySearch, query, params)
+	if err != nil {
+		return nil, page, err
+	}
+
+	var vacancies []models.ProjectVacancy
+	if len(hits) > 0 {
+		if err := r.db.Preload("Technologies").Find(&vacancies, searchHitIDs(hits)).Error; err != nil {
+			return nil, page, err
+		}
+	}
+	byID := make(map[uint]models.ProjectVacancy, len(vacancies))
+	for _, v := range vacancies {
+		byID[v.ID] = v
+	}
+
+	results := make([]VacancySearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if vacancy, ok := byID[hit.ID]; ok {
+			results = append(results, VacancySearchResult{Vacancy: vacancy, Rank: hit.Rank, Headline: hit.Headline})
+		}
+	}
+	return results, page, nil
+}
+
+// VacancyFacets — распределение найденных вакансий по технологиям и статусам проектов
+type VacancyFacets struct {
+	Technologies []FacetCount
+	Statuses     []FacetCount
+}
+
+// SearchFacets считает фасеты вакансий, найденных Search с теми же запросом и фильтром
+func (r *ProjectVacancyRepository) SearchFacets(query string, filter VacancyFilter) (*VacancyFacets, error) {
+	matches := searchMatches(r.filtered(filter), vacancySearch, query).Select("project_vacancies.id")
+
+	var facets VacancyFacets
+	var err error
+	facets.Technologies, err = facetCounts(r.db, "SELECT technologies.name AS value, COUNT(*) AS count FROM vacancy_technologies "+
+		"JOIN technologies ON technologies.id = vacancy_technologies.technology_id "+
+		"WHERE vacancy_technologies.project_vacancy_id IN (?) AND vacancy_technologies.deleted_at IS NULL GROUP BY technologies.name", matches)
+	if err != nil {
+		return nil, err
+	}
+	facets.Statuses, err = facetCounts(r.db, "SELECT projects.status AS value, COUNT(*) AS count FROM project_vacancies "+
+		"JOIN projects ON projects.id = project_vacancies.project_id WHERE project_vacancies.id IN (?) GROUP BY projects.status", matches)
+	if err != nil {
+		return nil, err
+	}
+	return &facets, nil
+}
+
+func (r *ProjectVacancyRepository) FindByProjectIDs(projectIDs []uint) ([]models.ProjectVacancy, error) {
+	var vacancies []models.ProjectVacancy
+	if len(projectIDs) == 0 {
+		return vacancies, nil
+	}
+	err := r.db.Preload("Technologies").Where("project_id IN ?", projectIDs).Find(&vacancies).Error
+	if err != nil {
+		return nil, err
+	}
+	return vacancies, nil
+}
+
 func (r *ProjectVacancyRepository) DB() *gorm.DB {
 	return r.db
 }
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/levstremilov/shance-app/internal/pagination"
+
+	"gorm.io/gorm"
+)
+
+// tsQuery разбирает запрос @q в обеих конфигурациях поискового вектора
+const tsQuery = "(websearch_to_tsquery('russian', @q) || websearch_to_tsquery('english', @q))"
+
+// Найденные слова во фрагментах выделяются тегом <mark>
+const headlineOptions = "MaxFragments=2, MaxWords=25, MinWords=8, FragmentDelimiter=\" … \", StartSel=<mark>, StopSel=</mark>"
+
+// Сколько самых частых значений возвращается для каждого фасета
+const maxFacetValues = 20
+
+// searchSpec описывает, как искать записи таблицы: условие совпадения,
+// релевантность и текст фрагмента. В выражениях доступны параметры
+// @q (запрос), @contains и @prefix (шаблоны LIKE) и @options (настройки ts_headline).
+type searchSpec struct {
+	table    string
+	match    string
+	rank     string
+	headline string
+}
+
+// fullTextSpec ищет по столбцу search_vector. Для фрагментов используется
+// конфигурация russian: английские слова в ней обрабатываются английским стеммером.
+func fullTextSpec(table, headlineText string) searchSpec {
+	return searchSpec{
+		table:    table,
+		match:    table + ".search_vector @@ " + tsQuery,
+		rank:     "ts_rank(" + table + ".search_vector, " + tsQuery + ")",
+		headline: "ts_headline('russian', " + headlineText + ", " + tsQuery + ", @options)",
+	}
+}
+
+// textSpec ищет вхождение запроса в короткий текст, например имя. Точное
+// совпадение релевантнее совпадения с началом, а оно — вхождения в середину.
+func textSpec(table, text string) searchSpec {
+	return searchSpec{
+		table:    table,
+		match:    text + " ILIKE @contains",
+		rank:     "CASE WHEN lower(" + text + ") = lower(@q) THEN 1.0 WHEN " + text + " ILIKE @prefix THEN 0.5 ELSE 0.1 END",
+		headline: text,
+	}
+}
+
+func searchArgs(q string) []interface{} {
+	return []interface{}{
+		sql.Named("q", q),
+		sql.Named("contains", "%"+q+"%"),
+		sql.Named("prefix", q+"%"),
+		sql.Named("options", headlineOptions),
+	}
+}
+
+// searchMatches ограничивает query записями, подходящими под запрос
+func searchMatches(query *gorm.DB, spec searchSpec, q string) *gorm.DB {
+	return query.Where(spec.match, searchArgs(q)...)
+}
+
+// searchHit — найденная запись, ее релевантность и фрагмент текста с совпадениями
+type searchHit struct {
+	ID       uint
+	Rank     float64
+	Headline string
+}
+
+// searchPage выбирает страницу записей, подходящих под запрос, в порядке
+// убывания релевантности. При нулевом размере страницы только считает записи.
+func searchPage(query *gorm.DB, spec searchSpec, q string, params pagination.Params) ([]searchHit, pagination.Page, error) {
+	var page pagination.Page
+	if params.UsesCursor() {
+		return nil, page, pagination.ErrCursorUnsupported
+	}
+
+	matches := searchMatches(query, spec, q)
+	if err := matches.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return nil, page, err
+	}
+	if params.PageSize == 0 {
+		page.HasMore = page.Total > 0
+		return nil, page, nil
+	}
+
+	var hits []searchHit
+	err := matches.Session(&gorm.Session{}).
+		Select(spec.table+".id AS id, "+spec.rank+" AS rank, "+spec.headline+" AS headline", searchArgs(q)...).
+		Order("rank DESC, " + spec.table + ".id DESC").
+		Offset(params.Offset()).
+		Limit(params.PageSize + 1).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, page, err
+	}
+
+	if len(hits) > params.PageSize {
+		page.HasMore = true
+		hits = hits[:params.PageSize]
+	}
+	return hits, page, nil
+}
+
+func searchHitIDs(hits []searchHit) []uint {
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+// FacetCount — значение фасета и число найденных записей с ним
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// facetCounts выполняет запрос, возвращающий столбцы value и count, и оставляет
+// самые частые значения
+func facetCounts(db *gorm.DB, query string, args ...interface{}) ([]FacetCount, error) {
+	var counts []FacetCount
+	err := db.Raw("SELECT value, count FROM ("+query+") AS facet WHERE value <> '' ORDER BY count DESC, value LIMIT ?",
+		append(args, maxFacetValues)...).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
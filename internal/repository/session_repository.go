@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *SessionRepository) GetByID(id uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) GetByUserAndID(userID, id uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) GetByFamilyID(familyID string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("family_id = ?", familyID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActive возвращает действующие сессии пользователя, последние активные первыми
+func (r *SessionRepository) ListActive(userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Refresh продлевает сессию при обновлении токенов и запоминает адрес клиента
+func (r *SessionRepository) Refresh(id uint, ip, userAgent string, now, expiresAt time.Time) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"ip":           ip,
+			"user_agent":   userAgent,
+			"last_seen_at": now,
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+// TouchLastSeen обновляет время последней активности не чаще раза в interval
+func (r *SessionRepository) TouchLastSeen(id uint, now time.Time, interval time.Duration) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ? AND last_seen_at < ?", id, now.Add(-interval)).
+		Update("last_seen_at", now).Error
+}
+
+// WithTx возвращает репозиторий, работающий в рамках транзакции tx.
+func (r *SessionRepository) WithTx(tx *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: tx}
+}
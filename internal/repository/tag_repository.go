@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
 
 	"gorm.io/gorm"
 )
@@ -44,6 +45,45 @@ func (r *TagRepository) List() ([]models.Tag, error) {
 	return tags, nil
 }
 
+// TagFilter — условия выборки списка тегов
+type TagFilter struct {
+	Query string
+}
+
+const tagPopularity = "(SELECT COUNT(*) FROM project_tags WHERE project_tags.tag_id = tags.id AND project_tags.deleted_at IS NULL)"
+
+var tagSortFields = map[string]sortField{
+	"created_at": {expr: "tags.created_at", kind: sortTime},
+	"name":       {expr: "tags.name", kind: sortString},
+	"popularity": {expr: tagPopularity, kind: sortInt},
+}
+
+// ListPage возвращает страницу тегов, подходящих под filter
+func (r *TagRepository) ListPage(filter TagFilter, params pagination.Params) ([]models.Tag, pagination.Page, error) {
+	query := r.DB.Model(&models.Tag{})
+	if filter.Query != "" {
+		query = query.Where("tags.name ILIKE ?", "%"+filter.Query+"%")
+	}
+
+	return fetchPage(query, "tags", params, tagSortFields,
+		func(q *gorm.DB) *gorm.DB {
+			return q.Select("tags.*, " + tagPopularity + " AS popularity")
+		},
+		func(t *models.Tag, sort string) pagination.Cursor {
+			cursor := pagination.Cursor{ID: t.ID}
+			switch sort {
+			case "name":
+				cursor.Value = t.Name
+			case "popularity":
+				cursor.Value = formatIntCursor(t.Popularity)
+			default:
+				cursor.Value = formatTimeCursor(t.CreatedAt)
+			}
+			return cursor
+		},
+	)
+}
+
 func (r *TagRepository) Search(query string) ([]models.Tag, error) {
 	var tags []models.Tag
 	if err := r.DB.Where("name ILIKE ?", "%"+query+"%").Find(&tags).Error; err != nil {
@@ -52,6 +92,42 @@ func (r *TagRepository) Search(query string) ([]models.Tag, error) {
 	return tags, nil
 }
 
+// TagSearchResult — найденный тег и его релевантность запросу
+type TagSearchResult struct {
+	Tag      models.Tag
+	Rank     float64
+	Headline string
+}
+
+var tagSearch = textSpec("tags", "tags.name")
+
+// SearchPage ищет теги по названию и возвращает страницу в порядке убывания релевантности
+func (r *TagRepository) SearchPage(query string, params pagination.Params) ([]TagSearchResult, pagination.Page, error) {
+	hits, page, err := searchPage(r.DB.Model(&models.Tag{}), tagSearch, query, params)
+	if err != nil {
+		return nil, page, err
+	}
+
+	var tags []models.Tag
+	if len(hits) > 0 {
+		if err := r.DB.Find(&tags, searchHitIDs(hits)).Error; err != nil {
+			return nil, page, err
+		}
+	}
+	byID := make(map[uint]models.Tag, len(tags))
+	for _, t := range tags {
+		byID[t.ID] = t
+	}
+
+	results := make([]TagSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if tag, ok := byID[hit.ID]; ok {
+			results = append(results, TagSearchResult{Tag: tag, Rank: hit.Rank, Headline: hit.Headline})
+		}
+	}
+	return results, page, nil
+}
+
 func (r *TagRepository) GetByUserID(userID uint) ([]models.Tag, error) {
 	var tags []models.Tag
 	if err := r.DB.Joins("JOIN user_tags ON user_tags.tag_id = tags.id").
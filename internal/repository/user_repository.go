@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
 
 	"gorm.io/gorm"
 )
@@ -34,8 +38,224 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+// Update сохраняет только перечисленные поля пользователя. Строка целиком не
+// перезаписывается: прочитанная раньше копия пользователя вернула бы значения,
+// которые успел изменить параллельный запрос, например последний принятый шаг TOTP.
+// Роль Update не меняет никогда: для этого есть UpdateRole, который завершает сессии.
+func (r *UserRepository) Update(user *models.User, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.db.Model(user).Select(fields).Omit("role").Updates(user).Error
+}
+
+// AdvanceTOTPStep запоминает последний принятый шаг TOTP, только если он новее сохраненного.
+// Возвращает false, если шаг уже принят другим запросом.
+func (r *UserRepository) AdvanceTOTPStep(id uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		UpdateColumn("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateRole меняет только глобальную роль пользователя
+func (r *UserRepository) UpdateRole(id uint, role string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (r *UserRepository) UpdateLastLogin(id uint, at time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).UpdateColumn("last_login", at).Error
+}
+
+// UserFilter — условия выборки списка пользователей
+type UserFilter struct {
+	Country     string
+	City        string
+	Tag         string
+	Role        string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+const userPopularity = "(SELECT COUNT(*) FROM project_members WHERE project_members.user_id = users.id AND project_members.deleted_at IS NULL)"
+
+var userSortFields = map[string]sortField{
+	"created_at": {expr: "users.created_at", kind: sortTime},
+	"name":       {expr: "users.last_name || ' ' || users.first_name", kind: sortString},
+	"popularity": {expr: userPopularity, kind: sortInt},
+}
+
+// filtered возвращает выборку пользователей, подходящих под filter
+func (r *UserRepository) filtered(filter UserFilter) *gorm.DB {
+	query := r.db.Model(&models.User{})
+	if filter.Country != "" {
+		query = query.Where("users.country ILIKE ?", filter.Country)
+	}
+	if filter.City != "" {
+		query = query.Where("users.city ILIKE ?", filter.City)
+	}
+	if filter.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM user_tags JOIN tags ON tags.id = user_tags.tag_id "+
+			"WHERE user_tags.user_id = users.id AND user_tags.deleted_at IS NULL AND tags.deleted_at IS NULL AND tags.name = ?)", filter.Tag)
+	}
+	if filter.Role != "" {
+		query = query.Where("users.role = ?", filter.Role)
+	}
+	return whereCreatedBetween(query, "users.created_at", filter.CreatedFrom, filter.CreatedTo)
+}
+
+// ListPage возвращает страницу пользователей, подходящих под filter
+func (r *UserRepository) ListPage(filter UserFilter, params pagination.Params) ([]models.User, pagination.Page, error) {
+	return fetchPage(r.filtered(filter), "users", params, userSortFields,
+		func(q *gorm.DB) *gorm.DB {
+			return q.Select("users.*, " + userPopularity + " AS popularity").Preload("Tags").Preload("Avatar.Variants")
+		},
+		func(u *models.User, sort string) pagination.Cursor {
+			cursor := pagination.Cursor{ID: u.ID}
+			switch sort {
+			case "name":
+				cursor.Value = u.LastName + " " + u.FirstName
+			case "popularity":
+				cursor.Value = formatIntCursor(u.Popularity)
+			default:
+				cursor.Value = formatTimeCursor(u.CreatedAt)
+			}
+			return cursor
+		},
+	)
+}
+
+// UserSearchResult — найденный пользователь и его релевантность запросу
+type UserSearchResult struct {
+	User     models.User
+	Rank     float64
+	Headline string
+}
+
+var userSearch = textSpec("users", "concat_ws(' ', users.first_name, users.last_name)")
+
+// Search ищет пользователей, подходящих под filter, по имени и фамилии
+func (r *UserRepository) Search(query string, filter UserFilter, params pagination.Params) ([]UserSearchResult, pagination.Page, error) {
+	hits, page, err := searchPage(r.filtered(filter), userSearch, query, params)
+	if err != nil {
+		return nil, page, err
+	}
+
+	var users []models.User
+	if len(hits) > 0 {
+		if err := r.db.Preload("Tags").Preload("Avatar.Variants").Find(&users, searchHitIDs(hits)).Error; err != nil {
+			return nil, page, err
+		}
+	}
+	byID := make(map[uint]models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	results := make([]UserSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		if user, ok := byID[hit.ID]; ok {
+			results = append(results, UserSearchResult{User: user, Rank: hit.Rank, Headline: hit.Headline})
+		}
+	}
+	return results, page, nil
+}
+
+// UserFacets — распределение найденных пользователей по тегам и странам
+type UserFacets struct {
+	Tags      []FacetCount
+	Countries []FacetCount
+}
+
+// SearchFacets считает фасеты пользователей, найденных Search с теми же запросом и фильтром
+func (r *UserRepository) SearchFacets(query string, filter UserFilter) (*UserFacets, error) {
+	matches := searchMatches(r.filtered(filter), userSearch, query).Select("users.id")
+
+	var facets UserFacets
+	var err error
+	facets.Tags, err = facetCounts(r.db, "SELECT tags.name AS value, COUNT(*) AS count FROM user_tags JOIN tags ON tags.id = user_tags.tag_id "+
+		"WHERE user_tags.user_id IN (?) AND user_tags.deleted_at IS NULL AND tags.deleted_at IS NULL GROUP BY tags.name", matches)
+	if err != nil {
+		return nil, err
+	}
+	facets.Countries, err = facetCounts(r.db, "SELECT users.country AS value, COUNT(*) AS count FROM users WHERE users.id IN (?) GROUP BY users.country", matches)
+	if err != nil {
+		return nil, err
+	}
+	return &facets, nil
+}
+
+// GetWithTags возвращает пользователя вместе с его тегами
+func (r *UserRepository) GetWithTags(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Preload("Tags").First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetProfile возвращает пользователя с тегами, аватаром и портфолио
+func (r *UserRepository) GetProfile(id uint) (*models.User, error) {
+	var user models.User
+	err := r.db.Preload("Tags").
+		Preload("Avatar.Variants").
+		Preload("PortfolioLinks", orderedByID).
+		Preload("PortfolioFiles", orderedByID).
+		First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetDeletionSchedule назначает или, если at равно nil, отменяет удаление аккаунта
+func (r *UserRepository) SetDeletionSchedule(id uint, at *time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).UpdateColumn("deletion_scheduled_at", at).Error
+}
+
+// Anonymize стирает персональные данные пользователя, удаляет его способы входа
+// и помечает аккаунт удаленным. Строка пользователя остается, чтобы не нарушать
+// ссылки из проектов и журнала аудита.
+func (r *UserRepository) Anonymize(id uint, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"email":                 fmt.Sprintf("deleted-%d@users.invalid", id),
+			"email_verified_at":     nil,
+			"password_hash":         "",
+			"first_name":            "",
+			"last_name":             "",
+			"role":                  "user",
+			"mfa_enabled":           false,
+			"totp_secret":           "",
+			"totp_last_step":        0,
+			"phone":                 "",
+			"country":               "",
+			"city":                  "",
+			"deletion_scheduled_at": nil,
+			"deleted_at":            now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Where("user_id = ?", id).Delete(&models.UserTag{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{
+			&models.UserIdentity{},
+			&models.PersonalAccessToken{},
+			&models.MFARecoveryCode{},
+			&models.OneTimeToken{},
+		} {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *UserRepository) Delete(id uint) error {
@@ -50,13 +270,24 @@ func (r *UserRepository) List() ([]models.User, error) {
 	return users, nil
 }
 
-
-func (r *UserRepository) OwnProjects(user_id uint) ([]models.Project, error) {
+// OwnProjects возвращает проекты пользователя, которые показываются в списках
+// пользователю viewerID (0 — анонимный посетитель)
+func (r *UserRepository) OwnProjects(user_id, viewerID uint) ([]models.Project, error) {
 	var projects []models.Project
 
-	if err := r.db.Find(&projects).Where("UserID = ?", user_id).Error; err != nil {
+	access, args := projectAccess(viewerID, listedVisibilities)
+	if err := r.db.Preload("Tags").Preload("User").Preload("Photos", orderedPhotos).Preload("Photos.Variants").Where("projects.user_id = ?", user_id).Where(access, args...).Find(&projects).Error; err != nil {
 		return nil, err
 	}
 
 	return projects, nil
-}
\ No newline at end of file
+}
+
+func (r *UserRepository) DB() *gorm.DB {
+	return r.db
+}
+
+// WithTx возвращает репозиторий, работающий в рамках транзакции tx.
+func (r *UserRepository) WithTx(tx *gorm.DB) *UserRepository {
+	return &UserRepository{db: tx}
+}
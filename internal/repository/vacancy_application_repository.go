@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type VacancyApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewVacancyApplicationRepository(db *gorm.DB) *VacancyApplicationRepository {
+	return &VacancyApplicationRepository{db: db}
+}
+
+// ApplicationFilter — условия выборки откликов на вакансии проекта
+type ApplicationFilter struct {
+	ProjectID uint
+	VacancyID uint
+	Stage     string
+}
+
+func (r *VacancyApplicationRepository) Create(application *models.VacancyApplication) error {
+	return r.db.Create(application).Error
+}
+
+func (r *VacancyApplicationRepository) GetByID(id uint) (*models.VacancyApplication, error) {
+	var application models.VacancyApplication
+	if err := r.db.Preload("Vacancy").Preload("User").First(&application, id).Error; err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// GetDetailed возвращает отклик вместе с заметками и историей смены этапов
+func (r *VacancyApplicationRepository) GetDetailed(id uint) (*models.VacancyApplication, error) {
+	var application models.VacancyApplication
+	err := r.db.Preload("Vacancy").Preload("User").
+		Preload("Notes", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Notes.Author").
+		Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&application, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// HasActive сообщает, есть ли у пользователя рассматриваемый отклик на вакансию
+func (r *VacancyApplicationRepository) HasActive(vacancyID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.VacancyApplication{}).
+		Where("vacancy_id = ? AND user_id = ? AND stage NOT IN ?", vacancyID, userID, finalApplicationStages).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListByProject возвращает отклики на вакансии проекта в порядке подачи
+func (r *VacancyApplicationRepository) ListByProject(filter ApplicationFilter) ([]models.VacancyApplication, error) {
+	var applications []models.VacancyApplication
+	query := r.db.Preload("Vacancy").Preload("User").Where("project_id = ?", filter.ProjectID)
+	if filter.VacancyID != 0 {
+		query = query.Where("vacancy_id = ?", filter.VacancyID)
+	}
+	if filter.Stage != "" {
+		query = query.Where("stage = ?", filter.Stage)
+	}
+	if err := query.Order("id ASC").Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// ListByUser возвращает отклики пользователя, начиная с последних
+func (r *VacancyApplicationRepository) ListByUser(userID uint) ([]models.VacancyApplication, error) {
+	var applications []models.VacancyApplication
+	err := r.db.Preload("Vacancy").Preload("Vacancy.Project").
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Find(&applications).Error
+	if err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// Этапы, на которых отклик больше не рассматривается
+var finalApplicationStages = []string{
+	models.ApplicationStageAccepted,
+	models.ApplicationStageRejected,
+	models.ApplicationStageWithdrawn,
+}
+
+// Move переводит отклик на этап change.ToStage и записывает смену в историю.
+// Если этап отклика уже не change.FromStage, возвращается gorm.ErrRecordNotFound.
+func (r *VacancyApplicationRepository) Move(application *models.VacancyApplication, change *models.ApplicationStageChange) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return moveApplication(tx, application, change)
+	})
+	if err != nil {
+		return err
+	}
+	application.Stage = change.ToStage
+	return nil
+}
+
+// Accept переводит отклик на этап accepted и добавляет кандидата в участники проекта
+// с ролью role, если он еще не участник. С closeVacancy вакансия помечается заполненной.
+// Если этап отклика уже не change.FromStage, возвращается gorm.ErrRecordNotFound.
+func (r *VacancyApplicationRepository) Accept(application *models.VacancyApplication, change *models.ApplicationStageChange, role string, closeVacancy bool, now time.Time) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := moveApplication(tx, application, change); err != nil {
+			return err
+		}
+
+		err := tx.Where("project_id = ? AND user_id = ?", application.ProjectID, application.UserID).
+			Limit(1).
+			Find(&member).Error
+		if err != nil {
+			return err
+		}
+		if member.ID == 0 {
+			member = models.ProjectMember{
+				ProjectID: application.ProjectID,
+				UserID:    application.UserID,
+				Role:      role,
+				JoinedAt:  now,
+			}
+			if err := tx.Create(&member).Error; err != nil {
+				return err
+			}
+		}
+
+		if closeVacancy {
+			err := tx.Model(&models.ProjectVacancy{}).
+				Where("id = ?", application.VacancyID).
+				Update("status", models.VacancyStatusFilled).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Preload("User").First(&member, member.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	application.Stage = change.ToStage
+	if closeVacancy {
+		application.Vacancy.Status = models.VacancyStatusFilled
+	}
+	return &member, nil
+}
+
+func moveApplication(tx *gorm.DB, application *models.VacancyApplication, change *models.ApplicationStageChange) error {
+	result := tx.Model(&models.VacancyApplication{}).
+		Where("id = ? AND stage = ?", application.ID, change.FromStage).
+		Update("stage", change.ToStage)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	change.ApplicationID = application.ID
+	return tx.Create(change).Error
+}
+
+func (r *VacancyApplicationRepository) AddNote(note *models.ApplicationNote) error {
+	if err := r.db.Create(note).Error; err != nil {
+		return err
+	}
+	return r.db.Preload("Author").First(note, note.ID).Error
+}
+
+// ListWithAttachment возвращает отклики с вложениями
+func (r *VacancyApplicationRepository) ListWithAttachment() ([]models.VacancyApplication, error) {
+	var applications []models.VacancyApplication
+	err := r.db.Where("attachment_key <> ''").Order("id").Find(&applications).Error
+	if err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// ClearAttachment удаляет из отклика сведения о вложении
+func (r *VacancyApplicationRepository) ClearAttachment(id uint) error {
+	return r.db.Model(&models.VacancyApplication{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attachment_key":          "",
+			"attachment_name":         "",
+			"attachment_content_type": "",
+			"attachment_size":         0,
+		}).Error
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/levstremilov/shance-app/internal/config"
 	"github.com/levstremilov/shance-app/internal/handler"
 	"github.com/levstremilov/shance-app/internal/middleware"
+	"github.com/levstremilov/shance-app/internal/policy"
 	"github.com/levstremilov/shance-app/internal/service"
+	"github.com/levstremilov/shance-app/internal/storage"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -18,19 +20,40 @@ import (
 func SetUpRouter(
 	projectHandler *handler.ProjectHandler,
 	authHandler *handler.AuthHandler,
+	oauthHandler *handler.OAuthHandler,
+	mfaHandler *handler.MFAHandler,
+	tokenHandler *handler.TokenHandler,
+	keysHandler *handler.KeysHandler,
+	sessionHandler *handler.SessionHandler,
+	accountHandler *handler.AccountHandler,
 	userHandler *handler.UserHandler,
+	profileHandler *handler.ProfileHandler,
 	tagHandler *handler.TagHandler,
 	vacancyHandler *handler.ProjectVacancyHandler,
+	searchHandler *handler.SearchHandler,
+	invitationHandler *handler.InvitationHandler,
+	joinRequestHandler *handler.JoinRequestHandler,
+	applicationHandler *handler.ApplicationHandler,
 	authService service.AuthServiceInterface,
+	tokenService service.PersonalAccessTokenServiceInterface,
+	sessionService service.SessionServiceInterface,
+	auditService service.AuditServiceInterface,
+	vacancyService *service.ProjectVacancyService,
+	policyEngine *policy.Engine,
 	cfg *config.Config,
-) *gin.Engine {
+) (*gin.Engine, error) {
 	r := gin.Default()
 
+	// Заголовкам с адресом клиента доверяем только от настроенных прокси
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
 	// CORS
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -46,6 +69,15 @@ func SetUpRouter(
 
 	r.GET("/api/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Файлы локального хранилища раздаются статически. Вложения откликов лежат
+	// в закрытом хранилище и выдаются только через API.
+	if cfg.Storage.Driver == "local" || cfg.Storage.Driver == "" {
+		r.Static(storage.LocalPath, cfg.Storage.LocalDir)
+	}
+
+	// Открытые ключи для проверки JWT другими сервисами
+	r.GET("/.well-known/jwks.json", keysHandler.JWKS)
+
 	// API v1
 	api := r.Group("/api/v1")
 	{
@@ -55,54 +87,160 @@ func SetUpRouter(
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/logout", authHandler.Logout)
+			auth.POST("/password/forgot", authHandler.ForgotPassword)
+			auth.POST("/password/reset", authHandler.ResetPassword)
+			auth.POST("/email/verify", authHandler.VerifyEmail)
+			auth.POST("/email/resend", authHandler.ResendVerification)
+			auth.POST("/unlock", authHandler.UnlockAccount)
+			auth.POST("/mfa/verify", authHandler.VerifyMFA)
+			auth.POST("/mfa/enroll", authHandler.BeginMFAEnrollment)
+			auth.POST("/mfa/enroll/confirm", authHandler.ConfirmMFAEnrollment)
+			auth.GET("/oauth/providers", oauthHandler.ListProviders)
+			auth.GET("/oauth/:provider", oauthHandler.Authorize)
+			auth.GET("/oauth/:provider/callback", oauthHandler.Callback)
 		}
 
-		protected := api.Group("")
+		authenticator := middleware.NewAuthenticator(authService, tokenService, sessionService)
 
-		// Protected routes
-		if cfg.Server.Env == "dev" {
-			protected.Use(middleware.AuthMiddleware(authService))
+		// Чтение доступно без входа; переданные заголовки проверяются, а просроченная
+		// cookie игнорируется
+		auditImpersonation := middleware.AuditImpersonation(auditService)
+		public := api.Group("", authenticator.Optional(), auditImpersonation)
+		protected := api.Group("", authenticator.Required(), auditImpersonation)
+
+		// Действия с учетными данными недоступны администратору, работающему от имени пользователя
+		notImpersonated := middleware.ForbidImpersonation()
+
+		// Ограничения для персональных токенов; сессии пользователя имеют все права
+		scope := middleware.RequireScope
+
+		// Права по ролям пользователя и ролям в проекте
+		can := func(permission policy.Permission) gin.HandlerFunc {
+			return middleware.Require(policyEngine, permission)
+		}
+		canProject := func(permission policy.Permission) gin.HandlerFunc {
+			return middleware.RequireProject(policyEngine, permission, "id")
+		}
+		canVacancy := func(permission policy.Permission) gin.HandlerFunc {
+			return middleware.RequireProjectOf(policyEngine, permission, "id", vacancyService.ProjectID, service.ErrVacancyNotFound)
 		}
 
+		// Public routes
 		{
+			public.GET("/users", scope(service.ScopeUsersRead), userHandler.ListUsers)
+			public.GET("/users/:id", scope(service.ScopeUsersRead), userHandler.GetUser)
+			public.GET("/users/:id/projects", scope(service.ScopeProjectsRead), userHandler.GetOwnProjects)
+
+			public.GET("/projects", scope(service.ScopeProjectsRead), projectHandler.GetProjects)
+			public.GET("/projects/:id", scope(service.ScopeProjectsRead), projectHandler.GetProject)
+			public.GET("/projects/search", scope(service.ScopeProjectsRead), projectHandler.SearchProjects)
+			public.GET("/projects/:id/photos", scope(service.ScopeProjectsRead), projectHandler.ListPhotos)
+			public.GET("/projects/:id/transitions", scope(service.ScopeProjectsRead), projectHandler.ListTransitions)
+			public.GET("/projects/:id/vacancies", scope(service.ScopeVacanciesRead), vacancyHandler.GetProjectVacancies)
+			public.GET("/vacancies", scope(service.ScopeVacanciesRead), vacancyHandler.ListVacancies)
+			public.GET("/vacancies/search", scope(service.ScopeVacanciesRead), vacancyHandler.SearchVacancies)
+			public.GET("/vacancies/:id", scope(service.ScopeVacanciesRead), vacancyHandler.GetVacancy)
+
+			public.GET("/tags", scope(service.ScopeTagsRead), tagHandler.ListTags)
+			public.GET("/tags/search", scope(service.ScopeTagsRead), tagHandler.SearchTags)
+
+			// Общий поиск возвращает записи всех типов, поэтому требует права на чтение каждого
+			public.GET("/search", scope(service.ScopeProjectsRead), scope(service.ScopeVacanciesRead),
+				scope(service.ScopeUsersRead), scope(service.ScopeTagsRead), searchHandler.Search)
+		}
+
+		// Protected routes
+		{
+			protected.POST("/auth/logout-all", middleware.RequireSession(), notImpersonated, authHandler.LogoutAll)
+
 			// User routes
 			users := protected.Group("/users")
 			{
-				users.GET("/me", userHandler.GetMe)
-				users.PATCH("/me", userHandler.UpdateMe)
-				users.GET("/:id", userHandler.GetUser)
-				users.GET("/:id/projects", userHandler.GetOwnProjects)
+				users.GET("/me", scope(service.ScopeUsersRead), userHandler.GetMe)
+				users.PATCH("/me", scope(service.ScopeUsersWrite), userHandler.UpdateMe)
+				users.GET("/me/invitations", scope(service.ScopeProjectsRead), invitationHandler.ListMyInvitations)
+				users.GET("/me/join-requests", scope(service.ScopeProjectsRead), joinRequestHandler.ListMyJoinRequests)
+				users.DELETE("/me/join-requests/:id", scope(service.ScopeProjectsWrite), joinRequestHandler.WithdrawJoinRequest)
+				users.GET("/me/applications", scope(service.ScopeVacanciesRead), applicationHandler.ListMyApplications)
+				users.DELETE("/me/applications/:id", scope(service.ScopeVacanciesWrite), applicationHandler.WithdrawApplication)
+				users.GET("/me/applications/:id/attachment", scope(service.ScopeVacanciesRead), applicationHandler.DownloadMyApplicationAttachment)
+				users.PUT("/me/avatar", scope(service.ScopeUsersWrite), profileHandler.UploadAvatar)
+				users.DELETE("/me/avatar", scope(service.ScopeUsersWrite), profileHandler.DeleteAvatar)
+				users.POST("/me/portfolio/links", scope(service.ScopeUsersWrite), profileHandler.AddPortfolioLink)
+				users.PUT("/me/portfolio/links/:id", scope(service.ScopeUsersWrite), profileHandler.UpdatePortfolioLink)
+				users.DELETE("/me/portfolio/links/:id", scope(service.ScopeUsersWrite), profileHandler.DeletePortfolioLink)
+				users.POST("/me/portfolio/files", scope(service.ScopeUsersWrite), profileHandler.UploadPortfolioFile)
+				users.DELETE("/me/portfolio/files/:id", scope(service.ScopeUsersWrite), profileHandler.DeletePortfolioFile)
+				users.PUT("/:id/role", middleware.RequireSession(), notImpersonated, can(policy.PermUserRolesManage), userHandler.UpdateRole)
+				users.POST("/:id/impersonate", middleware.RequireSession(), notImpersonated, can(policy.PermUserImpersonate), authHandler.Impersonate)
+
+				// Управление учетными данными доступно только в сессии пользователя
+				me := users.Group("/me", middleware.RequireSession(), notImpersonated)
+				{
+					me.POST("/mfa/totp", mfaHandler.SetupTOTP)
+					me.POST("/mfa/totp/confirm", mfaHandler.ConfirmTOTP)
+					me.POST("/mfa/disable", mfaHandler.DisableMFA)
+					me.POST("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+					me.GET("/tokens", tokenHandler.ListTokens)
+					me.POST("/tokens", tokenHandler.CreateToken)
+					me.DELETE("/tokens/:id", tokenHandler.DeleteToken)
+					me.GET("/sessions", sessionHandler.ListSessions)
+					me.DELETE("/sessions/:id", sessionHandler.DeleteSession)
+					me.POST("/export", accountHandler.RequestExport)
+					me.GET("/jobs/:id", accountHandler.GetJob)
+					me.GET("/jobs/:id/download", accountHandler.DownloadExport)
+					me.DELETE("", accountHandler.DeleteAccount)
+					me.DELETE("/deletion", accountHandler.CancelDeletion)
+				}
 			}
 
 			// Project routes
 			projects := protected.Group("/projects")
 			{
-				projects.POST("", projectHandler.CreateProject)
-				projects.GET("", projectHandler.GetProjects)
-				projects.GET("/:id", projectHandler.GetProject)
-				projects.PUT("/:id", projectHandler.UpdateProject)
-				projects.DELETE("/:id", projectHandler.DeleteProject)
-				projects.GET("/search", projectHandler.SearchProjects)
-				projects.POST("/:id/invite", projectHandler.InviteMember)
-				projects.GET("/:id/members", projectHandler.GetProjectMembers)
-				projects.POST("/:id/vacancy", vacancyHandler.CreateProjectVacancy)
-				projects.GET("/:id/vacancies", vacancyHandler.GetProjectVacancies)
+				projects.POST("", scope(service.ScopeProjectsWrite), can(policy.PermProjectCreate), projectHandler.CreateProject)
+				projects.PUT("/:id", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectUpdate), projectHandler.UpdateProject)
+				projects.DELETE("/:id", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectDelete), projectHandler.DeleteProject)
+				projects.POST("/:id/transitions", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectStatusManage), projectHandler.TransitionProject)
+				projects.POST("/:id/photos", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectUpdate), projectHandler.UploadPhoto)
+				projects.PUT("/:id/photos/order", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectUpdate), projectHandler.ReorderPhotos)
+				projects.DELETE("/:id/photos/:photo_id", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectUpdate), projectHandler.DeletePhoto)
+				projects.POST("/:id/invite", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectMembersManage), invitationHandler.InviteMember)
+				projects.GET("/:id/invitations", scope(service.ScopeProjectsRead), canProject(policy.PermProjectMembersManage), invitationHandler.ListProjectInvitations)
+				projects.DELETE("/:id/invitations/:invitation_id", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectMembersManage), invitationHandler.RevokeInvitation)
+				projects.POST("/:id/join-requests", scope(service.ScopeProjectsWrite), joinRequestHandler.SubmitJoinRequest)
+				projects.GET("/:id/join-requests", scope(service.ScopeProjectsRead), canProject(policy.PermProjectMembersManage), joinRequestHandler.ListJoinRequests)
+				projects.POST("/:id/join-requests/:request_id/approve", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectMembersManage), joinRequestHandler.ApproveJoinRequest)
+				projects.POST("/:id/join-requests/:request_id/reject", scope(service.ScopeProjectsWrite), canProject(policy.PermProjectMembersManage), joinRequestHandler.RejectJoinRequest)
+				projects.GET("/:id/members", scope(service.ScopeProjectsRead), canProject(policy.PermProjectMembersRead), projectHandler.GetProjectMembers)
+				projects.POST("/:id/vacancy", scope(service.ScopeVacanciesWrite), canProject(policy.PermVacancyManage), vacancyHandler.CreateProjectVacancy)
+				projects.GET("/:id/applications", scope(service.ScopeVacanciesRead), canProject(policy.PermVacancyManage), applicationHandler.ListProjectApplications)
+				projects.GET("/:id/applications/:application_id", scope(service.ScopeVacanciesRead), canProject(policy.PermVacancyManage), applicationHandler.GetProjectApplication)
+				projects.GET("/:id/applications/:application_id/attachment", scope(service.ScopeVacanciesRead), canProject(policy.PermVacancyManage), applicationHandler.DownloadProjectApplicationAttachment)
+				projects.POST("/:id/applications/:application_id/stage", scope(service.ScopeVacanciesWrite), canProject(policy.PermVacancyManage), applicationHandler.MoveApplication)
+				projects.POST("/:id/applications/:application_id/notes", scope(service.ScopeVacanciesWrite), canProject(policy.PermVacancyManage), applicationHandler.AddApplicationNote)
 			}
 
+			protected.PUT("/vacancies/:id", scope(service.ScopeVacanciesWrite), canVacancy(policy.PermVacancyManage), vacancyHandler.ReplaceVacancy)
+			protected.PATCH("/vacancies/:id", scope(service.ScopeVacanciesWrite), canVacancy(policy.PermVacancyManage), vacancyHandler.UpdateVacancy)
+			protected.DELETE("/vacancies/:id", scope(service.ScopeVacanciesWrite), canVacancy(policy.PermVacancyManage), vacancyHandler.DeleteVacancy)
+			protected.POST("/vacancies/:id/applications", scope(service.ScopeVacanciesWrite), applicationHandler.Apply)
+
+			protected.POST("/invitations/:token/accept", scope(service.ScopeProjectsWrite), invitationHandler.AcceptInvitation)
+			protected.POST("/invitations/:token/decline", scope(service.ScopeProjectsWrite), invitationHandler.DeclineInvitation)
+
 			// Tag routes
 			tags := protected.Group("/tags")
 			{
-				tags.POST("", tagHandler.CreateTag)
-				tags.GET("", tagHandler.ListTags)
-				tags.GET("/search", tagHandler.SearchTags)
-				tags.PUT("/:id", tagHandler.UpdateTag)
-				tags.DELETE("/:id", tagHandler.DeleteTag)
+				tags.POST("", scope(service.ScopeTagsWrite), can(policy.PermTagManage), tagHandler.CreateTag)
+				tags.PUT("/:id", scope(service.ScopeTagsWrite), can(policy.PermTagManage), tagHandler.UpdateTag)
+				tags.DELETE("/:id", scope(service.ScopeTagsWrite), can(policy.PermTagManage), tagHandler.DeleteTag)
 			}
 
 			// Technology route
-			protected.POST("/technologies", vacancyHandler.CreateTechnology)
+			protected.POST("/technologies", scope(service.ScopeVacanciesWrite), can(policy.PermTechnologyManage), vacancyHandler.CreateTechnology)
 		}
 	}
 
-	return r
+	return r, nil
 }
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/mailer"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrDeletionNotScheduled = errors.New("account deletion is not scheduled")
+
+// Роли участников, которым передается проект удаленного автора, в порядке приоритета
+var projectSuccessorRoles = []string{policy.ProjectRoleMaintainer, policy.ProjectRoleMember}
+
+type AccountDeletionServiceInterface interface {
+	Schedule(userID uint) (*models.Job, error)
+	Cancel(userID uint) error
+	Run(ctx context.Context, job *models.Job) (string, error)
+}
+
+type AccountDeletionService struct {
+	userRepo         *repository.UserRepository
+	projectRepo      *repository.ProjectRepository
+	joinRequestRepo  *repository.ProjectJoinRequestRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
+	jobRepo          *repository.JobRepository
+	exportService    DataExportServiceInterface
+	profileService   ProfileServiceInterface
+	applications     VacancyApplicationServiceInterface
+	auditService     AuditServiceInterface
+	mailer           mailer.Mailer
+	publicURL        string
+	gracePeriod      time.Duration
+}
+
+func NewAccountDeletionService(
+	userRepo *repository.UserRepository,
+	projectRepo *repository.ProjectRepository,
+	joinRequestRepo *repository.ProjectJoinRequestRepository,
+	refreshTokenRepo *repository.RefreshTokenRepository,
+	jobRepo *repository.JobRepository,
+	exportService DataExportServiceInterface,
+	profileService ProfileServiceInterface,
+	applications VacancyApplicationServiceInterface,
+	auditService AuditServiceInterface,
+	mail mailer.Mailer,
+	publicURL string,
+	gracePeriod time.Duration,
+) AccountDeletionServiceInterface {
+	return &AccountDeletionService{
+		userRepo:         userRepo,
+		projectRepo:      projectRepo,
+		joinRequestRepo:  joinRequestRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		jobRepo:          jobRepo,
+		exportService:    exportService,
+		profileService:   profileService,
+		applications:     applications,
+		auditService:     auditService,
+		mailer:           mail,
+		publicURL:        publicURL,
+		gracePeriod:      gracePeriod,
+	}
+}
+
+// Schedule назначает удаление аккаунта по истечении льготного периода.
+// До этого момента пользователь может войти и отменить удаление.
+func (s *AccountDeletionService) Schedule(userID uint) (*models.Job, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DeletionScheduledAt != nil {
+		job, err := s.jobRepo.FindActive(userID, models.JobTypeAccountDeletion)
+		if err == nil {
+			return job, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	scheduledAt := time.Now().Add(s.gracePeriod)
+	if user.DeletionScheduledAt != nil {
+		scheduledAt = *user.DeletionScheduledAt
+	}
+
+	// Задача создается первой: если отметка в профиле не сохранится,
+	// задача увидит это при запуске и ничего не удалит
+	job := &models.Job{
+		Type:   models.JobTypeAccountDeletion,
+		UserID: userID,
+		Status: models.JobStatusPending,
+		RunAt:  scheduledAt,
+	}
+	if err := s.jobRepo.Create(job); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetDeletionSchedule(userID, &scheduledAt); err != nil {
+		return nil, err
+	}
+
+	err = s.auditService.Record(AuditEntry{
+		Action:   AuditAccountDeletionScheduled,
+		UserID:   &userID,
+		Metadata: map[string]interface{}{"scheduled_at": scheduledAt},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Аккаунт будет удален",
+		Body: fmt.Sprintf("Мы получили запрос на удаление вашего аккаунта. %s ваши личные данные будут удалены без возможности восстановления.\n\nЕсли вы передумали, войдите в аккаунт и отмените удаление:\n%s",
+			scheduledAt.Format("02.01.2006 15:04 MST"), s.publicURL),
+	})
+	if err != nil {
+		log.Printf("Failed to send deletion notice to user %d: %v", userID, err)
+	}
+	return job, nil
+}
+
+// Cancel отменяет назначенное удаление аккаунта
+func (s *AccountDeletionService) Cancel(userID uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.DeletionScheduledAt == nil {
+		return ErrDeletionNotScheduled
+	}
+
+	if err := s.userRepo.SetDeletionSchedule(userID, nil); err != nil {
+		return err
+	}
+	if err := s.jobRepo.CancelPending(userID, models.JobTypeAccountDeletion, time.Now()); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(AuditEntry{Action: AuditAccountDeletionCancelled, UserID: &userID})
+}
+
+// Run обезличивает аккаунт и удаляет аватар и портфолио. Проекты пользователя передаются следующему по старшинству
+// участнику, а проекты без участников архивируются.
+func (s *AccountDeletionService) Run(ctx context.Context, job *models.Job) (string, error) {
+	user, err := s.userRepo.GetByID(job.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "already deleted", nil
+		}
+		return "", err
+	}
+
+	now := time.Now()
+	if user.DeletionScheduledAt == nil || user.DeletionScheduledAt.After(now) {
+		return "cancelled", nil
+	}
+
+	var transferred, archived int
+	err = s.userRepo.DB().Transaction(func(tx *gorm.DB) error {
+		projects := s.projectRepo.WithTx(tx)
+
+		owned, err := s.userRepo.WithTx(tx).OwnProjects(user.ID, user.ID)
+		if err != nil {
+			return err
+		}
+		for _, project := range owned {
+			successor, err := projects.FindSuccessor(project.ID, user.ID, projectSuccessorRoles)
+			switch {
+			case err == nil:
+				if err := projects.TransferOwnership(project.ID, successor.UserID, policy.ProjectRoleOwner); err != nil {
+					return err
+				}
+				transferred++
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if project.Status == models.ProjectStatusArchived {
+					continue
+				}
+				transition := &models.ProjectTransition{
+					ProjectID:  project.ID,
+					FromStatus: project.Status,
+					ToStatus:   models.ProjectStatusArchived,
+					Comment:    "owner account deleted",
+				}
+				if err := projects.Transition(transition, project.StartDate, project.EndDate); err != nil {
+					return err
+				}
+				archived++
+			default:
+				return err
+			}
+		}
+
+		if err := projects.RemoveUserFromAll(user.ID); err != nil {
+			return err
+		}
+		if err := s.joinRequestRepo.WithTx(tx).WithdrawAllForUser(user.ID); err != nil {
+			return err
+		}
+		if err := s.refreshTokenRepo.WithTx(tx).RevokeAllForUser(user.ID); err != nil {
+			return err
+		}
+		return s.userRepo.WithTx(tx).Anonymize(user.ID, now)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.exportService.Purge(user.ID); err != nil {
+		log.Printf("Failed to remove exports of user %d: %v", user.ID, err)
+	}
+	if err := s.profileService.Purge(ctx, user.ID); err != nil {
+		log.Printf("Failed to remove avatar and portfolio of user %d: %v", user.ID, err)
+	}
+	if err := s.applications.Purge(ctx, user.ID); err != nil {
+		log.Printf("Failed to withdraw applications of user %d: %v", user.ID, err)
+	}
+
+	err = s.auditService.Record(AuditEntry{
+		Action:   AuditAccountDeleted,
+		UserID:   &user.ID,
+		Metadata: map[string]interface{}{"projects_transferred": transferred, "projects_archived": archived},
+	})
+	if err != nil {
+		log.Printf("Failed to record deletion of user %d: %v", user.ID, err)
+	}
+
+	return fmt.Sprintf("projects transferred: %d, archived: %d", transferred, archived), nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/mailer"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidOneTimeToken = errors.New("invalid or expired token")
+
+type AccountServiceInterface interface {
+	RequestPasswordReset(email string) error
+	ResetPassword(token, newPassword string) error
+	RequestEmailVerification(email string) error
+	VerifyEmail(token string) error
+}
+
+type AccountService struct {
+	userRepo         *repository.UserRepository
+	tokenRepo        *repository.OneTimeTokenRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
+	mailer           mailer.Mailer
+	publicURL        string
+	resetTTL         time.Duration
+	verificationTTL  time.Duration
+}
+
+func NewAccountService(
+	userRepo *repository.UserRepository,
+	tokenRepo *repository.OneTimeTokenRepository,
+	refreshTokenRepo *repository.RefreshTokenRepository,
+	mailer mailer.Mailer,
+	publicURL string,
+	resetTTL, verificationTTL time.Duration,
+) AccountServiceInterface {
+	return &AccountService{
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		mailer:           mailer,
+		publicURL:        publicURL,
+		resetTTL:         resetTTL,
+		verificationTTL:  verificationTTL,
+	}
+}
+
+// RequestPasswordReset отправляет ссылку для сброса пароля.
+// Если пользователь не найден, ошибка не возвращается, чтобы не раскрывать наличие аккаунта.
+func (s *AccountService) RequestPasswordReset(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := issueOneTimeToken(s.tokenRepo, user.ID, models.TokenPurposePasswordReset, s.resetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf("Чтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка действительна %s. Если вы не запрашивали сброс, просто проигнорируйте это письмо.",
+			s.link("/reset-password", token), s.resetTTL),
+	})
+}
+
+// ResetPassword устанавливает новый пароль и отзывает все refresh токены пользователя
+func (s *AccountService) ResetPassword(token, newPassword string) error {
+	stored, err := s.tokenRepo.Consume(models.TokenPurposePasswordReset, hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidOneTimeToken
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hashedPassword)
+
+	// Переход по ссылке из письма подтверждает владение адресом
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := s.userRepo.Update(user, "password_hash", "email_verified_at"); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeAllForUser(user.ID)
+}
+
+// RequestEmailVerification отправляет письмо со ссылкой для подтверждения email.
+// Для неизвестных и уже подтвержденных адресов ничего не делает.
+func (s *AccountService) RequestEmailVerification(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	token, err := issueOneTimeToken(s.tokenRepo, user.ID, models.TokenPurposeEmailVerification, s.verificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf("Подтвердите адрес электронной почты, перейдя по ссылке:\n%s\n\nСсылка действительна %s.",
+			s.link("/verify-email", token), s.verificationTTL),
+	})
+}
+
+func (s *AccountService) VerifyEmail(token string) error {
+	stored, err := s.tokenRepo.Consume(models.TokenPurposeEmailVerification, hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidOneTimeToken
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return s.userRepo.Update(user, "email_verified_at")
+}
+
+func (s *AccountService) link(path, token string) string {
+	return s.publicURL + path + "?token=" + url.QueryEscape(token)
+}
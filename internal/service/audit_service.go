@@ -0,0 +1,65 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+)
+
+// События журнала аудита
+const (
+	AuditAccountLocked   = "account.locked"
+	AuditAccountUnlocked = "account.unlocked"
+	AuditIPLocked        = "ip.locked"
+
+	AuditImpersonationStarted = "impersonation.started"
+	AuditImpersonatedRequest  = "impersonation.request"
+
+	AuditDataExportRequested      = "account.export_requested"
+	AuditAccountDeletionScheduled = "account.deletion_scheduled"
+	AuditAccountDeletionCancelled = "account.deletion_cancelled"
+	AuditAccountDeleted           = "account.deleted"
+)
+
+// AuditEntry описывает событие для журнала аудита
+type AuditEntry struct {
+	Action   string
+	UserID   *uint
+	ActorID  *uint
+	IP       string
+	Metadata map[string]interface{}
+}
+
+type AuditServiceInterface interface {
+	Record(entry AuditEntry) error
+}
+
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+}
+
+func NewAuditService(auditRepo *repository.AuditRepository) AuditServiceInterface {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *AuditService) Record(entry AuditEntry) error {
+	metadata := "{}"
+	if len(entry.Metadata) > 0 {
+		data, err := json.Marshal(entry.Metadata)
+		if err != nil {
+			return err
+		}
+		metadata = string(data)
+	}
+
+	return s.auditRepo.Create(&models.AuditLog{
+		Action:   entry.Action,
+		UserID:   entry.UserID,
+		ActorID:  entry.ActorID,
+		IP:       entry.IP,
+		Metadata: metadata,
+	})
+}
@@ -2,42 +2,123 @@ package service
 
 import (
 	"errors"
-	"fmt"
+	"log"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
 	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/signing"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type Claims struct {
 	UserID uint   `json:"user_id"`
 	Email  string `json:"email"`
 	Role   string `json:"role"`
+	// Purpose задан только у промежуточных токенов (например, MFA challenge),
+	// такие токены не принимаются как access токены
+	Purpose string `json:"purpose,omitempty"`
+	// SessionID — сессия, для которой выдан access токен
+	SessionID uint `json:"sid,omitempty"`
+	// ImpersonatorID — администратор, которому выдан токен для работы от имени UserID
+	ImpersonatorID uint `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
+const (
+	tokenPurposeMFA       = "mfa"
+	tokenPurposeMFAEnroll = "mfa_enroll"
+)
+
+// dummyPasswordHash сравнивается с паролем, когда пользователя с таким email нет,
+// чтобы время ответа не выдавало, какие адреса зарегистрированы. Стоимость
+// совпадает с bcrypt.DefaultCost, с которой хешируются настоящие пароли.
+const dummyPasswordHash = "$2a$10$gIptmoxzDwpNu0yo/ADjlOzzj3Dw/UlTsBHWPFv9q/RPp37N8OVUW"
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrInvalidMFAChallenge = errors.New("invalid or expired mfa token")
+	ErrImpersonateSelf     = errors.New("cannot impersonate yourself")
+	ErrImpersonateAdmin    = errors.New("cannot impersonate an administrator")
+
+	errInvalidToken = errors.New("invalid token")
+)
+
+// MFARequiredError возвращается при входе, если для выдачи токенов нужен второй фактор.
+// ChallengeToken предъявляется вместе с кодом на следующем шаге входа.
+// EnrollmentRequired означает, что второй фактор обязателен, но еще не настроен.
+type MFARequiredError struct {
+	ChallengeToken     string
+	EnrollmentRequired bool
+}
+
+func (e *MFARequiredError) Error() string {
+	if e.EnrollmentRequired {
+		return "two-factor enrollment required"
+	}
+	return "two-factor authentication required"
+}
+
+// ClientInfo описывает устройство, с которого выполняется вход
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
 type AuthServiceInterface interface {
-	Register(data RegisterData) (*models.TokenPair, error)
-	Login(email, password string) (*models.TokenPair, error)
-	RefreshToken(token string) (*models.TokenPair, error)
+	Register(data RegisterData, client ClientInfo) (*models.TokenPair, error)
+	Login(email, password string, client ClientInfo) (*models.TokenPair, error)
+	LoginUser(user *models.User, client ClientInfo) (*models.TokenPair, error)
+	RefreshToken(token string, client ClientInfo) (*models.TokenPair, error)
 	ValidateToken(token string) (*Claims, error)
+	Logout(refreshToken string) error
+	LogoutAll(userID uint) error
+	VerifyMFA(challengeToken, code string, client ClientInfo) (*models.TokenPair, error)
+	BeginMFAEnrollment(challengeToken string) (*TOTPEnrollment, error)
+	ConfirmMFAEnrollment(challengeToken, code string, client ClientInfo) (*models.TokenPair, []string, error)
+	Impersonate(adminID, adminSessionID, targetID uint, client ClientInfo) (string, time.Time, error)
 }
 
 type AuthService struct {
-	userRepo   *repository.UserRepository
-	jwtSecret  []byte
-	accessTTL  time.Duration
-	refreshTTL time.Duration
+	userRepo         *repository.UserRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
+	sessionRepo      *repository.SessionRepository
+	mfaService       MFAServiceInterface
+	loginThrottle    LoginThrottleServiceInterface
+	keys             *signing.KeySet
+	issuer           string
+	audience         string
+	accessTTL        time.Duration
+	refreshTTL       time.Duration
+	challengeTTL     time.Duration
+	impersonationTTL time.Duration
+	auditService     AuditServiceInterface
+	invitations      ProjectInvitationServiceInterface
 }
 
-func NewAuthService(userRepo *repository.UserRepository, jwtSecret string, accessTTL, refreshTTL time.Duration) AuthServiceInterface {
+func NewAuthService(userRepo *repository.UserRepository, refreshTokenRepo *repository.RefreshTokenRepository, sessionRepo *repository.SessionRepository, mfaService MFAServiceInterface, loginThrottle LoginThrottleServiceInterface, keys *signing.KeySet, issuer, audience string, accessTTL, refreshTTL, challengeTTL, impersonationTTL time.Duration, auditService AuditServiceInterface, invitations ProjectInvitationServiceInterface) AuthServiceInterface {
 	return &AuthService{
-		userRepo:   userRepo,
-		jwtSecret:  []byte(jwtSecret),
-		accessTTL:  accessTTL,
-		refreshTTL: refreshTTL,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		mfaService:       mfaService,
+		loginThrottle:    loginThrottle,
+		keys:             keys,
+		issuer:           issuer,
+		audience:         audience,
+		accessTTL:        accessTTL,
+		refreshTTL:       refreshTTL,
+		challengeTTL:     challengeTTL,
+		impersonationTTL: impersonationTTL,
+		auditService:     auditService,
+		invitations:      invitations,
 	}
 }
 
@@ -47,13 +128,12 @@ type RegisterData struct {
 	FirstName string
 	LastName  string
 	Phone     string
-	Role      string
 	Tags      []string
 	Country   string
 	City      string
 }
 
-func (s *AuthService) Register(data RegisterData) (*models.TokenPair, error) {
+func (s *AuthService) Register(data RegisterData, client ClientInfo) (*models.TokenPair, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(data.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -65,7 +145,7 @@ func (s *AuthService) Register(data RegisterData) (*models.TokenPair, error) {
 		FirstName:    data.FirstName,
 		LastName:     data.LastName,
 		Phone:        data.Phone,
-		Role:         data.Role,
+		Role:         policy.RoleUser,
 		Country:      data.Country,
 		City:         data.City,
 	}
@@ -74,96 +154,433 @@ func (s *AuthService) Register(data RegisterData) (*models.TokenPair, error) {
 		return nil, err
 	}
 
-	return s.generateTokenPair(user)
+	// Приглашения в проекты, отправленные на адрес до регистрации, переходят к аккаунту
+	if err := s.invitations.LinkUser(user); err != nil {
+		log.Printf("Failed to link project invitations to user %d: %v", user.ID, err)
+	}
+
+	return s.generateTokenPair(user, client)
 }
 
-func (s *AuthService) Login(email, password string) (*models.TokenPair, error) {
+// Login проверяет пароль. Неудачные попытки учитываются по аккаунту и по IP адресу;
+// пока вход временно запрещен, возвращается *LoginThrottledError.
+func (s *AuthService) Login(email, password string, client ClientInfo) (*models.TokenPair, error) {
+	if err := s.loginThrottle.Check(email, client.IP); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByEmail(email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	passwordHash := dummyPasswordHash
+	if user != nil {
+		passwordHash = user.PasswordHash
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) != nil || user == nil {
+		if err := s.loginThrottle.Fail(email, client.IP); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := s.loginThrottle.Succeed(email); err != nil {
+		return nil, err
+	}
+
+	return s.LoginUser(user, client)
+}
+
+// LoginUser выдает токены пользователю, подтвердившему первый фактор
+// (паролем или через внешнего провайдера). Если пользователю нужен второй фактор,
+// вместо токенов возвращается *MFARequiredError.
+func (s *AuthService) LoginUser(user *models.User, client ClientInfo) (*models.TokenPair, error) {
+	if user.MFAEnabled || s.mfaService.IsRequired(user) {
+		purpose := tokenPurposeMFA
+		if !user.MFAEnabled {
+			purpose = tokenPurposeMFAEnroll
+		}
+
+		challenge, err := s.generateChallengeToken(user, purpose)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &MFARequiredError{
+			ChallengeToken:     challenge,
+			EnrollmentRequired: purpose == tokenPurposeMFAEnroll,
+		}
+	}
+
+	return s.generateTokenPair(user, client)
+}
+
+// VerifyMFA завершает вход кодом из приложения или кодом восстановления.
+// Неверные коды учитываются в loginThrottle: после нескольких ошибок MFA токен
+// перестает действовать, и вход нужно начинать заново.
+func (s *AuthService) VerifyMFA(challengeToken, code string, client ClientInfo) (*models.TokenPair, error) {
+	user, challengeID, err := s.parseChallengeToken(challengeToken, tokenPurposeMFA)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.throttleMFA(user.ID, challengeID, func() error {
+		return s.mfaService.Verify(user, code)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, errors.New("invalid credentials")
+	return s.generateTokenPair(user, client)
+}
+
+// throttleMFA проверяет код через verify с учетом ограничения неверных попыток
+func (s *AuthService) throttleMFA(userID uint, challengeID string, verify func() error) error {
+	if err := s.loginThrottle.CheckMFA(userID, challengeID); err != nil {
+		return err
+	}
+
+	err := verify()
+	if errors.Is(err, ErrInvalidMFACode) {
+		if failErr := s.loginThrottle.FailMFA(userID, challengeID); failErr != nil {
+			return failErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.loginThrottle.SucceedMFA(userID)
+}
+
+// BeginMFAEnrollment начинает обязательную настройку второго фактора во время входа
+func (s *AuthService) BeginMFAEnrollment(challengeToken string) (*TOTPEnrollment, error) {
+	user, _, err := s.parseChallengeToken(challengeToken, tokenPurposeMFAEnroll)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.generateTokenPair(user)
+	return s.mfaService.BeginEnrollment(user.ID)
 }
 
-func (s *AuthService) RefreshToken(refreshToken string) (*models.TokenPair, error) {
-	token, err := jwt.Parse(refreshToken, func(token *jwt.Token) (interface{}, error) {
-		return s.jwtSecret, nil
+// ConfirmMFAEnrollment включает второй фактор и завершает вход,
+// возвращая токены и коды восстановления
+func (s *AuthService) ConfirmMFAEnrollment(challengeToken, code string, client ClientInfo) (*models.TokenPair, []string, error) {
+	user, challengeID, err := s.parseChallengeToken(challengeToken, tokenPurposeMFAEnroll)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var recoveryCodes []string
+	err = s.throttleMFA(user.ID, challengeID, func() (err error) {
+		recoveryCodes, err = s.mfaService.ConfirmEnrollment(user.ID, code)
+		return err
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.generateTokenPair(user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, recoveryCodes, nil
+}
+
+// Impersonate выдает администратору access токен для работы от имени пользователя.
+// Токен не продлевается, привязан к сессии администратора и перестает действовать
+// вместе с ней.
+func (s *AuthService) Impersonate(adminID, adminSessionID, targetID uint, client ClientInfo) (string, time.Time, error) {
+	if adminID == targetID {
+		return "", time.Time{}, ErrImpersonateSelf
+	}
+
+	target, err := s.userRepo.GetByID(targetID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if policy.GlobalRole(target.Role) == policy.RoleAdmin {
+		return "", time.Time{}, ErrImpersonateAdmin
+	}
 
+	claims, err := s.newClaims(target, s.impersonationTTL)
 	if err != nil {
+		return "", time.Time{}, err
+	}
+	claims.Email = target.Email
+	claims.Role = target.Role
+	claims.SessionID = adminSessionID
+	claims.ImpersonatorID = adminID
+
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	err = s.auditService.Record(AuditEntry{
+		Action:   AuditImpersonationStarted,
+		UserID:   &target.ID,
+		ActorID:  &adminID,
+		IP:       client.IP,
+		Metadata: map[string]interface{}{"jti": claims.ID, "expires_at": claims.ExpiresAt.Time},
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, claims.ExpiresAt.Time, nil
+}
+
+// RefreshToken обменивает refresh токен на новую пару токенов.
+// Предъявленный токен отзывается; повторное использование уже отозванного
+// токена считается утечкой и отзывает все токены его семейства.
+func (s *AuthService) RefreshToken(refreshToken string, client ClientInfo) (*models.TokenPair, error) {
+	stored, err := s.refreshTokenRepo.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
 		return nil, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return nil, errors.New("invalid token")
+	if stored.RevokedAt != nil {
+		if err := s.refreshTokenRepo.RevokeFamily(stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
 	}
 
-	userID, ok := claims["user_id"].(float64)
-	if !ok {
-		return nil, errors.New("invalid token claims")
+	user, err := s.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		return nil, err
 	}
 
-	user, err := s.userRepo.GetByID(uint(userID))
+	var tokens *models.TokenPair
+	err = s.refreshTokenRepo.DB().Transaction(func(tx *gorm.DB) error {
+		repo := s.refreshTokenRepo.WithTx(tx)
+
+		sessions := s.sessionRepo.WithTx(tx)
+		session, err := sessions.GetByFamilyID(stored.FamilyID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Семейство открыто до появления сессий
+			session = &models.Session{UserID: user.ID, FamilyID: stored.FamilyID}
+			err = sessions.Create(session)
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := sessions.Refresh(session.ID, client.IP, client.UserAgent, now, now.Add(s.refreshTTL)); err != nil {
+			return err
+		}
+
+		pair, next, err := s.issueTokenPair(repo, user, stored.FamilyID, session.ID)
+		if err != nil {
+			return err
+		}
+
+		rotated, err := repo.Rotate(stored.ID, next.ID)
+		if err != nil {
+			return err
+		}
+		if !rotated {
+			// Токен успели использовать параллельным запросом
+			return ErrRefreshTokenReused
+		}
+
+		tokens = pair
+		return nil
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		if err := s.refreshTokenRepo.RevokeFamily(stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return s.generateTokenPair(user)
+	return tokens, nil
+}
+
+// Logout отзывает семейство, к которому принадлежит refresh токен.
+func (s *AuthService) Logout(refreshToken string) error {
+	stored, err := s.refreshTokenRepo.GetByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+	return s.refreshTokenRepo.RevokeFamily(stored.FamilyID)
+}
+
+// LogoutAll отзывает все refresh токены пользователя.
+func (s *AuthService) LogoutAll(userID uint) error {
+	return s.refreshTokenRepo.RevokeAllForUser(userID)
 }
 
-func (s *AuthService) generateTokenPair(user *models.User) (*models.TokenPair, error) {
-	accessToken, err := s.generateToken(user, s.accessTTL)
+// generateTokenPair завершает вход: открывает новую сессию с новым семейством
+// refresh токенов и выдает пару токенов.
+func (s *AuthService) generateTokenPair(user *models.User, client ClientInfo) (*models.TokenPair, error) {
+	familyID, err := generateOpaqueToken(16)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := s.generateToken(user, s.refreshTTL)
+	now := time.Now()
+	var tokens *models.TokenPair
+	err = s.refreshTokenRepo.DB().Transaction(func(tx *gorm.DB) error {
+		session := &models.Session{
+			UserID:     user.ID,
+			FamilyID:   familyID,
+			UserAgent:  client.UserAgent,
+			IP:         client.IP,
+			ExpiresAt:  now.Add(s.refreshTTL),
+			LastSeenAt: now,
+		}
+		if err := s.sessionRepo.WithTx(tx).Create(session); err != nil {
+			return err
+		}
+
+		pair, _, err := s.issueTokenPair(s.refreshTokenRepo.WithTx(tx), user, familyID, session.ID)
+		if err != nil {
+			return err
+		}
+		tokens = pair
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.userRepo.UpdateLastLogin(user.ID, now); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *AuthService) issueTokenPair(repo *repository.RefreshTokenRepository, user *models.User, familyID string, sessionID uint) (*models.TokenPair, *models.RefreshToken, error) {
+	accessToken, err := s.generateToken(user, sessionID, s.accessTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := generateOpaqueToken(32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stored := &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}
+	if err := repo.Create(stored); err != nil {
+		return nil, nil, err
+	}
+
 	return &models.TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-	}, nil
+	}, stored, nil
 }
 
-func (s *AuthService) generateToken(user *models.User, ttl time.Duration) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"email":   user.Email,
-		"role":    user.Role,
-		"exp":     time.Now().Add(ttl).Unix(),
+func (s *AuthService) generateToken(user *models.User, sessionID uint, ttl time.Duration) (string, error) {
+	claims, err := s.newClaims(user, ttl)
+	if err != nil {
+		return "", err
 	}
+	claims.Email = user.Email
+	claims.Role = user.Role
+	claims.SessionID = sessionID
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
+	return s.keys.Sign(claims)
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
-	fmt.Printf("Validating token with secret: %s\n", string(s.jwtSecret))
+func (s *AuthService) generateChallengeToken(user *models.User, purpose string) (string, error) {
+	claims, err := s.newClaims(user, s.challengeTTL)
+	if err != nil {
+		return "", err
+	}
+	claims.Purpose = purpose
 
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return s.jwtSecret, nil
-	})
+	return s.keys.Sign(claims)
+}
 
+func (s *AuthService) newClaims(user *models.User, ttl time.Duration) (*Claims, error) {
+	jti, err := generateOpaqueToken(16)
 	if err != nil {
-		fmt.Printf("Token validation error: %v\n", err)
 		return nil, err
 	}
 
+	now := time.Now()
+	return &Claims{
+		UserID: user.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Audience:  jwt.ClaimStrings{s.audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        jti,
+		},
+	}, nil
+}
+
+// parseToken проверяет подпись, срок действия, издателя и получателя токена
+func (s *AuthService) parseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, s.keys.Keyfunc,
+		jwt.WithValidMethods(s.keys.ValidMethods()), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
 	if !token.Valid {
-		fmt.Printf("Token is invalid\n")
-		return nil, errors.New("invalid token")
+		return nil, errInvalidToken
+	}
+
+	// Токены, подписанные общим секретом до появления iss и aud, принимаются без них
+	// только в явно заданный переходный период
+	if s.keys.IsSymmetric(token) && claims.Issuer == "" && s.keys.InHS256Transition() {
+		return claims, nil
+	}
+	if claims.Issuer != s.issuer || !slices.Contains(claims.Audience, s.audience) {
+		return nil, errInvalidToken
+	}
+	return claims, nil
+}
+
+func (s *AuthService) parseChallengeToken(tokenString, purpose string) (*models.User, string, error) {
+	claims, err := s.parseToken(tokenString)
+	if err != nil || claims.Purpose != purpose {
+		return nil, "", ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, claims.ID, nil
+}
+
+func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != "" {
+		return nil, errInvalidToken
 	}
 
-	fmt.Printf("Token validated successfully. Claims: %+v\n", claims)
 	return claims, nil
 }
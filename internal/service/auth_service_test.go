@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/levstremilov/shance-app/internal/dbtest"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/signing"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testSecret = []byte("test-secret-test-secret-test-secret")
+
+func newTokenTestService(t *testing.T, acceptHS256Until time.Time) *AuthService {
+	t.Helper()
+
+	keys, err := signing.NewKeySet(nil, testSecret, acceptHS256Until)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	return &AuthService{keys: keys, issuer: "shance", audience: "shance-api"}
+}
+
+func signTestToken(t *testing.T, claims *Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func TestValidateTokenLegacyHS256Window(t *testing.T) {
+	legacy := signTestToken(t, &Claims{
+		UserID:           1,
+		SessionID:        1,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+
+	if _, err := newTokenTestService(t, time.Time{}).ValidateToken(legacy); err == nil {
+		t.Fatal("token without iss accepted without a transition window")
+	}
+	if _, err := newTokenTestService(t, time.Now().Add(-time.Minute)).ValidateToken(legacy); err == nil {
+		t.Fatal("token without iss accepted after the transition window")
+	}
+	if _, err := newTokenTestService(t, time.Now().Add(time.Hour)).ValidateToken(legacy); err != nil {
+		t.Fatalf("token without iss rejected inside the transition window: %v", err)
+	}
+}
+
+func TestValidateTokenChecksIssuerAndAudience(t *testing.T) {
+	s := newTokenTestService(t, time.Time{})
+	claims := func(issuer, audience string) *Claims {
+		return &Claims{
+			UserID:    1,
+			SessionID: 1,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    issuer,
+				Audience:  jwt.ClaimStrings{audience},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}
+	}
+
+	if _, err := s.ValidateToken(signTestToken(t, claims("shance", "shance-api"))); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	if _, err := s.ValidateToken(signTestToken(t, claims("other", "shance-api"))); err == nil {
+		t.Fatal("token from another issuer accepted")
+	}
+	if _, err := s.ValidateToken(signTestToken(t, claims("shance", "other-api"))); err == nil {
+		t.Fatal("token for another audience accepted")
+	}
+}
+
+func TestDummyPasswordHashMatchesRealCost(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	if err != nil || cost != bcrypt.DefaultCost {
+		t.Fatalf("dummy hash cost = %d, %v; want %d", cost, err, bcrypt.DefaultCost)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte("password")); !errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		t.Fatalf("comparison with the dummy hash = %v, want a mismatch", err)
+	}
+}
+
+func TestLoginUnknownEmailLooksLikeWrongPassword(t *testing.T) {
+	db := dbtest.Open(t, &models.User{})
+	userRepo := repository.NewUserRepository(db)
+	throttle, _ := newTestLoginThrottle(100, 100)
+	s := &AuthService{userRepo: userRepo, loginThrottle: throttle}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := userRepo.Create(&models.User{Email: "known@example.com", PasswordHash: string(hash)}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	for _, email := range []string{"known@example.com", "unknown@example.com"} {
+		if _, err := s.Login(email, "wrong", ClientInfo{IP: "192.0.2.1"}); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Login(%q) error = %v, want ErrInvalidCredentials", email, err)
+		}
+	}
+}
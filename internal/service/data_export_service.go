@@ -0,0 +1,504 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrExportNotReady = errors.New("export is not ready yet")
+	ErrExportExpired  = errors.New("export has expired")
+)
+
+type DataExportServiceInterface interface {
+	Request(userID uint) (*models.Job, error)
+	Run(ctx context.Context, job *models.Job) (string, error)
+	Open(userID, jobID uint) (string, error)
+	Purge(userID uint) error
+	ScheduleCleanup() error
+	Cleanup(ctx context.Context, job *models.Job) (string, error)
+}
+
+type DataExportService struct {
+	userRepo        *repository.UserRepository
+	projectRepo     *repository.ProjectRepository
+	vacancyRepo     *repository.ProjectVacancyRepository
+	joinRequestRepo *repository.ProjectJoinRequestRepository
+	applicationRepo *repository.VacancyApplicationRepository
+	profileRepo     *repository.ProfileRepository
+	jobRepo         *repository.JobRepository
+	auditService    AuditServiceInterface
+	dir             string
+	ttl             time.Duration
+	cleanupInterval time.Duration
+}
+
+func NewDataExportService(
+	userRepo *repository.UserRepository,
+	projectRepo *repository.ProjectRepository,
+	vacancyRepo *repository.ProjectVacancyRepository,
+	joinRequestRepo *repository.ProjectJoinRequestRepository,
+	applicationRepo *repository.VacancyApplicationRepository,
+	profileRepo *repository.ProfileRepository,
+	jobRepo *repository.JobRepository,
+	auditService AuditServiceInterface,
+	dir string,
+	ttl time.Duration,
+	cleanupInterval time.Duration,
+) DataExportServiceInterface {
+	return &DataExportService{
+		userRepo:        userRepo,
+		projectRepo:     projectRepo,
+		vacancyRepo:     vacancyRepo,
+		joinRequestRepo: joinRequestRepo,
+		applicationRepo: applicationRepo,
+		profileRepo:     profileRepo,
+		jobRepo:         jobRepo,
+		auditService:    auditService,
+		dir:             dir,
+		ttl:             ttl,
+		cleanupInterval: cleanupInterval,
+	}
+}
+
+// Request ставит в очередь выгрузку данных пользователя.
+// Если выгрузка уже готовится, возвращается ее задача.
+func (s *DataExportService) Request(userID uint) (*models.Job, error) {
+	job, err := s.jobRepo.FindActive(userID, models.JobTypeDataExport)
+	if err == nil {
+		return job, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	job = &models.Job{
+		Type:   models.JobTypeDataExport,
+		UserID: userID,
+		Status: models.JobStatusPending,
+		RunAt:  time.Now(),
+	}
+	if err := s.jobRepo.Create(job); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(AuditEntry{Action: AuditDataExportRequested, UserID: &userID}); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Run собирает zip архив с JSON файлами данных пользователя и возвращает путь к нему
+func (s *DataExportService) Run(ctx context.Context, job *models.Job) (string, error) {
+	files, err := s.collect(job.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.dir, fmt.Sprintf("export-%d-%d.zip", job.UserID, job.ID))
+
+	if err := writeArchive(path, files); err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// Open возвращает путь к готовому архиву выгрузки
+func (s *DataExportService) Open(userID, jobID uint) (string, error) {
+	job, err := s.jobRepo.GetByUserAndID(userID, jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrJobNotFound
+		}
+		return "", err
+	}
+	if job.Type != models.JobTypeDataExport {
+		return "", ErrJobNotFound
+	}
+	if job.Status != models.JobStatusCompleted || job.FinishedAt == nil {
+		return "", ErrExportNotReady
+	}
+	if time.Since(*job.FinishedAt) > s.ttl {
+		return "", ErrExportExpired
+	}
+	if _, err := os.Stat(job.Result); err != nil {
+		return "", ErrExportExpired
+	}
+	return job.Result, nil
+}
+
+// Purge удаляет все архивы выгрузок пользователя
+func (s *DataExportService) Purge(userID uint) error {
+	jobs, err := s.jobRepo.ListByStatus(userID, models.JobTypeDataExport, models.JobStatusCompleted)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Result == "" {
+			continue
+		}
+		if err := os.Remove(job.Result); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to remove export %s: %v", job.Result, err)
+		}
+	}
+	return nil
+}
+
+// ScheduleCleanup ставит в очередь задачу удаления устаревших архивов, если ее еще нет
+func (s *DataExportService) ScheduleCleanup() error {
+	_, err := s.jobRepo.FindActive(0, models.JobTypeExportCleanup)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return s.jobRepo.Create(&models.Job{
+		Type:   models.JobTypeExportCleanup,
+		Status: models.JobStatusPending,
+		RunAt:  time.Now(),
+	})
+}
+
+// Cleanup удаляет архивы, срок хранения которых истек, очищает путь к ним в задачах
+// выгрузки и планирует следующий запуск через cleanupInterval
+func (s *DataExportService) Cleanup(ctx context.Context, job *models.Job) (string, error) {
+	now := time.Now()
+	expired, err := s.jobRepo.ListExpiredResults(models.JobTypeDataExport, now.Add(-s.ttl))
+	if err != nil {
+		return "", err
+	}
+
+	removed := 0
+	for _, export := range expired {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		// Архив, который не удалось удалить, остается в задаче до следующего запуска
+		if err := os.Remove(export.Result); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to remove export %s: %v", export.Result, err)
+			continue
+		}
+		if err := s.jobRepo.ClearResult(export.ID); err != nil {
+			return "", err
+		}
+		removed++
+	}
+
+	// Следующий запуск мог уже запланировать другой экземпляр приложения
+	pending, err := s.jobRepo.ListByStatus(0, models.JobTypeExportCleanup, models.JobStatusPending)
+	if err != nil {
+		return "", err
+	}
+	if len(pending) == 0 {
+		next := &models.Job{
+			Type:   models.JobTypeExportCleanup,
+			Status: models.JobStatusPending,
+			RunAt:  now.Add(s.cleanupInterval),
+		}
+		if err := s.jobRepo.Create(next); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("removed %d exports", removed), nil
+}
+
+type exportProfile struct {
+	ID              uint       `json:"id"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	Role            string     `json:"role"`
+	Phone           string     `json:"phone"`
+	Country         string     `json:"country"`
+	City            string     `json:"city"`
+	MFAEnabled      bool       `json:"mfa_enabled"`
+	LastLogin       time.Time  `json:"last_login"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type exportProject struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Title       string    `json:"title"`
+	Subtitle    string    `json:"subtitle"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	Visibility  string    `json:"visibility"`
+	Tags        []string  `json:"tags"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type exportMembership struct {
+	ProjectID   uint      `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+type exportVacancy struct {
+	ID           uint      `json:"id"`
+	ProjectID    uint      `json:"project_id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Technologies []string  `json:"technologies"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type exportJoinRequest struct {
+	ProjectID   uint       `json:"project_id"`
+	ProjectName string     `json:"project_name"`
+	Message     string     `json:"message"`
+	Status      string     `json:"status"`
+	Reason      string     `json:"reason"`
+	ReviewedAt  *time.Time `json:"reviewed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type exportApplication struct {
+	VacancyID      uint      `json:"vacancy_id"`
+	VacancyTitle   string    `json:"vacancy_title"`
+	ProjectID      uint      `json:"project_id"`
+	ProjectName    string    `json:"project_name"`
+	CoverLetter    string    `json:"cover_letter"`
+	AttachmentName string    `json:"attachment_name"`
+	Stage          string    `json:"stage"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type exportPortfolio struct {
+	Links []exportPortfolioLink `json:"links"`
+	Files []exportPortfolioFile `json:"files"`
+}
+
+type exportPortfolioLink struct {
+	Kind      string    `json:"kind"`
+	URL       string    `json:"url"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type exportPortfolioFile struct {
+	Name        string    `json:"name"`
+	Title       string    `json:"title"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// collect собирает содержимое файлов архива: имя файла и данные для JSON
+func (s *DataExportService) collect(userID uint) (map[string]interface{}, error) {
+	user, err := s.userRepo.GetWithTags(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, len(user.Tags))
+	for i, t := range user.Tags {
+		tags[i] = t.Name
+	}
+
+	owned, err := s.userRepo.OwnProjects(userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]exportProject, len(owned))
+	projectIDs := make([]uint, len(owned))
+	for i, p := range owned {
+		projectTags := make([]string, len(p.Tags))
+		for j, t := range p.Tags {
+			projectTags[j] = t.Name
+		}
+		projects[i] = exportProject{
+			ID:          p.ID,
+			Name:        p.Name,
+			Title:       p.Title,
+			Subtitle:    p.Subtitle,
+			Description: p.Description,
+			Status:      p.Status,
+			Visibility:  p.Visibility,
+			Tags:        projectTags,
+			CreatedAt:   p.CreatedAt,
+		}
+		projectIDs[i] = p.ID
+	}
+
+	members, err := s.projectRepo.ListMemberships(userID)
+	if err != nil {
+		return nil, err
+	}
+	memberships := make([]exportMembership, len(members))
+	for i, m := range members {
+		memberships[i] = exportMembership{
+			ProjectID:   m.ProjectID,
+			ProjectName: m.Project.Name,
+			Role:        m.Role,
+			JoinedAt:    m.JoinedAt,
+		}
+	}
+
+	ownVacancies, err := s.vacancyRepo.FindByProjectIDs(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+	vacancies := make([]exportVacancy, len(ownVacancies))
+	for i, v := range ownVacancies {
+		technologies := make([]string, len(v.Technologies))
+		for j, t := range v.Technologies {
+			technologies[j] = t.Name
+		}
+		vacancies[i] = exportVacancy{
+			ID:           v.ID,
+			ProjectID:    v.ProjectID,
+			Title:        v.Title,
+			Description:  v.Description,
+			Technologies: technologies,
+			CreatedAt:    v.CreatedAt,
+		}
+	}
+
+	requests, err := s.joinRequestRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	joinRequests := make([]exportJoinRequest, len(requests))
+	for i, r := range requests {
+		joinRequests[i] = exportJoinRequest{
+			ProjectID:   r.ProjectID,
+			ProjectName: r.Project.Name,
+			Message:     r.Message,
+			Status:      r.Status,
+			Reason:      r.Reason,
+			ReviewedAt:  r.ReviewedAt,
+			CreatedAt:   r.CreatedAt,
+		}
+	}
+
+	sent, err := s.applicationRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	applications := make([]exportApplication, len(sent))
+	for i, a := range sent {
+		applications[i] = exportApplication{
+			VacancyID:      a.VacancyID,
+			VacancyTitle:   a.Vacancy.Title,
+			ProjectID:      a.ProjectID,
+			ProjectName:    a.Vacancy.Project.Name,
+			CoverLetter:    a.CoverLetter,
+			AttachmentName: a.AttachmentName,
+			Stage:          a.Stage,
+			CreatedAt:      a.CreatedAt,
+		}
+	}
+
+	links, err := s.profileRepo.ListLinks(userID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.profileRepo.ListFiles(userID)
+	if err != nil {
+		return nil, err
+	}
+	portfolio := exportPortfolio{
+		Links: make([]exportPortfolioLink, len(links)),
+		Files: make([]exportPortfolioFile, len(files)),
+	}
+	for i, l := range links {
+		portfolio.Links[i] = exportPortfolioLink{
+			Kind:      l.Kind,
+			URL:       l.URL,
+			Title:     l.Title,
+			CreatedAt: l.CreatedAt,
+		}
+	}
+	for i, f := range files {
+		portfolio.Files[i] = exportPortfolioFile{
+			Name:        f.Name,
+			Title:       f.Title,
+			ContentType: f.ContentType,
+			Size:        f.Size,
+			CreatedAt:   f.CreatedAt,
+		}
+	}
+
+	return map[string]interface{}{
+		"profile.json": exportProfile{
+			ID:              user.ID,
+			Email:           user.Email,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			FirstName:       user.FirstName,
+			LastName:        user.LastName,
+			Role:            user.Role,
+			Phone:           user.Phone,
+			Country:         user.Country,
+			City:            user.City,
+			MFAEnabled:      user.MFAEnabled,
+			LastLogin:       user.LastLogin,
+			CreatedAt:       user.CreatedAt,
+			UpdatedAt:       user.UpdatedAt,
+		},
+		"tags.json":          tags,
+		"projects.json":      projects,
+		"memberships.json":   memberships,
+		"vacancies.json":     vacancies,
+		"join_requests.json": joinRequests,
+		"applications.json":  applications,
+		"portfolio.json":     portfolio,
+	}, nil
+}
+
+// writeArchive записывает архив во временный файл и переносит его на место path,
+// чтобы незавершенный архив нельзя было скачать
+func writeArchive(path string, files map[string]interface{}) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	archive := zip.NewWriter(tmp)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(content); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/dbtest"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+)
+
+func TestDataExportCleanup(t *testing.T) {
+	db := dbtest.Open(t, &models.Job{})
+	jobRepo := repository.NewJobRepository(db)
+	dir := t.TempDir()
+	exports := NewDataExportService(nil, nil, nil, nil, nil, nil, jobRepo, nil, dir, 24*time.Hour, time.Hour)
+
+	createExport := func(userID uint, finishedAgo time.Duration, withFile bool) *models.Job {
+		t.Helper()
+		finishedAt := time.Now().Add(-finishedAgo)
+		job := &models.Job{
+			Type:       models.JobTypeDataExport,
+			UserID:     userID,
+			Status:     models.JobStatusCompleted,
+			Result:     filepath.Join(dir, fmt.Sprintf("export-%d.zip", userID)),
+			FinishedAt: &finishedAt,
+		}
+		if withFile {
+			if err := os.WriteFile(job.Result, []byte("zip"), 0o600); err != nil {
+				t.Fatalf("write archive: %v", err)
+			}
+		}
+		if err := jobRepo.Create(job); err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+		return job
+	}
+
+	expired := createExport(1, 48*time.Hour, true)
+	missing := createExport(2, 48*time.Hour, false)
+	fresh := createExport(3, time.Hour, true)
+
+	if _, err := exports.Cleanup(context.Background(), &models.Job{Type: models.JobTypeExportCleanup}); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	if _, err := os.Stat(expired.Result); !os.IsNotExist(err) {
+		t.Fatalf("expired archive was not removed: %v", err)
+	}
+	if _, err := os.Stat(fresh.Result); err != nil {
+		t.Fatalf("fresh archive was removed: %v", err)
+	}
+
+	for _, job := range []*models.Job{expired, missing, fresh} {
+		stored, err := jobRepo.GetByUserAndID(job.UserID, job.ID)
+		if err != nil {
+			t.Fatalf("load job: %v", err)
+		}
+		wantResult := ""
+		if job == fresh {
+			wantResult = fresh.Result
+		}
+		if stored.Result != wantResult {
+			t.Errorf("job %d result = %q, want %q", job.ID, stored.Result, wantResult)
+		}
+	}
+
+	next, err := jobRepo.ListByStatus(0, models.JobTypeExportCleanup, models.JobStatusPending)
+	if err != nil {
+		t.Fatalf("ListByStatus: %v", err)
+	}
+	if len(next) != 1 || next[0].RunAt.Before(time.Now().Add(50*time.Minute)) {
+		t.Fatalf("next cleanup is not scheduled an interval later: %+v", next)
+	}
+
+	// Повторный запуск не планирует вторую задачу
+	if _, err := exports.Cleanup(context.Background(), &models.Job{Type: models.JobTypeExportCleanup}); err != nil {
+		t.Fatalf("second Cleanup: %v", err)
+	}
+	if next, _ := jobRepo.ListByStatus(0, models.JobTypeExportCleanup, models.JobStatusPending); len(next) != 1 {
+		t.Fatalf("%d pending cleanup jobs, want 1", len(next))
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+type JobServiceInterface interface {
+	Get(userID, jobID uint) (*models.Job, error)
+}
+
+type JobService struct {
+	jobRepo *repository.JobRepository
+}
+
+func NewJobService(jobRepo *repository.JobRepository) JobServiceInterface {
+	return &JobService{
+		jobRepo: jobRepo,
+	}
+}
+
+// Get возвращает задачу пользователя для отслеживания ее статуса
+func (s *JobService) Get(userID, jobID uint) (*models.Job, error) {
+	job, err := s.jobRepo.GetByUserAndID(userID, jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
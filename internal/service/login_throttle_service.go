@@ -0,0 +1,266 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/mailer"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/throttle"
+	"gorm.io/gorm"
+)
+
+// LoginThrottledError возвращается, если попытки входа временно запрещены
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return "too many failed login attempts"
+}
+
+// ErrMFAChallengeExhausted возвращается, когда по MFA токену введено слишком много неверных кодов
+var ErrMFAChallengeExhausted = errors.New("too many invalid two-factor codes, sign in again")
+
+// LoginThrottleServiceInterface считает неудачные попытки входа по аккаунту и по IP адресу,
+// а также неверные коды второго фактора по пользователю и по MFA токену
+type LoginThrottleServiceInterface interface {
+	// Check возвращает *LoginThrottledError, если вход для email или IP временно запрещен
+	Check(email, ip string) error
+	Fail(email, ip string) error
+	Succeed(email string) error
+	// Unlock снимает блокировку аккаунта по ссылке из письма
+	Unlock(token string) error
+	// CheckMFA возвращает *LoginThrottledError, если ввод кодов для пользователя временно
+	// запрещен, и ErrMFAChallengeExhausted, если MFA токен больше не действует
+	CheckMFA(userID uint, challengeID string) error
+	// FailMFA учитывает неверный код; возвращает ErrMFAChallengeExhausted,
+	// если этот код исчерпал попытки MFA токена
+	FailMFA(userID uint, challengeID string) error
+	SucceedMFA(userID uint) error
+}
+
+type LoginThrottleService struct {
+	accountLimiter   *throttle.Limiter
+	ipLimiter        *throttle.Limiter
+	challengeLimiter *throttle.Limiter // блокирует MFA токен после нескольких неверных кодов
+	userRepo         *repository.UserRepository
+	tokenRepo        *repository.OneTimeTokenRepository
+	auditService     AuditServiceInterface
+	mailer           mailer.Mailer
+	publicURL        string
+	unlockTTL        time.Duration
+}
+
+func NewLoginThrottleService(
+	accountLimiter, ipLimiter, challengeLimiter *throttle.Limiter,
+	userRepo *repository.UserRepository,
+	tokenRepo *repository.OneTimeTokenRepository,
+	auditService AuditServiceInterface,
+	mailer mailer.Mailer,
+	publicURL string,
+	unlockTTL time.Duration,
+) LoginThrottleServiceInterface {
+	return &LoginThrottleService{
+		accountLimiter:   accountLimiter,
+		ipLimiter:        ipLimiter,
+		challengeLimiter: challengeLimiter,
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		auditService:     auditService,
+		mailer:           mailer,
+		publicURL:        publicURL,
+		unlockTTL:        unlockTTL,
+	}
+}
+
+func (s *LoginThrottleService) Check(email, ip string) error {
+	wait, err := s.accountLimiter.Check(accountKey(email))
+	if err != nil {
+		return err
+	}
+
+	if ip != "" {
+		ipWait, err := s.ipLimiter.Check(ipKey(ip))
+		if err != nil {
+			return err
+		}
+		if ipWait > wait {
+			wait = ipWait
+		}
+	}
+
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// Fail учитывает неудачную попытку. При блокировке аккаунта владельцу
+// отправляется письмо со ссылкой для разблокировки.
+func (s *LoginThrottleService) Fail(email, ip string) error {
+	result, err := s.accountLimiter.Fail(accountKey(email))
+	if err != nil {
+		return err
+	}
+	if result.LockedNow {
+		if err := s.onAccountLocked(email, ip, result); err != nil {
+			return err
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+
+	result, err = s.ipLimiter.Fail(ipKey(ip))
+	if err != nil {
+		return err
+	}
+	if result.LockedNow {
+		return s.auditService.Record(AuditEntry{
+			Action:   AuditIPLocked,
+			IP:       ip,
+			Metadata: map[string]interface{}{"failures": result.Failures, "locked_for": result.RetryAfter.String()},
+		})
+	}
+	return nil
+}
+
+func (s *LoginThrottleService) Succeed(email string) error {
+	return s.accountLimiter.Reset(accountKey(email))
+}
+
+func (s *LoginThrottleService) Unlock(token string) error {
+	stored, err := s.tokenRepo.Consume(models.TokenPurposeAccountUnlock, hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidOneTimeToken
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(stored.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.accountLimiter.Reset(accountKey(user.Email)); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(AuditEntry{
+		Action: AuditAccountUnlocked,
+		UserID: &user.ID,
+	})
+}
+
+func (s *LoginThrottleService) CheckMFA(userID uint, challengeID string) error {
+	blocked, err := s.challengeLimiter.Check(challengeKey(challengeID))
+	if err != nil {
+		return err
+	}
+	if blocked > 0 {
+		return ErrMFAChallengeExhausted
+	}
+
+	wait, err := s.accountLimiter.Check(mfaUserKey(userID))
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// FailMFA учитывает неверный код и по пользователю, и по MFA токену: новый токен
+// после повторного ввода пароля не обнуляет задержку для пользователя
+func (s *LoginThrottleService) FailMFA(userID uint, challengeID string) error {
+	result, err := s.accountLimiter.Fail(mfaUserKey(userID))
+	if err != nil {
+		return err
+	}
+	if result.LockedNow {
+		err := s.auditService.Record(AuditEntry{
+			Action:   AuditAccountLocked,
+			UserID:   &userID,
+			Metadata: map[string]interface{}{"failures": result.Failures, "locked_for": result.RetryAfter.String(), "factor": "mfa"},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	result, err = s.challengeLimiter.Fail(challengeKey(challengeID))
+	if err != nil {
+		return err
+	}
+	if result.RetryAfter > 0 {
+		return ErrMFAChallengeExhausted
+	}
+	return nil
+}
+
+func (s *LoginThrottleService) SucceedMFA(userID uint) error {
+	return s.accountLimiter.Reset(mfaUserKey(userID))
+}
+
+func (s *LoginThrottleService) onAccountLocked(email, ip string, result throttle.Result) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		// Попытки для несуществующих адресов тоже ограничиваются, но писать некому
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	err = s.auditService.Record(AuditEntry{
+		Action:   AuditAccountLocked,
+		UserID:   &user.ID,
+		IP:       ip,
+		Metadata: map[string]interface{}{"failures": result.Failures, "locked_for": result.RetryAfter.String()},
+	})
+	if err != nil {
+		return err
+	}
+
+	token, err := issueOneTimeToken(s.tokenRepo, user.ID, models.TokenPurposeAccountUnlock, s.unlockTTL)
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Вход в аккаунт заблокирован",
+		Body: fmt.Sprintf("Мы заблокировали вход в ваш аккаунт на %s после нескольких неудачных попыток ввода пароля.\n\nЕсли это были вы, разблокируйте вход по ссылке:\n%s\n\nЕсли нет, рекомендуем сменить пароль.",
+			result.RetryAfter.Round(time.Minute), s.publicURL+"/unlock-account?token="+url.QueryEscape(token)),
+	})
+	if err != nil {
+		// Блокировка уже действует, письмо не должно влиять на ответ
+		log.Printf("Failed to send unlock email to user %d: %v", user.ID, err)
+	}
+	return nil
+}
+
+func accountKey(email string) string {
+	return "login:account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+func mfaUserKey(userID uint) string {
+	return fmt.Sprintf("mfa:user:%d", userID)
+}
+
+func challengeKey(challengeID string) string {
+	return "mfa:challenge:" + challengeID
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/throttle"
+)
+
+type stubAuditService struct {
+	entries []AuditEntry
+}
+
+func (s *stubAuditService) Record(entry AuditEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func newTestLoginThrottle(userAttempts, challengeAttempts int) (LoginThrottleServiceInterface, *stubAuditService) {
+	store := throttle.NewMemoryStore()
+	audit := &stubAuditService{}
+	limiter := func(attempts int, delay time.Duration) *throttle.Limiter {
+		return throttle.NewLimiter(store, throttle.Config{
+			MaxAttempts: attempts,
+			BaseDelay:   delay,
+			MaxDelay:    delay,
+			Lockout:     time.Hour,
+			Window:      time.Hour,
+		})
+	}
+	return NewLoginThrottleService(limiter(userAttempts, 0), limiter(100, 0), limiter(challengeAttempts, 0),
+		nil, nil, audit, nil, "", time.Hour), audit
+}
+
+func TestMFAChallengeExhaustedAfterMaxAttempts(t *testing.T) {
+	throttleService, _ := newTestLoginThrottle(100, 3)
+
+	for i := 1; i < 3; i++ {
+		if err := throttleService.FailMFA(1, "challenge"); err != nil {
+			t.Fatalf("failure %d: %v", i, err)
+		}
+		if err := throttleService.CheckMFA(1, "challenge"); err != nil {
+			t.Fatalf("challenge rejected after %d failures: %v", i, err)
+		}
+	}
+
+	if err := throttleService.FailMFA(1, "challenge"); !errors.Is(err, ErrMFAChallengeExhausted) {
+		t.Fatalf("last failure error = %v, want ErrMFAChallengeExhausted", err)
+	}
+	if err := throttleService.CheckMFA(1, "challenge"); !errors.Is(err, ErrMFAChallengeExhausted) {
+		t.Fatalf("CheckMFA error = %v, want ErrMFAChallengeExhausted", err)
+	}
+
+	// Успешный код не возвращает к жизни исчерпанный токен, но новый токен работает
+	if err := throttleService.SucceedMFA(1); err != nil {
+		t.Fatalf("SucceedMFA: %v", err)
+	}
+	if err := throttleService.CheckMFA(1, "challenge"); !errors.Is(err, ErrMFAChallengeExhausted) {
+		t.Fatalf("exhausted challenge accepted after SucceedMFA: %v", err)
+	}
+	if err := throttleService.CheckMFA(1, "another"); err != nil {
+		t.Fatalf("new challenge rejected: %v", err)
+	}
+}
+
+func TestMFAFailuresCountPerUserAcrossChallenges(t *testing.T) {
+	throttleService, audit := newTestLoginThrottle(2, 100)
+
+	if err := throttleService.FailMFA(7, "first"); err != nil {
+		t.Fatalf("FailMFA: %v", err)
+	}
+	if err := throttleService.FailMFA(7, "second"); err != nil {
+		t.Fatalf("FailMFA: %v", err)
+	}
+
+	var throttled *LoginThrottledError
+	if err := throttleService.CheckMFA(7, "third"); !errors.As(err, &throttled) {
+		t.Fatalf("CheckMFA error = %v, want *LoginThrottledError", err)
+	}
+	if len(audit.entries) != 1 || audit.entries[0].Action != AuditAccountLocked {
+		t.Fatalf("audit entries = %+v, want one account lock", audit.entries)
+	}
+
+	if err := throttleService.CheckMFA(8, "third"); err != nil {
+		t.Fatalf("other user is throttled: %v", err)
+	}
+}
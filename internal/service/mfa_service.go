@@ -0,0 +1,229 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/totp"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrMFAEnrollmentEmpty = errors.New("two-factor enrollment was not started")
+	ErrInvalidMFACode     = errors.New("invalid two-factor code")
+	ErrMFARequiredForRole = errors.New("two-factor authentication is required for your role")
+)
+
+// TOTPEnrollment — данные для добавления аккаунта в приложение-аутентификатор
+type TOTPEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+type MFAServiceInterface interface {
+	BeginEnrollment(userID uint) (*TOTPEnrollment, error)
+	ConfirmEnrollment(userID uint, code string) ([]string, error)
+	Disable(userID uint, code string) error
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+	Verify(user *models.User, code string) error
+	IsRequired(user *models.User) bool
+}
+
+type MFAService struct {
+	userRepo         *repository.UserRepository
+	recoveryCodeRepo *repository.RecoveryCodeRepository
+	issuer           string
+	requireForAdmins bool
+}
+
+func NewMFAService(userRepo *repository.UserRepository, recoveryCodeRepo *repository.RecoveryCodeRepository, issuer string, requireForAdmins bool) MFAServiceInterface {
+	return &MFAService{
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		issuer:           issuer,
+		requireForAdmins: requireForAdmins,
+	}
+}
+
+// BeginEnrollment генерирует новый секрет. Второй фактор включается
+// только после подтверждения кодом из приложения.
+func (s *MFAService) BeginEnrollment(userID uint) (*TOTPEnrollment, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(user, "totp_secret", "totp_last_step"); err != nil {
+		return nil, err
+	}
+
+	return &TOTPEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment включает второй фактор и возвращает коды восстановления
+func (s *MFAService) ConfirmEnrollment(userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFAEnrollmentEmpty
+	}
+
+	step, ok := totp.Verify(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	// Код подтверждения, как и код при входе, принимается один раз
+	advanced, err := s.userRepo.AdvanceTOTPStep(user.ID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !advanced {
+		return nil, ErrInvalidMFACode
+	}
+	user.MFAEnabled = true
+	user.TOTPLastStep = step
+	if err := s.userRepo.Update(user, "mfa_enabled"); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(user.ID)
+}
+
+func (s *MFAService) Disable(userID uint, code string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+	if s.IsRequired(user) {
+		return ErrMFARequiredForRole
+	}
+
+	if err := s.Verify(user, code); err != nil {
+		return err
+	}
+
+	user.MFAEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	if err := s.userRepo.Update(user, "mfa_enabled", "totp_secret", "totp_last_step"); err != nil {
+		return err
+	}
+
+	return s.recoveryCodeRepo.DeleteAll(user.ID)
+}
+
+func (s *MFAService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, ErrMFANotEnabled
+	}
+
+	if err := s.Verify(user, code); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(user.ID)
+}
+
+// Verify принимает код из приложения или один из кодов восстановления.
+// Код из приложения нельзя использовать повторно.
+func (s *MFAService) Verify(user *models.User, code string) error {
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	if step, ok := totp.Verify(user.TOTPSecret, code, time.Now()); ok {
+		// Шаг сравнивается в самом UPDATE: два параллельных запроса с одним кодом
+		// не пройдут оба
+		advanced, err := s.userRepo.AdvanceTOTPStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			return ErrInvalidMFACode
+		}
+		user.TOTPLastStep = step
+		return nil
+	}
+
+	used, err := s.recoveryCodeRepo.Consume(user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// IsRequired сообщает, обязателен ли второй фактор для пользователя
+func (s *MFAService) IsRequired(user *models.User) bool {
+	return s.requireForAdmins && policy.GlobalRole(user.Role) == policy.RoleAdmin
+}
+
+func (s *MFAService) issueRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashToken(normalizeRecoveryCode(code))
+	}
+
+	if err := s.recoveryCodeRepo.Replace(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode возвращает код вида xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 7)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/dbtest"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/totp"
+)
+
+func TestMFAVerifyRejectsReplayedCode(t *testing.T) {
+	db := dbtest.Open(t, &models.User{}, &models.MFARecoveryCode{})
+	userRepo := repository.NewUserRepository(db)
+	mfa := NewMFAService(userRepo, repository.NewRecoveryCodeRepository(db), "Shance", false)
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	user := &models.User{Email: "mfa@example.com", PasswordHash: "hash", MFAEnabled: true, TOTPSecret: secret}
+	if err := userRepo.Create(user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	code, err := totp.CodeAt(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatalf("CodeAt: %v", err)
+	}
+
+	// Два запроса прочитали пользователя до того, как любой из них принял код
+	first, err := userRepo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	second, err := userRepo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+
+	if err := mfa.Verify(first, code); err != nil {
+		t.Fatalf("first Verify: %v", err)
+	}
+	if err := mfa.Verify(second, code); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("replayed Verify error = %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestStaleUserUpdateKeepsTOTPStep(t *testing.T) {
+	db := dbtest.Open(t, &models.User{}, &models.MFARecoveryCode{})
+	userRepo := repository.NewUserRepository(db)
+	mfa := NewMFAService(userRepo, repository.NewRecoveryCodeRepository(db), "Shance", false)
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	user := &models.User{Email: "stale@example.com", PasswordHash: "hash", MFAEnabled: true, TOTPSecret: secret}
+	if err := userRepo.Create(user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	step := totp.Step(time.Now())
+	code, err := totp.CodeAt(secret, step)
+	if err != nil {
+		t.Fatalf("CodeAt: %v", err)
+	}
+
+	// Профиль прочитан до входа, а сохранен после того, как вход принял код
+	stale, err := userRepo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if err := mfa.Verify(user, code); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	stale.FirstName = "Иван"
+	if err := userRepo.Update(stale, "first_name"); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	stored, err := userRepo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.FirstName != "Иван" || stored.TOTPLastStep != step {
+		t.Fatalf("stored first name %q, totp step %d", stored.FirstName, stored.TOTPLastStep)
+	}
+	if err := mfa.Verify(stored, code); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("code accepted again after a stale update: %v", err)
+	}
+}
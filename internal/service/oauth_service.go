@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/oauth"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownOAuthProvider  = errors.New("unknown oauth provider")
+	ErrInvalidOAuthState     = errors.New("invalid or expired oauth state")
+	ErrInvalidRedirectURI    = errors.New("redirect uri is not allowed")
+	ErrOAuthEmailMissing     = errors.New("provider did not return an email address")
+	ErrOAuthEmailNotVerified = errors.New("email is not verified by the provider, cannot link it to an existing account")
+)
+
+type OAuthServiceInterface interface {
+	Providers() []string
+	AuthorizationURL(provider, redirectURI string) (*OAuthAuthorization, error)
+	Callback(ctx context.Context, provider, state, browserState, code string, client ClientInfo) (*models.TokenPair, string, error)
+}
+
+// OAuthAuthorization — начатый вход через внешнего провайдера
+type OAuthAuthorization struct {
+	// URL — страница авторизации провайдера, на которую перенаправляется пользователь
+	URL string
+	// State сохраняется в браузере, начавшем вход, до ExpiresAt и передается
+	// в Callback вместе с state из адреса возврата
+	State     string
+	ExpiresAt time.Time
+}
+
+type OAuthService struct {
+	providers    map[string]*oauth.Provider
+	identityRepo *repository.IdentityRepository
+	userRepo     *repository.UserRepository
+	authService  AuthServiceInterface
+	invitations  ProjectInvitationServiceInterface
+	publicURL    string
+	stateTTL     time.Duration
+}
+
+func NewOAuthService(
+	providers []*oauth.Provider,
+	identityRepo *repository.IdentityRepository,
+	userRepo *repository.UserRepository,
+	authService AuthServiceInterface,
+	invitations ProjectInvitationServiceInterface,
+	publicURL string,
+	stateTTL time.Duration,
+) OAuthServiceInterface {
+	byName := make(map[string]*oauth.Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+
+	return &OAuthService{
+		providers:    byName,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		invitations:  invitations,
+		publicURL:    publicURL,
+		stateTTL:     stateTTL,
+	}
+}
+
+func (s *OAuthService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AuthorizationURL начинает вход через провайдера: сохраняет state и PKCE verifier
+// и возвращает адрес, на который нужно перенаправить пользователя.
+func (s *OAuthService) AuthorizationURL(providerName, redirectURI string) (*OAuthAuthorization, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownOAuthProvider
+	}
+
+	if redirectURI != "" && !s.isAllowedRedirect(redirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	state, err := generateOpaqueToken(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+	expiresAt := time.Now().Add(s.stateTTL)
+
+	err = s.identityRepo.CreateState(&models.OAuthState{
+		StateHash:    hashToken(state),
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		RedirectURI:  redirectURI,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthAuthorization{
+		URL:       provider.AuthCodeURL(state, verifier),
+		State:     state,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Callback завершает вход: обменивает код, находит или создает пользователя
+// и выдает ему пару токенов. Вторым значением возвращается redirect_uri,
+// переданный при начале входа.
+//
+// browserState — state, сохраненный в браузере при начале входа. Он должен совпасть
+// со state из адреса возврата: иначе злоумышленник мог бы подсунуть пользователю
+// ссылку на callback своего входа и авторизовать его в чужом аккаунте.
+func (s *OAuthService) Callback(ctx context.Context, providerName, state, browserState, code string, client ClientInfo) (*models.TokenPair, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, "", ErrUnknownOAuthProvider
+	}
+	if browserState == "" || subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		return nil, "", ErrInvalidOAuthState
+	}
+
+	stored, err := s.identityRepo.ConsumeState(hashToken(state))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrInvalidOAuthState
+		}
+		return nil, "", err
+	}
+	if stored.Provider != providerName {
+		return nil, "", ErrInvalidOAuthState
+	}
+
+	info, err := provider.Exchange(ctx, code, stored.CodeVerifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.resolveUser(providerName, info)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// redirect_uri нужен и при ошибке: при обязательном втором факторе
+	// фронтенд получает MFA токен по тому же адресу
+	tokens, err := s.authService.LoginUser(user, client)
+	if err != nil {
+		return nil, stored.RedirectURI, err
+	}
+
+	return tokens, stored.RedirectURI, nil
+}
+
+// resolveUser находит пользователя по внешней учетной записи. Если связи еще нет,
+// привязывает учетную запись к пользователю с тем же подтвержденным email
+// или регистрирует нового пользователя.
+func (s *OAuthService) resolveUser(providerName string, info *oauth.UserInfo) (*models.User, error) {
+	identity, err := s.identityRepo.GetByProviderSubject(providerName, info.Subject)
+	if err == nil {
+		return &identity.User, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if info.Email == "" {
+		return nil, ErrOAuthEmailMissing
+	}
+
+	identity = &models.UserIdentity{
+		Provider: providerName,
+		Subject:  info.Subject,
+		Email:    info.Email,
+	}
+
+	user, err := s.userRepo.GetByEmail(info.Email)
+	if err == nil {
+		if !info.EmailVerified {
+			return nil, ErrOAuthEmailNotVerified
+		}
+		if user.EmailVerifiedAt == nil {
+			// Адрес никто не подтверждал, поэтому пароль мог задать посторонний:
+			// сбрасываем его, владельцем аккаунта становится владелец адреса
+			passwordHash, err := unusablePasswordHash()
+			if err != nil {
+				return nil, err
+			}
+			now := time.Now()
+			user.PasswordHash = passwordHash
+			user.EmailVerifiedAt = &now
+			if err := s.userRepo.Update(user, "password_hash", "email_verified_at"); err != nil {
+				return nil, err
+			}
+		}
+
+		identity.UserID = user.ID
+		if err := s.identityRepo.Create(identity); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	user, err = s.newUser(info)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.identityRepo.CreateWithUser(user, identity); err != nil {
+		return nil, err
+	}
+	if err := s.invitations.LinkUser(user); err != nil {
+		log.Printf("Failed to link project invitations to user %d: %v", user.ID, err)
+	}
+	return user, nil
+}
+
+func (s *OAuthService) newUser(info *oauth.UserInfo) (*models.User, error) {
+	passwordHash, err := unusablePasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:        info.Email,
+		PasswordHash: passwordHash,
+		FirstName:    info.FirstName,
+		LastName:     info.LastName,
+	}
+	if info.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	return user, nil
+}
+
+func (s *OAuthService) isAllowedRedirect(redirectURI string) bool {
+	base := strings.TrimSuffix(s.publicURL, "/")
+	return redirectURI == base || strings.HasPrefix(redirectURI, base+"/")
+}
+
+// unusablePasswordHash возвращает хеш случайного пароля, неизвестного пользователю.
+// Задать собственный пароль можно через сброс пароля.
+func unusablePasswordHash() (string, error) {
+	password, err := generateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
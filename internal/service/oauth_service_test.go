@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/dbtest"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/oauth"
+	"github.com/levstremilov/shance-app/internal/oauth/oauthtest"
+	"github.com/levstremilov/shance-app/internal/repository"
+)
+
+// stubAuthService выдает токены с ID пользователя вместо настоящей сессии
+type stubAuthService struct {
+	AuthServiceInterface
+	loggedIn []*models.User
+}
+
+func (s *stubAuthService) LoginUser(user *models.User, client ClientInfo) (*models.TokenPair, error) {
+	s.loggedIn = append(s.loggedIn, user)
+	return &models.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+type stubInvitationService struct {
+	ProjectInvitationServiceInterface
+	linked []uint
+}
+
+func (s *stubInvitationService) LinkUser(user *models.User) error {
+	s.linked = append(s.linked, user.ID)
+	return nil
+}
+
+type oauthTestEnv struct {
+	service     OAuthServiceInterface
+	provider    *oauthtest.Server
+	userRepo    *repository.UserRepository
+	auth        *stubAuthService
+	invitations *stubInvitationService
+}
+
+func newOAuthTestEnv(t *testing.T, claims oauthtest.Claims) *oauthTestEnv {
+	t.Helper()
+
+	db := dbtest.Open(t, &models.User{}, &models.UserIdentity{}, &models.OAuthState{})
+	srv := oauthtest.NewServer(t, claims)
+
+	provider, err := oauth.NewProvider(context.Background(), oauth.ProviderConfig{
+		Name:        "test",
+		Kind:        oauth.KindOIDC,
+		Issuer:      srv.URL,
+		ClientID:    "client",
+		RedirectURL: "http://app.local/api/auth/oauth/test/callback",
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+
+	env := &oauthTestEnv{
+		provider:    srv,
+		userRepo:    repository.NewUserRepository(db),
+		auth:        &stubAuthService{},
+		invitations: &stubInvitationService{},
+	}
+	env.service = NewOAuthService(
+		[]*oauth.Provider{provider},
+		repository.NewIdentityRepository(db),
+		env.userRepo,
+		env.auth,
+		env.invitations,
+		"http://app.local",
+		10*time.Minute,
+	)
+	return env
+}
+
+// login проходит вход целиком: начало входа, согласие у провайдера и callback.
+// browserState — значение cookie, с которым браузер возвращается на callback.
+func (e *oauthTestEnv) login(t *testing.T, browserState func(started string) string) (*models.TokenPair, string, error) {
+	t.Helper()
+
+	authorization, err := e.service.AuthorizationURL("test", "http://app.local/oauth/done")
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	if got := authURLState(t, authorization.URL); got != authorization.State {
+		t.Fatalf("auth url state %q differs from the returned state %q", got, authorization.State)
+	}
+
+	code, state := e.provider.Authorize(t, authorization.URL)
+	return e.service.Callback(context.Background(), "test", state, browserState(authorization.State), code, ClientInfo{})
+}
+
+func sameBrowser(started string) string { return started }
+
+func authURLState(t *testing.T, authURL string) string {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid auth url: %v", err)
+	}
+	return u.Query().Get("state")
+}
+
+func TestOAuthCallbackCreatesUser(t *testing.T) {
+	env := newOAuthTestEnv(t, oauthtest.Claims{
+		Subject:       "sub-1",
+		Email:         "new@example.com",
+		EmailVerified: true,
+		GivenName:     "Анна",
+		FamilyName:    "Смирнова",
+	})
+
+	tokens, redirectURI, err := env.login(t, sameBrowser)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if tokens == nil || redirectURI != "http://app.local/oauth/done" {
+		t.Fatalf("Callback returned tokens %v, redirect %q", tokens, redirectURI)
+	}
+
+	user, err := env.userRepo.GetByEmail("new@example.com")
+	if err != nil {
+		t.Fatalf("user was not created: %v", err)
+	}
+	if user.FirstName != "Анна" || user.LastName != "Смирнова" || user.EmailVerifiedAt == nil {
+		t.Fatalf("unexpected user %+v", user)
+	}
+	if len(env.invitations.linked) != 1 || env.invitations.linked[0] != user.ID {
+		t.Fatalf("invitations linked to %v, want [%d]", env.invitations.linked, user.ID)
+	}
+
+	// Повторный вход находит пользователя по внешней учетной записи
+	if _, _, err := env.login(t, sameBrowser); err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if len(env.auth.loggedIn) != 2 || env.auth.loggedIn[1].ID != user.ID {
+		t.Fatalf("second login resolved another user")
+	}
+}
+
+func TestOAuthCallbackLinksVerifiedEmail(t *testing.T) {
+	env := newOAuthTestEnv(t, oauthtest.Claims{Subject: "sub-2", Email: "owner@example.com", EmailVerified: true})
+
+	existing := &models.User{Email: "owner@example.com", PasswordHash: "set-by-someone"}
+	if err := env.userRepo.Create(existing); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	if _, _, err := env.login(t, sameBrowser); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if len(env.auth.loggedIn) != 1 || env.auth.loggedIn[0].ID != existing.ID {
+		t.Fatalf("identity was not linked to the existing user")
+	}
+
+	user, err := env.userRepo.GetByID(existing.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	// Адрес не был подтвержден, поэтому пароль, заданный до привязки, сбрасывается
+	if user.EmailVerifiedAt == nil || user.PasswordHash == "set-by-someone" {
+		t.Fatalf("unverified account was linked without resetting the password")
+	}
+	if len(env.invitations.linked) != 0 {
+		t.Fatalf("invitations linked for an existing user")
+	}
+}
+
+func TestOAuthCallbackRejectsUnverifiedEmailOfExistingUser(t *testing.T) {
+	env := newOAuthTestEnv(t, oauthtest.Claims{Subject: "sub-3", Email: "owner@example.com"})
+
+	if err := env.userRepo.Create(&models.User{Email: "owner@example.com", PasswordHash: "hash"}); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	if _, _, err := env.login(t, sameBrowser); !errors.Is(err, ErrOAuthEmailNotVerified) {
+		t.Fatalf("Callback error = %v, want ErrOAuthEmailNotVerified", err)
+	}
+}
+
+func TestOAuthCallbackRejectsStateFromAnotherBrowser(t *testing.T) {
+	env := newOAuthTestEnv(t, oauthtest.Claims{Subject: "sub-4", Email: "victim@example.com", EmailVerified: true})
+
+	cases := map[string]func(string) string{
+		"no cookie":      func(string) string { return "" },
+		"another cookie": func(string) string { return "state-of-another-login" },
+	}
+	for name, browserState := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := env.login(t, browserState); !errors.Is(err, ErrInvalidOAuthState) {
+				t.Fatalf("Callback error = %v, want ErrInvalidOAuthState", err)
+			}
+		})
+	}
+
+	if len(env.auth.loggedIn) != 0 {
+		t.Fatal("user was logged in with a foreign state")
+	}
+	if _, err := env.userRepo.GetByEmail("victim@example.com"); err == nil {
+		t.Fatal("user was created with a foreign state")
+	}
+}
+
+func TestOAuthCallbackStateIsSingleUse(t *testing.T) {
+	env := newOAuthTestEnv(t, oauthtest.Claims{Subject: "sub-5", Email: "user@example.com", EmailVerified: true})
+
+	authorization, err := env.service.AuthorizationURL("test", "")
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+	code, state := env.provider.Authorize(t, authorization.URL)
+	if _, _, err := env.service.Callback(context.Background(), "test", state, authorization.State, code, ClientInfo{}); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if _, _, err := env.service.Callback(context.Background(), "test", state, authorization.State, code, ClientInfo{}); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Fatalf("replayed Callback error = %v, want ErrInvalidOAuthState", err)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Области действия персональных токенов
+const (
+	ScopeProjectsRead   = "projects:read"
+	ScopeProjectsWrite  = "projects:write"
+	ScopeVacanciesRead  = "vacancies:read"
+	ScopeVacanciesWrite = "vacancies:write"
+	ScopeTagsRead       = "tags:read"
+	ScopeTagsWrite      = "tags:write"
+	ScopeUsersRead      = "users:read"
+	ScopeUsersWrite     = "users:write"
+)
+
+var AllScopes = []string{
+	ScopeProjectsRead,
+	ScopeProjectsWrite,
+	ScopeVacanciesRead,
+	ScopeVacanciesWrite,
+	ScopeTagsRead,
+	ScopeTagsWrite,
+	ScopeUsersRead,
+	ScopeUsersWrite,
+}
+
+// PersonalAccessTokenPrefix отличает персональные токены от JWT
+const PersonalAccessTokenPrefix = "shp_"
+
+const lastUsedInterval = time.Minute
+
+var (
+	ErrInvalidScope               = errors.New("invalid scope")
+	ErrInvalidTokenExpiry         = errors.New("expiry must be in the future")
+	ErrInvalidPersonalAccessToken = errors.New("invalid personal access token")
+	ErrPersonalAccessTokenMissing = errors.New("personal access token not found")
+)
+
+type PersonalAccessTokenServiceInterface interface {
+	Create(userID uint, name string, scopes []string, expiresAt *time.Time) (*models.PersonalAccessToken, string, error)
+	List(userID uint) ([]models.PersonalAccessToken, error)
+	Revoke(userID, tokenID uint) error
+	Authenticate(token string) (*models.PersonalAccessToken, error)
+}
+
+type PersonalAccessTokenService struct {
+	tokenRepo *repository.PersonalAccessTokenRepository
+}
+
+func NewPersonalAccessTokenService(tokenRepo *repository.PersonalAccessTokenRepository) PersonalAccessTokenServiceInterface {
+	return &PersonalAccessTokenService{
+		tokenRepo: tokenRepo,
+	}
+}
+
+// Create выпускает токен. Значение токена возвращается только здесь, в базе хранится хеш.
+func (s *PersonalAccessTokenService) Create(userID uint, name string, scopes []string, expiresAt *time.Time) (*models.PersonalAccessToken, string, error) {
+	for _, scope := range scopes {
+		if !IsValidScope(scope) {
+			return nil, "", ErrInvalidScope
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidTokenExpiry
+	}
+
+	secret, err := generateOpaqueToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	value := PersonalAccessTokenPrefix + secret
+
+	token := &models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    value[:len(PersonalAccessTokenPrefix)+6],
+		TokenHash: hashToken(value),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, "", err
+	}
+
+	return token, value, nil
+}
+
+func (s *PersonalAccessTokenService) List(userID uint) ([]models.PersonalAccessToken, error) {
+	return s.tokenRepo.ListByUserID(userID)
+}
+
+func (s *PersonalAccessTokenService) Revoke(userID, tokenID uint) error {
+	err := s.tokenRepo.Delete(userID, tokenID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPersonalAccessTokenMissing
+	}
+	return err
+}
+
+// Authenticate проверяет токен и отмечает время его использования
+func (s *PersonalAccessTokenService) Authenticate(value string) (*models.PersonalAccessToken, error) {
+	if !strings.HasPrefix(value, PersonalAccessTokenPrefix) {
+		return nil, ErrInvalidPersonalAccessToken
+	}
+
+	token, err := s.tokenRepo.GetByHash(hashToken(value))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidPersonalAccessToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return nil, ErrInvalidPersonalAccessToken
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(token.ID, now, lastUsedInterval); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func IsValidScope(scope string) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/levstremilov/shance-app/internal/imaging"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/storage"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAvatarNotFound           = errors.New("avatar not found")
+	ErrAvatarTooLarge           = errors.New("avatar is too large")
+	ErrUnsupportedAvatarType    = errors.New("avatar must be a JPEG, PNG or WebP image")
+	ErrPortfolioLinkNotFound    = errors.New("portfolio link not found")
+	ErrInvalidPortfolioLink     = errors.New("link must be an absolute http or https URL")
+	ErrTooManyPortfolioLinks    = errors.New("portfolio has the maximum number of links")
+	ErrPortfolioFileNotFound    = errors.New("portfolio file not found")
+	ErrPortfolioFileTooLarge    = errors.New("file is too large")
+	ErrUnsupportedPortfolioFile = errors.New("file must be a PDF document")
+	ErrTooManyPortfolioFiles    = errors.New("portfolio has the maximum number of files")
+	ErrPortfolioTitleTooLong    = errors.New("title is too long")
+)
+
+const (
+	maxPortfolioLinks     = 20
+	maxPortfolioURLLength = 2048
+	// Ограничение длины названий ссылок и файлов и имен файлов, в символах
+	maxPortfolioTitleLength = 200
+)
+
+// portfolioFileExtensions — типы файлов портфолио, определяемые по содержимому,
+// и расширения их файлов в хранилище
+var portfolioFileExtensions = map[string]string{
+	"application/pdf": ".pdf",
+}
+
+// portfolioLinkHosts — сайты, по адресу которых определяется вид ссылки портфолио
+var portfolioLinkHosts = map[string]string{
+	"github.com":  models.PortfolioLinkGitHub,
+	"behance.net": models.PortfolioLinkBehance,
+}
+
+type ProfileServiceInterface interface {
+	GetProfile(userID uint) (*models.User, error)
+	UploadAvatar(ctx context.Context, userID uint, body io.Reader) (*models.UserAvatar, error)
+	DeleteAvatar(ctx context.Context, userID uint) error
+	AddLink(userID uint, rawURL, title string) (*models.PortfolioLink, error)
+	UpdateLink(userID, linkID uint, rawURL, title string) (*models.PortfolioLink, error)
+	DeleteLink(userID, linkID uint) error
+	UploadFile(ctx context.Context, userID uint, name, title string, body io.Reader) (*models.PortfolioFile, error)
+	DeleteFile(ctx context.Context, userID, fileID uint) error
+	Purge(ctx context.Context, userID uint) error
+	URL(key string) (string, error)
+}
+
+// ProfileService управляет аватарами и портфолио пользователей
+type ProfileService struct {
+	profileRepo   *repository.ProfileRepository
+	userRepo      *repository.UserRepository
+	storage       storage.Storage
+	maxAvatarSize int64
+	maxFileSize   int64
+	maxFiles      int
+}
+
+func NewProfileService(
+	profileRepo *repository.ProfileRepository,
+	userRepo *repository.UserRepository,
+	files storage.Storage,
+	maxAvatarSize int64,
+	maxFileSize int64,
+	maxPortfolioFiles int,
+) ProfileServiceInterface {
+	return &ProfileService{
+		profileRepo:   profileRepo,
+		userRepo:      userRepo,
+		storage:       files,
+		maxAvatarSize: maxAvatarSize,
+		maxFileSize:   maxFileSize,
+		maxFiles:      maxPortfolioFiles,
+	}
+}
+
+// GetProfile возвращает пользователя с аватаром и портфолио
+func (s *ProfileService) GetProfile(userID uint) (*models.User, error) {
+	return s.userRepo.GetProfile(userID)
+}
+
+// UploadAvatar обрезает изображение по центру до квадрата, создает уменьшенные
+// варианты без метаданных и заменяет ими прежний аватар пользователя
+func (s *ProfileService) UploadAvatar(ctx context.Context, userID uint, body io.Reader) (*models.UserAvatar, error) {
+	data, err := io.ReadAll(io.LimitReader(body, s.maxAvatarSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxAvatarSize {
+		return nil, ErrAvatarTooLarge
+	}
+	if !allowedPhotoTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedAvatarType
+	}
+
+	processed, err := imaging.ProcessSquare(data, imaging.AvatarSizes)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrUnsupportedFormat):
+			return nil, ErrUnsupportedAvatarType
+		case errors.Is(err, imaging.ErrImageTooLarge):
+			return nil, ErrAvatarTooLarge
+		}
+		return nil, err
+	}
+
+	name, err := generateOpaqueToken(16)
+	if err != nil {
+		return nil, err
+	}
+	avatar := &models.UserAvatar{
+		UserID:   userID,
+		Width:    processed.Width,
+		Height:   processed.Height,
+		Blurhash: processed.Blurhash,
+	}
+
+	var stored []string
+	for _, variant := range processed.Variants {
+		key := fmt.Sprintf("users/%d/avatar/%s_%s%s", userID, name, variant.Name, imaging.Extension(variant.Format))
+		if err := s.storage.Put(ctx, key, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType); err != nil {
+			removeOrphanedFiles(s.storage, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+
+		avatar.Variants = append(avatar.Variants, models.UserAvatarVariant{
+			Name:        variant.Name,
+			Format:      variant.Format,
+			Key:         key,
+			ContentType: variant.ContentType,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			Size:        int64(len(variant.Data)),
+		})
+	}
+
+	previous, err := s.profileRepo.ReplaceAvatar(avatar)
+	if err != nil {
+		removeOrphanedFiles(s.storage, stored)
+		return nil, err
+	}
+	if previous != nil {
+		removeOrphanedFiles(s.storage, avatarKeys(previous))
+	}
+	return avatar, nil
+}
+
+// DeleteAvatar удаляет аватар пользователя и файлы всех его вариантов
+func (s *ProfileService) DeleteAvatar(ctx context.Context, userID uint) error {
+	avatar, err := s.profileRepo.GetAvatar(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAvatarNotFound
+		}
+		return err
+	}
+	if err := s.profileRepo.DeleteAvatar(avatar); err != nil {
+		return err
+	}
+	for _, key := range avatarKeys(avatar) {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddLink добавляет ссылку в портфолио; вид ссылки определяется по ее адресу
+func (s *ProfileService) AddLink(userID uint, rawURL, title string) (*models.PortfolioLink, error) {
+	link := &models.PortfolioLink{UserID: userID}
+	if err := setPortfolioLink(link, rawURL, title); err != nil {
+		return nil, err
+	}
+
+	count, err := s.profileRepo.CountLinks(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxPortfolioLinks {
+		return nil, ErrTooManyPortfolioLinks
+	}
+
+	if err := s.profileRepo.CreateLink(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *ProfileService) UpdateLink(userID, linkID uint, rawURL, title string) (*models.PortfolioLink, error) {
+	link, err := s.profileRepo.GetLink(userID, linkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPortfolioLinkNotFound
+		}
+		return nil, err
+	}
+	if err := setPortfolioLink(link, rawURL, title); err != nil {
+		return nil, err
+	}
+	if err := s.profileRepo.UpdateLink(link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *ProfileService) DeleteLink(userID, linkID uint) error {
+	link, err := s.profileRepo.GetLink(userID, linkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPortfolioLinkNotFound
+		}
+		return err
+	}
+	return s.profileRepo.DeleteLink(link)
+}
+
+// UploadFile проверяет размер и тип файла по его содержимому и добавляет файл в портфолио
+func (s *ProfileService) UploadFile(ctx context.Context, userID uint, name, title string, body io.Reader) (*models.PortfolioFile, error) {
+	// Из имени файла убирается путь, который передают некоторые браузеры
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "." || name == "/" {
+		name = ""
+	}
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(name) > maxPortfolioTitleLength || utf8.RuneCountInString(title) > maxPortfolioTitleLength {
+		return nil, ErrPortfolioTitleTooLong
+	}
+
+	count, err := s.profileRepo.CountFiles(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= int64(s.maxFiles) {
+		return nil, ErrTooManyPortfolioFiles
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, s.maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxFileSize {
+		return nil, ErrPortfolioFileTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := portfolioFileExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedPortfolioFile
+	}
+
+	token, err := generateOpaqueToken(16)
+	if err != nil {
+		return nil, err
+	}
+	file := &models.PortfolioFile{
+		UserID:      userID,
+		Key:         fmt.Sprintf("users/%d/portfolio/%s%s", userID, token, ext),
+		Name:        name,
+		Title:       title,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+	if err := s.storage.Put(ctx, file.Key, bytes.NewReader(data), file.Size, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.profileRepo.CreateFile(file); err != nil {
+		removeOrphanedFiles(s.storage, []string{file.Key})
+		return nil, err
+	}
+	return file, nil
+}
+
+// DeleteFile удаляет файл из портфолио и из хранилища
+func (s *ProfileService) DeleteFile(ctx context.Context, userID, fileID uint) error {
+	file, err := s.profileRepo.GetFile(userID, fileID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPortfolioFileNotFound
+		}
+		return err
+	}
+	if err := s.profileRepo.DeleteFile(file); err != nil {
+		return err
+	}
+	return s.storage.Delete(ctx, file.Key)
+}
+
+// Purge удаляет аватар и портфолио пользователя вместе с файлами
+func (s *ProfileService) Purge(ctx context.Context, userID uint) error {
+	var keys []string
+	avatar, err := s.profileRepo.GetAvatar(userID)
+	switch {
+	case err == nil:
+		keys = append(keys, avatarKeys(avatar)...)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+	files, err := s.profileRepo.ListFiles(userID)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		keys = append(keys, file.Key)
+	}
+
+	if err := s.profileRepo.DeleteAll(userID); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ProfileService) URL(key string) (string, error) {
+	return s.storage.URL(key)
+}
+
+// setPortfolioLink проверяет адрес и название ссылки и заполняет ими link
+func setPortfolioLink(link *models.PortfolioLink, rawURL, title string) error {
+	rawURL = strings.TrimSpace(rawURL)
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(title) > maxPortfolioTitleLength {
+		return ErrPortfolioTitleTooLong
+	}
+	if len(rawURL) > maxPortfolioURLLength {
+		return ErrInvalidPortfolioLink
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || u.User != nil {
+		return ErrInvalidPortfolioLink
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	kind, ok := portfolioLinkHosts[host]
+	if !ok {
+		kind = models.PortfolioLinkWebsite
+	}
+
+	link.Kind = kind
+	link.URL = u.String()
+	link.Title = title
+	return nil
+}
+
+func avatarKeys(avatar *models.UserAvatar) []string {
+	keys := make([]string, len(avatar.Variants))
+	for i, variant := range avatar.Variants {
+		keys[i] = variant.Key
+	}
+	return keys
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/levstremilov/shance-app/internal/mailer"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/signing"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidInvitationToken  = errors.New("invalid invitation link")
+	ErrInvitationNotFound      = errors.New("invitation not found")
+	ErrInvitationNotPending    = errors.New("invitation is no longer pending")
+	ErrInvitationExpired       = errors.New("invitation has expired")
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to another email address")
+	ErrAlreadyProjectMember    = errors.New("user is already a member of this project")
+)
+
+// Назначение подписанной ссылки-приглашения; токены с назначением
+// не принимаются как access токены
+const tokenPurposeInvitation = "project_invitation"
+
+// invitationClaims — содержимое ссылки-приглашения: Subject — ID приглашения,
+// ID — идентификатор ссылки (models.ProjectInvitation.TokenID)
+type invitationClaims struct {
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+type ProjectInvitationServiceInterface interface {
+	Invite(projectID, inviterID uint, email, role string) (*models.ProjectInvitation, error)
+	List(projectID uint) ([]models.ProjectInvitation, error)
+	Revoke(projectID, invitationID uint) error
+	Accept(token string, userID uint) (*models.ProjectMember, error)
+	Decline(token string, userID uint) error
+	ListForUser(userID uint) ([]models.ProjectInvitation, error)
+	LinkUser(user *models.User) error
+}
+
+type ProjectInvitationService struct {
+	invitationRepo *repository.ProjectInvitationRepository
+	projectRepo    *repository.ProjectRepository
+	userRepo       *repository.UserRepository
+	keys           *signing.KeySet
+	issuer         string
+	audience       string
+	mailer         mailer.Mailer
+	publicURL      string
+	ttl            time.Duration
+}
+
+func NewProjectInvitationService(
+	invitationRepo *repository.ProjectInvitationRepository,
+	projectRepo *repository.ProjectRepository,
+	userRepo *repository.UserRepository,
+	keys *signing.KeySet,
+	issuer, audience string,
+	mailer mailer.Mailer,
+	publicURL string,
+	ttl time.Duration,
+) ProjectInvitationServiceInterface {
+	return &ProjectInvitationService{
+		invitationRepo: invitationRepo,
+		projectRepo:    projectRepo,
+		userRepo:       userRepo,
+		keys:           keys,
+		issuer:         issuer,
+		audience:       audience,
+		mailer:         mailer,
+		publicURL:      publicURL,
+		ttl:            ttl,
+	}
+}
+
+// Invite приглашает в проект владельца адреса email, в том числе еще не
+// зарегистрированного, и отправляет ему ссылку-приглашение. Повторное приглашение
+// на тот же адрес обновляет роль и срок действия и отправляет новую ссылку.
+func (s *ProjectInvitationService) Invite(projectID, inviterID uint, email, role string) (*models.ProjectInvitation, error) {
+	// Владелец у проекта один, передача владения — отдельная операция
+	if !policy.IsProjectRole(role) || role == policy.ProjectRoleOwner {
+		return nil, ErrInvalidProjectRole
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, policy.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	member, err := s.invitationRepo.IsMember(projectID, email)
+	if err != nil {
+		return nil, err
+	}
+	if member {
+		return nil, ErrAlreadyProjectMember
+	}
+
+	tokenID, err := generateOpaqueToken(16)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	invitation := &models.ProjectInvitation{
+		ProjectID: projectID,
+		Email:     email,
+		InviterID: inviterID,
+		Role:      role,
+		Status:    models.InvitationStatusPending,
+		TokenID:   tokenID,
+		ExpiresAt: now.Add(s.ttl),
+	}
+
+	var invitee models.User
+	if err := s.userRepo.DB().Where("lower(email) = ?", email).Limit(1).Find(&invitee).Error; err != nil {
+		return nil, err
+	}
+	if invitee.ID != 0 {
+		invitation.InviteeID = &invitee.ID
+	}
+
+	// Просроченное приглашение больше не занимает адрес
+	if err := s.invitationRepo.ExpireStale(projectID, now); err != nil {
+		return nil, err
+	}
+	existing, err := s.invitationRepo.GetPending(projectID, email)
+	switch {
+	case err == nil:
+		invitation.ID = existing.ID
+		invitation.CreatedAt = existing.CreatedAt
+		err = s.invitationRepo.Reissue(invitation)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err = s.invitationRepo.Create(invitation)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.signToken(invitation)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: fmt.Sprintf("Приглашение в проект «%s»", project.Name),
+		Body: fmt.Sprintf("Вас приглашают в проект «%s» с ролью %s.\nЧтобы принять или отклонить приглашение, перейдите по ссылке:\n%s\n\nСсылка действительна %s. Если у вас еще нет аккаунта, зарегистрируйтесь с этим адресом.",
+			project.Name, role, s.link(token), s.ttl),
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.GetByID(invitation.ID)
+}
+
+// List возвращает приглашения в проект, начиная с последних
+func (s *ProjectInvitationService) List(projectID uint) ([]models.ProjectInvitation, error) {
+	if err := s.invitationRepo.ExpireStale(projectID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.ListByProject(projectID)
+}
+
+// Revoke отзывает ожидающее приглашение; ссылка из письма перестает действовать
+func (s *ProjectInvitationService) Revoke(projectID, invitationID uint) error {
+	invitation, err := s.invitationRepo.GetByID(invitationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvitationNotFound
+		}
+		return err
+	}
+	if invitation.ProjectID != projectID {
+		return ErrInvitationNotFound
+	}
+	if err := s.checkPending(invitation); err != nil {
+		return err
+	}
+	return s.resolve(invitation, models.InvitationStatusRevoked)
+}
+
+// Accept принимает приглашение по ссылке и добавляет пользователя в участники проекта.
+// Принять приглашение может только владелец аккаунта с адресом, на который оно отправлено.
+func (s *ProjectInvitationService) Accept(token string, userID uint) (*models.ProjectMember, error) {
+	invitation, err := s.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPending(invitation); err != nil {
+		return nil, err
+	}
+	if invitation.Project.ID == 0 {
+		return nil, policy.ErrProjectNotFound
+	}
+	if err := s.checkInvitee(invitation, userID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.invitationRepo.Accept(invitation, userID, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotPending
+	}
+	return member, err
+}
+
+// Decline отклоняет приглашение по ссылке. Отклонить приглашение, как и принять,
+// может только владелец аккаунта с адресом, на который оно отправлено.
+func (s *ProjectInvitationService) Decline(token string, userID uint) error {
+	invitation, err := s.parseToken(token)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPending(invitation); err != nil {
+		return err
+	}
+	if err := s.checkInvitee(invitation, userID); err != nil {
+		return err
+	}
+	return s.resolve(invitation, models.InvitationStatusDeclined)
+}
+
+// checkInvitee проверяет, что приглашение адресовано пользователю userID. Адрес
+// должен быть подтвержден: иначе ответить на приглашение мог бы любой, кто
+// зарегистрировался с этим адресом и получил ссылку.
+func (s *ProjectInvitationService) checkInvitee(invitation *models.ProjectInvitation, userID uint) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return ErrInvitationEmailMismatch
+	}
+	if user.EmailVerifiedAt == nil {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// ListForUser возвращает действующие приглашения, адресованные пользователю. Адрес
+// должен быть подтвержден: иначе приглашения увидел бы любой, кто зарегистрировался с ним.
+func (s *ProjectInvitationService) ListForUser(userID uint) ([]models.ProjectInvitation, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.EmailVerifiedAt == nil {
+		return nil, ErrEmailNotVerified
+	}
+	return s.invitationRepo.ListPendingForUser(userID, time.Now())
+}
+
+// LinkUser связывает ожидающие приглашения на адрес нового пользователя с его аккаунтом
+func (s *ProjectInvitationService) LinkUser(user *models.User) error {
+	return s.invitationRepo.LinkInvitee(user.Email, user.ID)
+}
+
+// checkPending проверяет, что приглашение ожидает ответа, и помечает просроченное истекшим
+func (s *ProjectInvitationService) checkPending(invitation *models.ProjectInvitation) error {
+	if invitation.Status != models.InvitationStatusPending {
+		return fmt.Errorf("%w: invitation is %s", ErrInvitationNotPending, invitation.Status)
+	}
+	now := time.Now()
+	if !invitation.ExpiresAt.After(now) {
+		if err := s.invitationRepo.ExpireStale(invitation.ProjectID, now); err != nil {
+			return err
+		}
+		return ErrInvitationExpired
+	}
+	return nil
+}
+
+func (s *ProjectInvitationService) resolve(invitation *models.ProjectInvitation, status string) error {
+	err := s.invitationRepo.Resolve(invitation, status, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvitationNotPending
+	}
+	return err
+}
+
+// signToken подписывает ссылку-приглашение ключами JWT. Срок действия в ссылке
+// не хранится: он и состояние приглашения проверяются по базе, поэтому ссылку
+// можно отозвать или продлить.
+func (s *ProjectInvitationService) signToken(invitation *models.ProjectInvitation) (string, error) {
+	return s.keys.Sign(&invitationClaims{
+		Purpose: tokenPurposeInvitation,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:   s.issuer,
+			Subject:  strconv.FormatUint(uint64(invitation.ID), 10),
+			Audience: jwt.ClaimStrings{s.audience},
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+			ID:       invitation.TokenID,
+		},
+	})
+}
+
+// parseToken проверяет подпись ссылки и возвращает приглашение, для которого она выдана.
+// Ссылка, замененная повторной отправкой приглашения, недействительна.
+func (s *ProjectInvitationService) parseToken(tokenString string) (*models.ProjectInvitation, error) {
+	claims := &invitationClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, s.keys.Keyfunc,
+		jwt.WithValidMethods(s.keys.ValidMethods()), jwt.WithIssuer(s.issuer), jwt.WithAudience(s.audience))
+	if err != nil || !token.Valid || claims.Purpose != tokenPurposeInvitation {
+		return nil, ErrInvalidInvitationToken
+	}
+	id, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidInvitationToken
+	}
+
+	invitation, err := s.invitationRepo.GetByID(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitationToken
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(invitation.TokenID), []byte(claims.ID)) != 1 {
+		return nil, ErrInvalidInvitationToken
+	}
+	return invitation, nil
+}
+
+func (s *ProjectInvitationService) link(token string) string {
+	return s.publicURL + "/invitations?token=" + url.QueryEscape(token)
+}
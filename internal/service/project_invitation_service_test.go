@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/dbtest"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+)
+
+func TestCheckInviteeRequiresVerifiedEmail(t *testing.T) {
+	db := dbtest.Open(t, &models.User{})
+	userRepo := repository.NewUserRepository(db)
+	s := &ProjectInvitationService{userRepo: userRepo}
+
+	verifiedAt := time.Now()
+	verified := &models.User{Email: "invitee@example.com", PasswordHash: "hash", EmailVerifiedAt: &verifiedAt}
+	unverified := &models.User{Email: "squatter@example.com", PasswordHash: "hash"}
+	for _, user := range []*models.User{verified, unverified} {
+		if err := userRepo.Create(user); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		email  string
+		userID uint
+		want   error
+	}{
+		{"verified invitee", "Invitee@Example.com", verified.ID, nil},
+		{"another account", "invitee@example.com", unverified.ID, ErrInvitationEmailMismatch},
+		{"unverified invitee", "squatter@example.com", unverified.ID, ErrEmailNotVerified},
+	}
+	for _, c := range cases {
+		invitation := &models.ProjectInvitation{Email: c.email}
+		if err := s.checkInvitee(invitation, c.userID); !errors.Is(err, c.want) {
+			t.Errorf("%s: checkInvitee error = %v, want %v", c.name, err, c.want)
+		}
+	}
+}
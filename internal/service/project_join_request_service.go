@@ -0,0 +1,209 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Ограничения длины сопроводительного сообщения и причины отказа, в символах
+const (
+	maxJoinRequestMessageLength = 2000
+	maxJoinRequestReasonLength  = 1000
+)
+
+var (
+	ErrJoinRequestsClosed       = errors.New("project does not accept join requests")
+	ErrJoinRequestNotFound      = errors.New("join request not found")
+	ErrJoinRequestNotPending    = errors.New("join request has already been reviewed or withdrawn")
+	ErrJoinRequestExists        = errors.New("join request to this project is already pending")
+	ErrJoinRequestMessageLong   = fmt.Errorf("message must be at most %d characters", maxJoinRequestMessageLength)
+	ErrJoinRequestReasonLong    = fmt.Errorf("reason must be at most %d characters", maxJoinRequestReasonLength)
+	ErrInvalidJoinRequestStatus = errors.New("status must be one of: pending, approved, rejected, withdrawn")
+)
+
+// Статусы проекта, в которых не принимаются заявки на вступление и отклики на вакансии
+var closedToNewcomers = []string{models.ProjectStatusCompleted, models.ProjectStatusArchived}
+
+type ProjectJoinRequestServiceInterface interface {
+	Submit(projectID, userID uint, message string) (*models.ProjectJoinRequest, error)
+	List(projectID uint, status string) ([]models.ProjectJoinRequest, error)
+	Approve(projectID, requestID, reviewerID uint, role string) (*models.ProjectMember, error)
+	Reject(projectID, requestID, reviewerID uint, reason string) (*models.ProjectJoinRequest, error)
+	Withdraw(requestID, userID uint) error
+	ListForUser(userID uint) ([]models.ProjectJoinRequest, error)
+}
+
+type ProjectJoinRequestService struct {
+	joinRequestRepo *repository.ProjectJoinRequestRepository
+	projectRepo     *repository.ProjectRepository
+}
+
+func NewProjectJoinRequestService(joinRequestRepo *repository.ProjectJoinRequestRepository, projectRepo *repository.ProjectRepository) ProjectJoinRequestServiceInterface {
+	return &ProjectJoinRequestService{
+		joinRequestRepo: joinRequestRepo,
+		projectRepo:     projectRepo,
+	}
+}
+
+// IsJoinRequestStatus сообщает, является ли status состоянием заявки на вступление
+func IsJoinRequestStatus(status string) bool {
+	switch status {
+	case models.JoinRequestStatusPending, models.JoinRequestStatusApproved,
+		models.JoinRequestStatusRejected, models.JoinRequestStatusWithdrawn:
+		return true
+	}
+	return false
+}
+
+// Submit подает заявку пользователя на вступление в проект. Подать заявку можно в проект,
+// который пользователь может открыть по ссылке, если проект принимает заявки и еще не завершен.
+func (s *ProjectJoinRequestService) Submit(projectID, userID uint, message string) (*models.ProjectJoinRequest, error) {
+	if utf8.RuneCountInString(message) > maxJoinRequestMessageLength {
+		return nil, ErrJoinRequestMessageLong
+	}
+
+	project, err := s.projectRepo.GetVisible(projectID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, policy.ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if project.AcceptsJoinRequests != nil && !*project.AcceptsJoinRequests {
+		return nil, ErrJoinRequestsClosed
+	}
+	if slices.Contains(closedToNewcomers, project.Status) {
+		return nil, fmt.Errorf("%w: project is %s", ErrJoinRequestsClosed, project.Status)
+	}
+
+	member, err := s.projectRepo.IsMember(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member || project.UserID == userID {
+		return nil, ErrAlreadyProjectMember
+	}
+	pending, err := s.joinRequestRepo.HasPending(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrJoinRequestExists
+	}
+
+	request := &models.ProjectJoinRequest{
+		ProjectID: projectID,
+		UserID:    userID,
+		Message:   message,
+		Status:    models.JoinRequestStatusPending,
+	}
+	if err := s.joinRequestRepo.Create(request); err != nil {
+		return nil, err
+	}
+	return s.joinRequestRepo.GetByID(request.ID)
+}
+
+// List возвращает заявки в проект в порядке подачи; пустой status — заявки во всех состояниях
+func (s *ProjectJoinRequestService) List(projectID uint, status string) ([]models.ProjectJoinRequest, error) {
+	if status != "" && !IsJoinRequestStatus(status) {
+		return nil, ErrInvalidJoinRequestStatus
+	}
+	return s.joinRequestRepo.ListByProject(projectID, status)
+}
+
+// Approve одобряет заявку и добавляет ее автора в участники проекта с ролью role,
+// по умолчанию — member
+func (s *ProjectJoinRequestService) Approve(projectID, requestID, reviewerID uint, role string) (*models.ProjectMember, error) {
+	if role == "" {
+		role = policy.ProjectRoleMember
+	}
+	// Владелец у проекта один, передача владения — отдельная операция
+	if !policy.IsProjectRole(role) || role == policy.ProjectRoleOwner {
+		return nil, ErrInvalidProjectRole
+	}
+
+	request, err := s.getPending(projectID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.joinRequestRepo.Approve(request, reviewerID, role, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJoinRequestNotPending
+	}
+	return member, err
+}
+
+// Reject отклоняет заявку; причину отказа видит автор заявки
+func (s *ProjectJoinRequestService) Reject(projectID, requestID, reviewerID uint, reason string) (*models.ProjectJoinRequest, error) {
+	if utf8.RuneCountInString(reason) > maxJoinRequestReasonLength {
+		return nil, ErrJoinRequestReasonLong
+	}
+
+	request, err := s.getPending(projectID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.joinRequestRepo.Reject(request, reviewerID, reason, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJoinRequestNotPending
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// Withdraw отзывает ожидающую заявку пользователя
+func (s *ProjectJoinRequestService) Withdraw(requestID, userID uint) error {
+	request, err := s.joinRequestRepo.GetByID(requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrJoinRequestNotFound
+		}
+		return err
+	}
+	// Чужая заявка не отличается от несуществующей
+	if request.UserID != userID {
+		return ErrJoinRequestNotFound
+	}
+	if request.Status != models.JoinRequestStatusPending {
+		return fmt.Errorf("%w: join request is %s", ErrJoinRequestNotPending, request.Status)
+	}
+	if err := s.joinRequestRepo.Withdraw(request); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrJoinRequestNotPending
+		}
+		return err
+	}
+	return nil
+}
+
+// ListForUser возвращает заявки пользователя во всех состояниях, начиная с последних
+func (s *ProjectJoinRequestService) ListForUser(userID uint) ([]models.ProjectJoinRequest, error) {
+	return s.joinRequestRepo.ListByUser(userID)
+}
+
+// getPending возвращает ожидающую рассмотрения заявку в проект
+func (s *ProjectJoinRequestService) getPending(projectID, requestID uint) (*models.ProjectJoinRequest, error) {
+	request, err := s.joinRequestRepo.GetByID(requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJoinRequestNotFound
+		}
+		return nil, err
+	}
+	if request.ProjectID != projectID {
+		return nil, ErrJoinRequestNotFound
+	}
+	if request.Status != models.JoinRequestStatusPending {
+		return nil, fmt.Errorf("%w: join request is %s", ErrJoinRequestNotPending, request.Status)
+	}
+	return request, nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/levstremilov/shance-app/internal/imaging"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/storage"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPhotoNotFound        = errors.New("photo not found")
+	ErrPhotoTooLarge        = errors.New("photo is too large")
+	ErrUnsupportedPhotoType = errors.New("photo must be a JPEG, PNG or WebP image")
+	ErrTooManyPhotos        = errors.New("project has the maximum number of photos")
+	ErrInvalidPhotoOrder    = errors.New("photo order must list every photo of the project exactly once")
+)
+
+// allowedPhotoTypes — типы фотографий, определяемые по содержимому файла
+var allowedPhotoTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type ProjectPhotoServiceInterface interface {
+	Upload(ctx context.Context, projectID uint, body io.Reader) (*models.ProjectPhoto, error)
+	List(projectID, viewerID uint) ([]models.ProjectPhoto, error)
+	Reorder(projectID uint, ids []uint) ([]models.ProjectPhoto, error)
+	Delete(ctx context.Context, projectID, photoID uint) error
+	URL(key string) (string, error)
+}
+
+type ProjectPhotoService struct {
+	photoRepo   *repository.ProjectPhotoRepository
+	projectRepo *repository.ProjectRepository
+	storage     storage.Storage
+	maxSize     int64
+	maxPhotos   int
+}
+
+func NewProjectPhotoService(photoRepo *repository.ProjectPhotoRepository, projectRepo *repository.ProjectRepository, files storage.Storage, maxSize int64, maxPhotos int) ProjectPhotoServiceInterface {
+	return &ProjectPhotoService{
+		photoRepo:   photoRepo,
+		projectRepo: projectRepo,
+		storage:     files,
+		maxSize:     maxSize,
+		maxPhotos:   maxPhotos,
+	}
+}
+
+// Upload проверяет размер и тип фотографии по ее содержимому, убирает из нее
+// метаданные, создает уменьшенные варианты, сохраняет файлы в хранилище
+// и добавляет фотографию в конец списка фотографий проекта
+func (s *ProjectPhotoService) Upload(ctx context.Context, projectID uint, body io.Reader) (*models.ProjectPhoto, error) {
+	if err := s.checkProject(projectID); err != nil {
+		return nil, err
+	}
+	count, err := s.photoRepo.CountByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= int64(s.maxPhotos) {
+		return nil, ErrTooManyPhotos
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrPhotoTooLarge
+	}
+	if !allowedPhotoTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedPhotoType
+	}
+
+	processed, err := imaging.Process(data, imaging.ProjectPhotoSizes)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrUnsupportedFormat):
+			return nil, ErrUnsupportedPhotoType
+		case errors.Is(err, imaging.ErrImageTooLarge):
+			return nil, ErrPhotoTooLarge
+		}
+		return nil, err
+	}
+
+	name, err := generateOpaqueToken(16)
+	if err != nil {
+		return nil, err
+	}
+	photo := &models.ProjectPhoto{
+		ProjectID: projectID,
+		Width:     processed.Width,
+		Height:    processed.Height,
+		Blurhash:  processed.Blurhash,
+	}
+
+	var stored []string
+	for _, variant := range processed.Variants {
+		key := fmt.Sprintf("projects/%d/%s", projectID, name)
+		if variant.Name != imaging.OriginalVariant {
+			key += "_" + variant.Name
+		}
+		key += imaging.Extension(variant.Format)
+
+		if err := s.storage.Put(ctx, key, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType); err != nil {
+			removeOrphanedFiles(s.storage, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+
+		if variant.Name == imaging.OriginalVariant {
+			photo.Key = key
+			photo.ContentType = variant.ContentType
+			photo.Size = int64(len(variant.Data))
+			continue
+		}
+		photo.Variants = append(photo.Variants, models.ProjectPhotoVariant{
+			Name:        variant.Name,
+			Format:      variant.Format,
+			Key:         key,
+			ContentType: variant.ContentType,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			Size:        int64(len(variant.Data)),
+		})
+	}
+
+	if err := s.photoRepo.Create(photo); err != nil {
+		removeOrphanedFiles(s.storage, stored)
+		return nil, err
+	}
+	return photo, nil
+}
+
+// List возвращает фотографии проекта, если пользователь viewerID может открыть проект
+func (s *ProjectPhotoService) List(projectID, viewerID uint) ([]models.ProjectPhoto, error) {
+	visible, err := s.projectRepo.IsVisible(projectID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, policy.ErrProjectNotFound
+	}
+	return s.photoRepo.ListByProject(projectID)
+}
+
+// Reorder задает порядок фотографий проекта; ids должен содержать каждую фотографию ровно один раз
+func (s *ProjectPhotoService) Reorder(projectID uint, ids []uint) ([]models.ProjectPhoto, error) {
+	photos, err := s.photoRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) != len(photos) {
+		return nil, ErrInvalidPhotoOrder
+	}
+	remaining := make(map[uint]bool, len(photos))
+	for _, photo := range photos {
+		remaining[photo.ID] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return nil, ErrInvalidPhotoOrder
+		}
+		delete(remaining, id)
+	}
+
+	if err := s.photoRepo.Reorder(projectID, ids); err != nil {
+		return nil, err
+	}
+	return s.photoRepo.ListByProject(projectID)
+}
+
+// Delete удаляет фотографию проекта и файлы всех ее вариантов
+func (s *ProjectPhotoService) Delete(ctx context.Context, projectID, photoID uint) error {
+	photo, err := s.photoRepo.GetByProjectAndID(projectID, photoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPhotoNotFound
+		}
+		return err
+	}
+	if err := s.photoRepo.Delete(photo); err != nil {
+		return err
+	}
+	if err := s.storage.Delete(ctx, photo.Key); err != nil {
+		return err
+	}
+	for _, variant := range photo.Variants {
+		if err := s.storage.Delete(ctx, variant.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ProjectPhotoService) URL(key string) (string, error) {
+	return s.storage.URL(key)
+}
+
+func (s *ProjectPhotoService) checkProject(projectID uint) error {
+	var count int64
+	if err := s.projectRepo.GetDB().Model(&models.Project{}).Where("id = ?", projectID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return policy.ErrProjectNotFound
+	}
+	return nil
+}
+
+// removeOrphanedFiles удаляет файлы, запись о которых не удалось сохранить
+// или которые больше не нужны; ошибки только записываются в лог
+func removeOrphanedFiles(files storage.Storage, keys []string) {
+	for _, key := range keys {
+		if err := files.Delete(context.Background(), key); err != nil {
+			log.Printf("Failed to remove orphaned file %s: %v", key, err)
+		}
+	}
+}
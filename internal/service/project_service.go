@@ -1,37 +1,61 @@
 package service
 
 import (
-	"fmt"
+	"errors"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
+	"github.com/levstremilov/shance-app/internal/policy"
 	"github.com/levstremilov/shance-app/internal/repository"
 	"gorm.io/gorm"
 )
 
 type ProjectServiceInterface interface {
 	GetAll() ([]models.Project, error)
+	ListPage(filter repository.ProjectFilter, params pagination.Params) ([]models.Project, pagination.Page, error)
 	GetByID(id string) (*models.Project, error)
+	GetVisible(id, viewerID uint) (*models.Project, error)
 	Create(project *models.Project) error
 	Update(project *models.Project) error
 	Delete(id string) error
-	Search(query string) ([]models.Project, error)
-	IsProjectOwner(projectID, userID uint) (bool, error)
-	InviteMember(projectID uint, email, role string) (*models.ProjectMember, error)
+	Search(query string, viewerID uint, params pagination.Params) ([]repository.ProjectSearchResult, pagination.Page, error)
+	Transition(projectID, userID uint, input ProjectTransitionInput) (*models.Project, error)
+	ListTransitions(projectID, viewerID uint) ([]models.ProjectTransition, error)
+	ProjectRole(projectID, userID uint) (string, error)
 	GetProjectMembers(projectID uint) ([]models.ProjectMember, error)
 }
 
+var (
+	ErrEmailNotVerified         = errors.New("email is not verified")
+	ErrInvalidProjectRole       = errors.New("invalid project role")
+	ErrInvalidProjectVisibility = errors.New("visibility must be one of: public, unlisted, private")
+)
+
+// IsProjectVisibility сообщает, является ли visibility уровнем доступа к проекту
+func IsProjectVisibility(visibility string) bool {
+	switch visibility {
+	case models.ProjectVisibilityPublic, models.ProjectVisibilityUnlisted, models.ProjectVisibilityPrivate:
+		return true
+	}
+	return false
+}
+
 type ProjectService struct {
-	projectRepo *repository.ProjectRepository
-	tagRepo     *repository.TagRepository
-	userRepo    *repository.UserRepository
+	projectRepo          *repository.ProjectRepository
+	tagRepo              *repository.TagRepository
+	userRepo             *repository.UserRepository
+	requireVerifiedEmail bool
 }
 
-func NewProjectService(projectRepo *repository.ProjectRepository, tagRepo *repository.TagRepository, userRepo *repository.UserRepository) ProjectServiceInterface {
+func NewProjectService(projectRepo *repository.ProjectRepository, tagRepo *repository.TagRepository, userRepo *repository.UserRepository, requireVerifiedEmail bool) ProjectServiceInterface {
 	return &ProjectService{
-		projectRepo: projectRepo,
-		tagRepo:     tagRepo,
-		userRepo:    userRepo,
+		projectRepo:          projectRepo,
+		tagRepo:              tagRepo,
+		userRepo:             userRepo,
+		requireVerifiedEmail: requireVerifiedEmail,
 	}
 }
 
@@ -39,6 +63,10 @@ func (s *ProjectService) GetAll() ([]models.Project, error) {
 	return s.projectRepo.List()
 }
 
+func (s *ProjectService) ListPage(filter repository.ProjectFilter, params pagination.Params) ([]models.Project, pagination.Page, error) {
+	return s.projectRepo.ListPage(filter, params)
+}
+
 func (s *ProjectService) GetByID(id string) (*models.Project, error) {
 	idUint, err := strconv.ParseUint(id, 10, 32)
 	if err != nil {
@@ -47,11 +75,53 @@ func (s *ProjectService) GetByID(id string) (*models.Project, error) {
 	return s.projectRepo.GetByID(uint(idUint))
 }
 
+// GetVisible возвращает проект, если пользователь viewerID (0 — анонимный посетитель)
+// может его открыть; закрытый проект для остальных не существует
+func (s *ProjectService) GetVisible(id, viewerID uint) (*models.Project, error) {
+	project, err := s.projectRepo.GetVisible(id, viewerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, policy.ErrProjectNotFound
+	}
+	return project, err
+}
+
+// Create создает проект в одном из начальных статусов, по умолчанию — активным.
+// Черновик создается, только если клиент явно запросил статус draft.
 func (s *ProjectService) Create(project *models.Project) error {
-	return s.projectRepo.Create(project)
+	if project.Status == "" {
+		project.Status = models.ProjectStatusActive
+	}
+	if project.Visibility == "" {
+		project.Visibility = models.ProjectVisibilityPublic
+	}
+	if !IsProjectVisibility(project.Visibility) {
+		return ErrInvalidProjectVisibility
+	}
+	if !slices.Contains(initialProjectStatuses, project.Status) {
+		return ErrInvalidProjectStatus
+	}
+	var err error
+	project.StartDate, project.EndDate, err = checkProjectDates(project.Status, project.StartDate, project.EndDate, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if s.requireVerifiedEmail {
+		owner, err := s.userRepo.GetByID(project.UserID)
+		if err != nil {
+			return err
+		}
+		if owner.EmailVerifiedAt == nil {
+			return ErrEmailNotVerified
+		}
+	}
+	return s.projectRepo.CreateWithOwner(project, policy.ProjectRoleOwner)
 }
 
 func (s *ProjectService) Update(project *models.Project) error {
+	if project.Visibility != "" && !IsProjectVisibility(project.Visibility) {
+		return ErrInvalidProjectVisibility
+	}
 	return s.projectRepo.Update(project)
 }
 
@@ -63,46 +133,35 @@ func (s *ProjectService) Delete(id string) error {
 	return s.projectRepo.Delete(uint(idUint))
 }
 
-func (s *ProjectService) Search(query string) ([]models.Project, error) {
-	return s.projectRepo.Search(query)
+func (s *ProjectService) Search(query string, viewerID uint, params pagination.Params) ([]repository.ProjectSearchResult, pagination.Page, error) {
+	return s.projectRepo.Search(query, repository.ProjectFilter{ViewerID: viewerID}, params)
 }
 
-func (s *ProjectService) IsProjectOwner(projectID, userID uint) (bool, error) {
-	var member models.ProjectMember
-	err := s.projectRepo.GetDB().Where("project_id = ? AND user_id = ? AND role = ?", projectID, userID, "owner").First(&member).Error
+// ProjectRole возвращает роль пользователя в проекте. Автор проекта, созданного
+// до появления записи владельца в участниках, считается владельцем.
+func (s *ProjectService) ProjectRole(projectID, userID uint) (string, error) {
+	var project models.Project
+	err := s.projectRepo.GetDB().Select("id", "user_id").First(&project, projectID).Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return false, nil
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", policy.ErrProjectNotFound
 		}
-		return false, err
+		return "", err
 	}
-	return true, nil
-}
 
-func (s *ProjectService) InviteMember(projectID uint, email, role string) (*models.ProjectMember, error) {
-	user, err := s.userRepo.GetByEmail(email)
-	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
-	}
-
-	var existingMember models.ProjectMember
-	err = s.projectRepo.GetDB().Where("project_id = ? AND user_id = ?", projectID, user.ID).First(&existingMember).Error
+	var member models.ProjectMember
+	err = s.projectRepo.GetDB().Where("project_id = ? AND user_id = ?", projectID, userID).First(&member).Error
 	if err == nil {
-		return nil, fmt.Errorf("user is already a member of this project")
+		return member.Role, nil
 	}
-
-	err = s.projectRepo.AddMember(projectID, user.ID, role)
-	if err != nil {
-		return nil, err
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
 	}
 
-	member := &models.ProjectMember{}
-	err = s.projectRepo.GetDB().Where("project_id = ? AND user_id = ?", projectID, user.ID).Preload("User").First(member).Error
-	if err != nil {
-		return nil, err
+	if project.UserID == userID {
+		return policy.ProjectRoleOwner, nil
 	}
-
-	return member, nil
+	return "", nil
 }
 
 func (s *ProjectService) GetProjectMembers(projectID uint) ([]models.ProjectMember, error) {
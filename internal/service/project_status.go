@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/policy"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidProjectStatus  = errors.New("invalid project status")
+	ErrTransitionNotAllowed  = errors.New("project status transition is not allowed")
+	ErrInvalidProjectDates   = errors.New("invalid project dates")
+	ErrProjectStatusChanged  = errors.New("project status has changed, reload the project and try again")
+	ErrTransitionCommentLong = errors.New("transition comment is too long")
+)
+
+// Максимальная длина комментария к переходу, в символах
+const maxTransitionCommentLength = 500
+
+// projectTransitions — допустимые переходы между статусами проекта.
+// Архивный проект больше не меняет статус.
+var projectTransitions = map[string][]string{
+	models.ProjectStatusDraft:      {models.ProjectStatusRecruiting, models.ProjectStatusActive, models.ProjectStatusArchived},
+	models.ProjectStatusRecruiting: {models.ProjectStatusDraft, models.ProjectStatusActive, models.ProjectStatusPaused, models.ProjectStatusArchived},
+	models.ProjectStatusActive:     {models.ProjectStatusRecruiting, models.ProjectStatusPaused, models.ProjectStatusCompleted, models.ProjectStatusArchived},
+	models.ProjectStatusPaused:     {models.ProjectStatusRecruiting, models.ProjectStatusActive, models.ProjectStatusCompleted, models.ProjectStatusArchived},
+	models.ProjectStatusCompleted:  {models.ProjectStatusActive, models.ProjectStatusArchived},
+	models.ProjectStatusArchived:   {},
+}
+
+// initialProjectStatuses — статусы, с которыми можно создать проект
+var initialProjectStatuses = []string{models.ProjectStatusDraft, models.ProjectStatusRecruiting, models.ProjectStatusActive}
+
+// ProjectTransitionInput — запрос на смену статуса проекта. Даты, равные nil,
+// остаются прежними.
+type ProjectTransitionInput struct {
+	Status    string
+	StartDate *time.Time
+	EndDate   *time.Time
+	Comment   string
+}
+
+// IsProjectStatus сообщает, является ли status статусом жизненного цикла проекта
+func IsProjectStatus(status string) bool {
+	_, ok := projectTransitions[status]
+	return ok
+}
+
+// CanTransition сообщает, можно ли перевести проект из статуса from в статус to
+func CanTransition(from, to string) bool {
+	return slices.Contains(projectTransitions[from], to)
+}
+
+// Transition переводит проект в новый статус, проверяя допустимость перехода и даты
+// проекта, и записывает переход в историю
+func (s *ProjectService) Transition(projectID, userID uint, input ProjectTransitionInput) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, policy.ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	if !IsProjectStatus(input.Status) {
+		return nil, ErrInvalidProjectStatus
+	}
+	if !CanTransition(project.Status, input.Status) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrTransitionNotAllowed, project.Status, input.Status)
+	}
+	if utf8.RuneCountInString(input.Comment) > maxTransitionCommentLength {
+		return nil, ErrTransitionCommentLong
+	}
+
+	startDate, endDate := project.StartDate, project.EndDate
+	// Возобновленный завершенный проект больше не имеет даты окончания
+	if project.Status == models.ProjectStatusCompleted {
+		endDate = time.Time{}
+	}
+	if input.StartDate != nil {
+		startDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		endDate = *input.EndDate
+	}
+	startDate, endDate, err = checkProjectDates(input.Status, startDate, endDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	transition := &models.ProjectTransition{
+		ProjectID:  projectID,
+		FromStatus: project.Status,
+		ToStatus:   input.Status,
+		UserID:     &userID,
+		Comment:    input.Comment,
+	}
+	if err := s.projectRepo.Transition(transition, startDate, endDate); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectStatusChanged
+		}
+		return nil, err
+	}
+	return s.projectRepo.GetByID(projectID)
+}
+
+// ListTransitions возвращает историю смены статуса проекта, если пользователь
+// viewerID может открыть проект
+func (s *ProjectService) ListTransitions(projectID, viewerID uint) ([]models.ProjectTransition, error) {
+	visible, err := s.projectRepo.IsVisible(projectID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, policy.ErrProjectNotFound
+	}
+	return s.projectRepo.ListTransitions(projectID)
+}
+
+// checkProjectDates проверяет, что даты начала и окончания подходят для статуса,
+// и подставляет текущее время в даты, которые статус требует, но которые не заданы:
+//   - черновик и набор участников: даты плановые и могут быть любыми;
+//   - активный и приостановленный проект уже начался и еще не закончился,
+//     при переходе в активный статус дата начала по умолчанию — сейчас;
+//   - завершенный проект начался и закончился, дата окончания по умолчанию — сейчас;
+//   - архивный проект может иметь любые даты.
+//
+// Во всех статусах проект не может закончиться раньше, чем начался.
+// Нулевая дата означает, что дата не задана.
+func checkProjectDates(status string, startDate, endDate, now time.Time) (time.Time, time.Time, error) {
+	switch status {
+	case models.ProjectStatusActive, models.ProjectStatusPaused:
+		if startDate.IsZero() && status == models.ProjectStatusActive {
+			startDate = now
+		}
+		if startDate.After(now) {
+			return startDate, endDate, fmt.Errorf("%w: a %s project cannot start in the future", ErrInvalidProjectDates, status)
+		}
+		if !endDate.IsZero() && endDate.Before(now) {
+			return startDate, endDate, fmt.Errorf("%w: a %s project cannot have ended", ErrInvalidProjectDates, status)
+		}
+	case models.ProjectStatusCompleted:
+		if startDate.IsZero() {
+			return startDate, endDate, fmt.Errorf("%w: a completed project requires a start date", ErrInvalidProjectDates)
+		}
+		if endDate.IsZero() {
+			endDate = now
+		}
+		if endDate.After(now) {
+			return startDate, endDate, fmt.Errorf("%w: a completed project cannot end in the future", ErrInvalidProjectDates)
+		}
+	}
+
+	if !startDate.IsZero() && !endDate.IsZero() && endDate.Before(startDate) {
+		return startDate, endDate, fmt.Errorf("%w: end date is before start date", ErrInvalidProjectDates)
+	}
+	return startDate, endDate, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+)
+
+func TestCanTransition(t *testing.T) {
+	allowed := [][2]string{
+		{models.ProjectStatusDraft, models.ProjectStatusRecruiting},
+		{models.ProjectStatusDraft, models.ProjectStatusActive},
+		{models.ProjectStatusRecruiting, models.ProjectStatusDraft},
+		{models.ProjectStatusActive, models.ProjectStatusPaused},
+		{models.ProjectStatusPaused, models.ProjectStatusActive},
+		{models.ProjectStatusActive, models.ProjectStatusCompleted},
+		{models.ProjectStatusCompleted, models.ProjectStatusActive},
+		{models.ProjectStatusCompleted, models.ProjectStatusArchived},
+	}
+	for _, c := range allowed {
+		if !CanTransition(c[0], c[1]) {
+			t.Errorf("CanTransition(%q, %q) = false, want true", c[0], c[1])
+		}
+	}
+
+	denied := [][2]string{
+		{models.ProjectStatusDraft, models.ProjectStatusPaused},
+		{models.ProjectStatusDraft, models.ProjectStatusCompleted},
+		{models.ProjectStatusRecruiting, models.ProjectStatusCompleted},
+		{models.ProjectStatusCompleted, models.ProjectStatusDraft},
+		{models.ProjectStatusActive, models.ProjectStatusActive},
+		{models.ProjectStatusActive, "unknown"},
+		{"unknown", models.ProjectStatusActive},
+	}
+	for _, c := range denied {
+		if CanTransition(c[0], c[1]) {
+			t.Errorf("CanTransition(%q, %q) = true, want false", c[0], c[1])
+		}
+	}
+
+	// Архивный проект больше не меняет статус, а в архив можно перевести любой другой
+	for status := range projectTransitions {
+		if CanTransition(models.ProjectStatusArchived, status) {
+			t.Errorf("archived project can move to %q", status)
+		}
+		if status != models.ProjectStatusArchived && !CanTransition(status, models.ProjectStatusArchived) {
+			t.Errorf("%q project cannot be archived", status)
+		}
+	}
+}
+
+func TestCheckProjectDates(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.AddDate(0, -1, 0), now.AddDate(0, 1, 0)
+
+	start, _, err := checkProjectDates(models.ProjectStatusActive, time.Time{}, time.Time{}, now)
+	if err != nil || !start.Equal(now) {
+		t.Fatalf("active project without a start date: start = %v, err = %v; want now", start, err)
+	}
+	if _, end, err := checkProjectDates(models.ProjectStatusCompleted, past, time.Time{}, now); err != nil || !end.Equal(now) {
+		t.Fatalf("completed project without an end date: end = %v, err = %v; want now", end, err)
+	}
+	if _, _, err := checkProjectDates(models.ProjectStatusDraft, future, future.AddDate(0, 1, 0), now); err != nil {
+		t.Fatalf("draft with planned dates: %v", err)
+	}
+
+	invalid := []struct {
+		status     string
+		start, end time.Time
+	}{
+		{models.ProjectStatusActive, future, time.Time{}},
+		{models.ProjectStatusActive, past, past.AddDate(0, 0, 1)},
+		{models.ProjectStatusPaused, future, time.Time{}},
+		{models.ProjectStatusCompleted, time.Time{}, past},
+		{models.ProjectStatusCompleted, past, future},
+		{models.ProjectStatusDraft, future, past},
+	}
+	for _, c := range invalid {
+		if _, _, err := checkProjectDates(c.status, c.start, c.end, now); !errors.Is(err, ErrInvalidProjectDates) {
+			t.Errorf("checkProjectDates(%q, %v, %v) error = %v, want ErrInvalidProjectDates", c.status, c.start, c.end, err)
+		}
+	}
+}
@@ -1,27 +1,397 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
+	"github.com/levstremilov/shance-app/internal/policy"
 	"github.com/levstremilov/shance-app/internal/repository"
 	"gorm.io/gorm"
 )
 
+// Ограничения условий работы по вакансии
+const (
+	maxVacancyHoursPerWeek   = 168
+	maxVacancyLocationLength = 200
+)
+
+var (
+	ErrInvalidVacancyStatus   = errors.New("status must be one of: open, closed, filled")
+	ErrVacancyExpiryPast      = errors.New("expires_at must be in the future")
+	ErrVacancyExpired         = errors.New("vacancy has expired, set a later expires_at to reopen it")
+	ErrVacancyHasApplications = errors.New("vacancy has applications, close it instead of deleting")
+	ErrInvalidSeniority       = errors.New("seniority must be one of: junior, middle, senior, lead")
+	ErrInvalidEmploymentType  = errors.New("employment_type must be one of: volunteer, paid, equity, internship")
+	ErrInvalidRemotePolicy    = errors.New("remote_policy must be one of: remote, hybrid, onsite")
+	ErrVacancyLocationInvalid = fmt.Errorf("location is required for hybrid and onsite vacancies and must be at most %d characters", maxVacancyLocationLength)
+	ErrInvalidHoursPerWeek    = fmt.Errorf("hours_per_week must be between 1 and %d", maxVacancyHoursPerWeek)
+	ErrInvalidCompensation    = errors.New("compensation must not be negative and compensation_min must not exceed compensation_max")
+	ErrInvalidCurrency        = errors.New("currency must be a three-letter ISO 4217 code and is required with compensation")
+	ErrInvalidOpenings        = errors.New("openings must be at least 1")
+)
+
 type ProjectVacancyService struct {
-	repo *repository.ProjectVacancyRepository
+	repo           *repository.ProjectVacancyRepository
+	projectRepo    *repository.ProjectRepository
+	jobRepo        *repository.JobRepository
+	expiryInterval time.Duration
+}
+
+func NewProjectVacancyService(repo *repository.ProjectVacancyRepository, projectRepo *repository.ProjectRepository, jobRepo *repository.JobRepository, expiryInterval time.Duration) *ProjectVacancyService {
+	return &ProjectVacancyService{
+		repo:           repo,
+		projectRepo:    projectRepo,
+		jobRepo:        jobRepo,
+		expiryInterval: expiryInterval,
+	}
+}
+
+// VacancyUpdate — изменения вакансии; nil-поля не меняются
+type VacancyUpdate struct {
+	Title       *string
+	Description *string
+	Role        *string
+	Status      *string
+	// Technologies заменяет технологии вакансии
+	Technologies *[]models.Technology
+	ExpiresAt    *time.Time
+	// ClearExpiry снимает срок вакансии, если ExpiresAt не задан
+	ClearExpiry     bool
+	Seniority       *string
+	HoursPerWeek    *int
+	EmploymentType  *string
+	RemotePolicy    *string
+	Location        *string
+	CompensationMin *int64
+	CompensationMax *int64
+	Currency        *string
+	Openings        *int
+}
+
+// IsVacancyStatus сообщает, является ли status состоянием вакансии
+func IsVacancyStatus(status string) bool {
+	switch status {
+	case models.VacancyStatusOpen, models.VacancyStatusClosed, models.VacancyStatusFilled:
+		return true
+	}
+	return false
+}
+
+// IsVacancySeniority сообщает, является ли seniority уровнем квалификации вакансии
+func IsVacancySeniority(seniority string) bool {
+	switch seniority {
+	case models.VacancySeniorityJunior, models.VacancySeniorityMiddle,
+		models.VacancySenioritySenior, models.VacancySeniorityLead:
+		return true
+	}
+	return false
 }
 
-func NewProjectVacancyService(repo *repository.ProjectVacancyRepository) *ProjectVacancyService {
-	return &ProjectVacancyService{repo: repo}
+// IsEmploymentType сообщает, является ли employmentType видом занятости по вакансии
+func IsEmploymentType(employmentType string) bool {
+	switch employmentType {
+	case models.EmploymentTypeVolunteer, models.EmploymentTypePaid,
+		models.EmploymentTypeEquity, models.EmploymentTypeInternship:
+		return true
+	}
+	return false
 }
 
+// IsRemotePolicy сообщает, является ли policy форматом работы по вакансии
+func IsRemotePolicy(policy string) bool {
+	switch policy {
+	case models.RemotePolicyRemote, models.RemotePolicyHybrid, models.RemotePolicyOnsite:
+		return true
+	}
+	return false
+}
+
+// IsCurrency сообщает, похож ли currency на трехбуквенный код валюты ISO 4217
+func IsCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// vacancyExpired сообщает, истек ли к моменту now срок вакансии
+func vacancyExpired(vacancy *models.ProjectVacancy, now time.Time) bool {
+	return vacancy.ExpiresAt != nil && !vacancy.ExpiresAt.After(now)
+}
+
+// Create создает открытую вакансию; принятый на нее кандидат по умолчанию становится участником с ролью member
 func (s *ProjectVacancyService) Create(vacancy *models.ProjectVacancy) error {
+	if vacancy.Role == "" {
+		vacancy.Role = policy.ProjectRoleMember
+	}
+	if err := checkVacancyRole(vacancy.Role); err != nil {
+		return err
+	}
+	if err := checkVacancyTerms(vacancy); err != nil {
+		return err
+	}
+	if vacancyExpired(vacancy, time.Now()) {
+		return ErrVacancyExpiryPast
+	}
+	vacancy.Status = models.VacancyStatusOpen
 	return s.repo.Create(vacancy)
 }
 
+// Get возвращает вакансию в любом состоянии, если пользователь viewerID может открыть ее проект по ссылке
+func (s *ProjectVacancyService) Get(id, viewerID uint) (*models.ProjectVacancy, error) {
+	vacancy, err := s.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	visible, err := s.projectRepo.IsVisible(vacancy.ProjectID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrVacancyNotFound
+	}
+	return vacancy, nil
+}
+
+// ProjectID возвращает ID проекта, к которому относится вакансия
+func (s *ProjectVacancyService) ProjectID(id uint) (uint, error) {
+	vacancy, err := s.getByID(id)
+	if err != nil {
+		return 0, err
+	}
+	return vacancy.ProjectID, nil
+}
+
+// Update меняет вакансию. Открыть снова можно закрытую и заполненную вакансию; если ее срок
+// истек, вместе с открытием нужно назначить новый срок или снять его.
+func (s *ProjectVacancyService) Update(id uint, update VacancyUpdate) (*models.ProjectVacancy, error) {
+	vacancy, err := s.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if update.Title != nil {
+		vacancy.Title = *update.Title
+	}
+	if update.Description != nil {
+		vacancy.Description = *update.Description
+	}
+	if update.Role != nil {
+		if err := checkVacancyRole(*update.Role); err != nil {
+			return nil, err
+		}
+		vacancy.Role = *update.Role
+	}
+	if update.ExpiresAt != nil {
+		if !update.ExpiresAt.After(now) {
+			return nil, ErrVacancyExpiryPast
+		}
+		vacancy.ExpiresAt = update.ExpiresAt
+	} else if update.ClearExpiry {
+		vacancy.ExpiresAt = nil
+	}
+	if update.Status != nil && *update.Status != vacancy.Status {
+		if !IsVacancyStatus(*update.Status) {
+			return nil, ErrInvalidVacancyStatus
+		}
+		vacancy.Status = *update.Status
+	}
+	if update.Status != nil && vacancy.Status == models.VacancyStatusOpen && vacancyExpired(vacancy, now) {
+		return nil, ErrVacancyExpired
+	}
+	if update.Technologies != nil {
+		vacancy.Technologies = *update.Technologies
+	}
+	applyVacancyTerms(vacancy, update)
+	if err := checkVacancyTerms(vacancy); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(vacancy, update.Technologies != nil); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Delete удаляет вакансию без откликов. Вакансию с откликами можно только закрыть,
+// чтобы у кандидатов и команды осталась история отбора.
+func (s *ProjectVacancyService) Delete(id uint) error {
+	vacancy, err := s.getByID(id)
+	if err != nil {
+		return err
+	}
+	hasApplications, err := s.repo.HasApplications(id)
+	if err != nil {
+		return err
+	}
+	if hasApplications {
+		return ErrVacancyHasApplications
+	}
+	return s.repo.Delete(vacancy)
+}
+
+// ScheduleExpiry ставит в очередь задачу закрытия вакансий с истекшим сроком, если ее еще нет
+func (s *ProjectVacancyService) ScheduleExpiry() error {
+	_, err := s.jobRepo.FindActive(0, models.JobTypeVacancyExpiry)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return s.jobRepo.Create(&models.Job{
+		Type:   models.JobTypeVacancyExpiry,
+		Status: models.JobStatusPending,
+		RunAt:  time.Now(),
+	})
+}
+
+// Run закрывает вакансии с истекшим сроком и планирует следующий запуск через expiryInterval
+func (s *ProjectVacancyService) Run(ctx context.Context, job *models.Job) (string, error) {
+	now := time.Now()
+	closed, err := s.repo.CloseExpired(now)
+	if err != nil {
+		return "", err
+	}
+
+	// Следующий запуск мог уже запланировать другой экземпляр приложения
+	pending, err := s.jobRepo.ListByStatus(0, models.JobTypeVacancyExpiry, models.JobStatusPending)
+	if err != nil {
+		return "", err
+	}
+	if len(pending) == 0 {
+		next := &models.Job{
+			Type:   models.JobTypeVacancyExpiry,
+			Status: models.JobStatusPending,
+			RunAt:  now.Add(s.expiryInterval),
+		}
+		if err := s.jobRepo.Create(next); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("closed %d vacancies", closed), nil
+}
+
 func (s *ProjectVacancyService) GetAll(project_id uint) ([]models.ProjectVacancy, error) {
 	return s.repo.FindByProjectID(project_id)
 }
 
+func (s *ProjectVacancyService) ListPage(filter repository.VacancyFilter, params pagination.Params) ([]models.ProjectVacancy, pagination.Page, error) {
+	return s.repo.ListPage(filter, params)
+}
+
+func (s *ProjectVacancyService) Search(query string, viewerID uint, params pagination.Params) ([]repository.VacancySearchResult, pagination.Page, error) {
+	return s.repo.Search(query, repository.VacancyFilter{ViewerID: viewerID}, params)
+}
+
 func (s *ProjectVacancyService) DB() *gorm.DB {
 	return s.repo.DB()
 }
+
+func (s *ProjectVacancyService) getByID(id uint) (*models.ProjectVacancy, error) {
+	vacancy, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVacancyNotFound
+		}
+		return nil, err
+	}
+	return vacancy, nil
+}
+
+// checkVacancyRole проверяет роль, которую получает принятый на вакансию кандидат
+func checkVacancyRole(role string) error {
+	// Владелец у проекта один, передача владения — отдельная операция
+	if !policy.IsProjectRole(role) || role == policy.ProjectRoleOwner {
+		return ErrInvalidProjectRole
+	}
+	return nil
+}
+
+// applyVacancyTerms переносит в вакансию переданные условия работы
+func applyVacancyTerms(vacancy *models.ProjectVacancy, update VacancyUpdate) {
+	if update.Seniority != nil {
+		vacancy.Seniority = *update.Seniority
+	}
+	if update.HoursPerWeek != nil {
+		vacancy.HoursPerWeek = *update.HoursPerWeek
+	}
+	if update.EmploymentType != nil {
+		vacancy.EmploymentType = *update.EmploymentType
+	}
+	if update.RemotePolicy != nil {
+		vacancy.RemotePolicy = *update.RemotePolicy
+	}
+	if update.Location != nil {
+		vacancy.Location = *update.Location
+	}
+	if update.CompensationMin != nil {
+		vacancy.CompensationMin = *update.CompensationMin
+	}
+	if update.CompensationMax != nil {
+		vacancy.CompensationMax = *update.CompensationMax
+	}
+	if update.Currency != nil {
+		vacancy.Currency = *update.Currency
+	}
+	if update.Openings != nil {
+		vacancy.Openings = *update.Openings
+	}
+}
+
+// checkVacancyTerms проверяет условия работы по вакансии и приводит их к единому виду:
+// код валюты — к верхнему регистру, незаданное число мест — к одному
+func checkVacancyTerms(vacancy *models.ProjectVacancy) error {
+	if vacancy.Seniority != "" && !IsVacancySeniority(vacancy.Seniority) {
+		return ErrInvalidSeniority
+	}
+	if vacancy.EmploymentType != "" && !IsEmploymentType(vacancy.EmploymentType) {
+		return ErrInvalidEmploymentType
+	}
+	if vacancy.HoursPerWeek < 0 || vacancy.HoursPerWeek > maxVacancyHoursPerWeek {
+		return ErrInvalidHoursPerWeek
+	}
+
+	vacancy.Location = strings.TrimSpace(vacancy.Location)
+	if vacancy.RemotePolicy != "" && !IsRemotePolicy(vacancy.RemotePolicy) {
+		return ErrInvalidRemotePolicy
+	}
+	if utf8.RuneCountInString(vacancy.Location) > maxVacancyLocationLength {
+		return ErrVacancyLocationInvalid
+	}
+	if vacancy.Location == "" && (vacancy.RemotePolicy == models.RemotePolicyHybrid || vacancy.RemotePolicy == models.RemotePolicyOnsite) {
+		return ErrVacancyLocationInvalid
+	}
+
+	if vacancy.CompensationMin < 0 || vacancy.CompensationMax < 0 ||
+		(vacancy.CompensationMax > 0 && vacancy.CompensationMin > vacancy.CompensationMax) {
+		return ErrInvalidCompensation
+	}
+	vacancy.Currency = strings.ToUpper(strings.TrimSpace(vacancy.Currency))
+	if vacancy.Currency != "" && !IsCurrency(vacancy.Currency) {
+		return ErrInvalidCurrency
+	}
+	if vacancy.Currency == "" && (vacancy.CompensationMin > 0 || vacancy.CompensationMax > 0) {
+		return ErrInvalidCurrency
+	}
+
+	if vacancy.Openings == 0 {
+		vacancy.Openings = 1
+	}
+	if vacancy.Openings < 1 {
+		return ErrInvalidOpenings
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
+	"github.com/levstremilov/shance-app/internal/repository"
+)
+
+// Типы записей в общем поиске
+const (
+	SearchTypeProject = "project"
+	SearchTypeVacancy = "vacancy"
+	SearchTypeUser    = "user"
+	SearchTypeTag     = "tag"
+)
+
+var searchTypes = []string{SearchTypeProject, SearchTypeVacancy, SearchTypeUser, SearchTypeTag}
+
+// Общий поиск сливает лучшие записи каждого типа, поэтому глубина выдачи ограничена
+const maxSearchWindow = 1000
+
+var (
+	ErrInvalidSearchType  = errors.New("type must be one of: project, vacancy, user, tag")
+	ErrSearchWindowTooBig = errors.New("search results are limited to the first 1000 hits")
+)
+
+// SearchQuery — запрос общего поиска. Фильтры по атрибутам применяются к типам,
+// у которых есть этот атрибут, а записи остальных типов при заданном фильтре не ищутся.
+type SearchQuery struct {
+	Query string
+	// ViewerID — пользователь, выполняющий поиск; 0 — анонимный посетитель.
+	// Проекты по ссылке и закрытые проекты находятся только для их участников.
+	ViewerID uint
+	// Type оставляет в выдаче записи одного типа
+	Type string
+	// Tag — тег проекта или пользователя
+	Tag string
+	// Technology — технология вакансии
+	Technology string
+	// Country — страна пользователя или автора проекта
+	Country string
+	// Status — статус проекта или проекта вакансии
+	Status string
+}
+
+// SearchHit — найденная запись одного из типов; заполнено поле, соответствующее Type
+type SearchHit struct {
+	Type     string
+	Rank     float64
+	Headline string
+	Project  *models.Project
+	Vacancy  *models.ProjectVacancy
+	User     *models.User
+	Tag      *models.Tag
+}
+
+// SearchFacets — распределение найденных записей по значениям атрибутов
+type SearchFacets struct {
+	Types        []repository.FacetCount
+	Tags         []repository.FacetCount
+	Technologies []repository.FacetCount
+	Countries    []repository.FacetCount
+	Statuses     []repository.FacetCount
+}
+
+type SearchServiceInterface interface {
+	Search(query SearchQuery, params pagination.Params) ([]SearchHit, pagination.Page, *SearchFacets, error)
+}
+
+type SearchService struct {
+	projectRepo *repository.ProjectRepository
+	vacancyRepo *repository.ProjectVacancyRepository
+	userRepo    *repository.UserRepository
+	tagRepo     *repository.TagRepository
+}
+
+func NewSearchService(projectRepo *repository.ProjectRepository, vacancyRepo *repository.ProjectVacancyRepository, userRepo *repository.UserRepository, tagRepo *repository.TagRepository) SearchServiceInterface {
+	return &SearchService{
+		projectRepo: projectRepo,
+		vacancyRepo: vacancyRepo,
+		userRepo:    userRepo,
+		tagRepo:     tagRepo,
+	}
+}
+
+// supports сообщает, есть ли у записей типа все атрибуты, по которым задан фильтр
+func (q SearchQuery) supports(searchType string) bool {
+	switch searchType {
+	case SearchTypeProject:
+		return q.Technology == ""
+	case SearchTypeVacancy:
+		return q.Tag == "" && q.Country == ""
+	case SearchTypeUser:
+		return q.Technology == "" && q.Status == ""
+	default:
+		return q.Tag == "" && q.Technology == "" && q.Country == "" && q.Status == ""
+	}
+}
+
+// Search ищет записи всех типов и сливает их в одну выдачу по убыванию релевантности.
+// Фасет типов считается без учета фильтра по типу, чтобы по нему можно было переключаться.
+func (s *SearchService) Search(query SearchQuery, params pagination.Params) ([]SearchHit, pagination.Page, *SearchFacets, error) {
+	var page pagination.Page
+	if query.Type != "" && !isSearchType(query.Type) {
+		return nil, page, nil, ErrInvalidSearchType
+	}
+	if params.UsesCursor() {
+		return nil, page, nil, pagination.ErrCursorUnsupported
+	}
+	window := params.Offset() + params.PageSize
+	if window > maxSearchWindow {
+		return nil, page, nil, ErrSearchWindowTooBig
+	}
+
+	facets := &SearchFacets{}
+	var hits []SearchHit
+	for _, searchType := range searchTypes {
+		if !query.supports(searchType) {
+			continue
+		}
+		// Записи типов, исключенных фильтром по типу, только считаются
+		size := window
+		if query.Type != "" && query.Type != searchType {
+			size = 0
+		}
+		found, total, err := s.searchType(searchType, query, pagination.Params{Page: 1, PageSize: size})
+		if err != nil {
+			return nil, page, nil, err
+		}
+		if total == 0 {
+			continue
+		}
+		facets.Types = append(facets.Types, repository.FacetCount{Value: searchType, Count: total})
+		if size == 0 {
+			continue
+		}
+		page.Total += total
+		hits = append(hits, found...)
+		if err := s.addFacets(facets, searchType, query); err != nil {
+			return nil, page, nil, err
+		}
+	}
+	sortFacets(facets.Types)
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Rank > hits[j].Rank
+	})
+	offset := min(params.Offset(), len(hits))
+	end := min(offset+params.PageSize, len(hits))
+	page.HasMore = int64(offset+params.PageSize) < page.Total
+	return hits[offset:end], page, facets, nil
+}
+
+func isSearchType(value string) bool {
+	for _, searchType := range searchTypes {
+		if searchType == value {
+			return true
+		}
+	}
+	return false
+}
+
+// searchType ищет записи одного типа и возвращает их вместе с общим числом найденных
+func (s *SearchService) searchType(searchType string, query SearchQuery, params pagination.Params) ([]SearchHit, int64, error) {
+	var hits []SearchHit
+	switch searchType {
+	case SearchTypeProject:
+		results, page, err := s.projectRepo.Search(query.Query, query.projectFilter(), params)
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := range results {
+			hits = append(hits, SearchHit{Type: searchType, Rank: results[i].Rank, Headline: results[i].Headline, Project: &results[i].Project})
+		}
+		return hits, page.Total, nil
+	case SearchTypeVacancy:
+		results, page, err := s.vacancyRepo.Search(query.Query, query.vacancyFilter(), params)
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := range results {
+			hits = append(hits, SearchHit{Type: searchType, Rank: results[i].Rank, Headline: results[i].Headline, Vacancy: &results[i].Vacancy})
+		}
+		return hits, page.Total, nil
+	case SearchTypeUser:
+		results, page, err := s.userRepo.Search(query.Query, query.userFilter(), params)
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := range results {
+			hits = append(hits, SearchHit{Type: searchType, Rank: results[i].Rank, Headline: results[i].Headline, User: &results[i].User})
+		}
+		return hits, page.Total, nil
+	default:
+		results, page, err := s.tagRepo.SearchPage(query.Query, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := range results {
+			hits = append(hits, SearchHit{Type: searchType, Rank: results[i].Rank, Headline: results[i].Headline, Tag: &results[i].Tag})
+		}
+		return hits, page.Total, nil
+	}
+}
+
+// addFacets добавляет к facets распределение записей типа по атрибутам
+func (s *SearchService) addFacets(facets *SearchFacets, searchType string, query SearchQuery) error {
+	switch searchType {
+	case SearchTypeProject:
+		counts, err := s.projectRepo.SearchFacets(query.Query, query.projectFilter())
+		if err != nil {
+			return err
+		}
+		facets.Tags = mergeFacets(facets.Tags, counts.Tags)
+		facets.Countries = mergeFacets(facets.Countries, counts.Countries)
+		facets.Statuses = mergeFacets(facets.Statuses, counts.Statuses)
+	case SearchTypeVacancy:
+		counts, err := s.vacancyRepo.SearchFacets(query.Query, query.vacancyFilter())
+		if err != nil {
+			return err
+		}
+		facets.Technologies = mergeFacets(facets.Technologies, counts.Technologies)
+		facets.Statuses = mergeFacets(facets.Statuses, counts.Statuses)
+	case SearchTypeUser:
+		counts, err := s.userRepo.SearchFacets(query.Query, query.userFilter())
+		if err != nil {
+			return err
+		}
+		facets.Tags = mergeFacets(facets.Tags, counts.Tags)
+		facets.Countries = mergeFacets(facets.Countries, counts.Countries)
+	}
+	return nil
+}
+
+func (q SearchQuery) projectFilter() repository.ProjectFilter {
+	return repository.ProjectFilter{ViewerID: q.ViewerID, Status: q.Status, Tag: q.Tag, OwnerCountry: q.Country}
+}
+
+func (q SearchQuery) vacancyFilter() repository.VacancyFilter {
+	return repository.VacancyFilter{ViewerID: q.ViewerID, ProjectStatus: q.Status, Technology: q.Technology}
+}
+
+func (q SearchQuery) userFilter() repository.UserFilter {
+	return repository.UserFilter{Tag: q.Tag, Country: q.Country}
+}
+
+// mergeFacets складывает счетчики одинаковых значений двух фасетов
+func mergeFacets(a, b []repository.FacetCount) []repository.FacetCount {
+	index := make(map[string]int, len(a))
+	for i, count := range a {
+		index[count.Value] = i
+	}
+	for _, count := range b {
+		if i, ok := index[count.Value]; ok {
+			a[i].Count += count.Count
+			continue
+		}
+		index[count.Value] = len(a)
+		a = append(a, count)
+	}
+	sortFacets(a)
+	return a
+}
+
+// sortFacets упорядочивает значения фасета по убыванию числа записей
+func sortFacets(counts []repository.FacetCount) {
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session has been revoked")
+)
+
+type SessionServiceInterface interface {
+	List(userID uint) ([]models.Session, error)
+	Revoke(userID, sessionID uint) error
+	Touch(sessionID uint) error
+}
+
+type SessionService struct {
+	sessionRepo      *repository.SessionRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
+}
+
+func NewSessionService(sessionRepo *repository.SessionRepository, refreshTokenRepo *repository.RefreshTokenRepository) SessionServiceInterface {
+	return &SessionService{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+	}
+}
+
+func (s *SessionService) List(userID uint) ([]models.Session, error) {
+	return s.sessionRepo.ListActive(userID, time.Now())
+}
+
+// Revoke завершает сессию на устройстве: отзывает ее refresh токены,
+// а выданные ей access токены перестают приниматься.
+func (s *SessionService) Revoke(userID, sessionID uint) error {
+	session, err := s.sessionRepo.GetByUserAndID(userID, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(session.FamilyID)
+}
+
+// Touch проверяет, что сессия не отозвана, и отмечает ее активность
+func (s *SessionService) Touch(sessionID uint) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+	if session.RevokedAt != nil {
+		return ErrSessionRevoked
+	}
+
+	return s.sessionRepo.TouchLastSeen(session.ID, time.Now(), lastUsedInterval)
+}
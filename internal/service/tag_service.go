@@ -2,6 +2,7 @@ package service
 
 import (
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
 	"github.com/levstremilov/shance-app/internal/repository"
 )
 
@@ -11,6 +12,7 @@ type TagServiceInterface interface {
 	Update(tag *models.Tag) error
 	Delete(id uint) error
 	List() ([]models.Tag, error)
+	ListPage(filter repository.TagFilter, params pagination.Params) ([]models.Tag, pagination.Page, error)
 	Search(query string) ([]models.Tag, error)
 }
 
@@ -44,6 +46,10 @@ func (s *TagService) List() ([]models.Tag, error) {
 	return s.tagRepo.List()
 }
 
+func (s *TagService) ListPage(filter repository.TagFilter, params pagination.Params) ([]models.Tag, pagination.Page, error) {
+	return s.tagRepo.ListPage(filter, params)
+}
+
 func (s *TagService) Search(query string) ([]models.Tag, error) {
 	return s.tagRepo.Search(query)
 }
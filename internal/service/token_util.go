@@ -0,0 +1,51 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+)
+
+// generateOpaqueToken возвращает криптографически случайную строку из n байт в base64url.
+func generateOpaqueToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken возвращает SHA-256 хеш токена для хранения в базе.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueOneTimeToken создает новый одноразовый токен, делая недействительными выданные ранее
+func issueOneTimeToken(tokenRepo *repository.OneTimeTokenRepository, userID uint, purpose string, ttl time.Duration) (string, error) {
+	if err := tokenRepo.InvalidateForUser(userID, purpose); err != nil {
+		return "", err
+	}
+
+	token, err := generateOpaqueToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = tokenRepo.Create(&models.OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
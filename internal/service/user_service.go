@@ -1,48 +1,60 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
+	"github.com/levstremilov/shance-app/internal/auth"
 	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/pagination"
+	"github.com/levstremilov/shance-app/internal/policy"
 	"github.com/levstremilov/shance-app/internal/repository"
+	"gorm.io/gorm"
 )
 
 type UserServiceInterface interface {
 	GetMe(c *gin.Context) (*models.User, error)
 	GetByID(id uint) (*models.User, error)
-	Update(user *models.User) error
+	// Update сохраняет перечисленные поля пользователя
+	Update(user *models.User, fields ...string) error
 	Create(user *models.User) error
 	GetByEmail(email string) (*models.User, error)
 	Delete(id uint) error
 	List() ([]models.User, error)
-	GetOwnProjects(id uint) ([]models.Project, error)
+	ListPage(filter repository.UserFilter, params pagination.Params) ([]models.User, pagination.Page, error)
+	GetOwnProjects(id, viewerID uint) ([]models.Project, error)
+	UpdateRole(id uint, role string) (*models.User, error)
 }
 
+var ErrInvalidRole = errors.New("invalid role")
+
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo         *repository.UserRepository
+	refreshTokenRepo *repository.RefreshTokenRepository
 }
 
-func NewUserService(userRepo *repository.UserRepository) UserServiceInterface {
+func NewUserService(userRepo *repository.UserRepository, refreshTokenRepo *repository.RefreshTokenRepository) UserServiceInterface {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
 	}
 }
 
 func (s *UserService) GetMe(c *gin.Context) (*models.User, error) {
-	userID, exists := c.Get("user_id")
+	principal, exists := auth.FromContext(c)
 	if !exists {
 		return nil, fmt.Errorf("user not authenticated")
 	}
-	return s.userRepo.GetByID(userID.(uint))
+	return s.userRepo.GetByID(principal.UserID)
 }
 
 func (s *UserService) GetByID(id uint) (*models.User, error) {
 	return s.userRepo.GetByID(id)
 }
 
-func (s *UserService) Update(user *models.User) error {
-	return s.userRepo.Update(user)
+func (s *UserService) Update(user *models.User, fields ...string) error {
+	return s.userRepo.Update(user, fields...)
 }
 
 func (s *UserService) Create(user *models.User) error {
@@ -61,6 +73,52 @@ func (s *UserService) List() ([]models.User, error) {
 	return s.userRepo.List()
 }
 
-func (s *UserService) GetOwnProjects(id uint) ([]models.Project, error) {
-	return s.userRepo.OwnProjects(id)
+func (s *UserService) ListPage(filter repository.UserFilter, params pagination.Params) ([]models.User, pagination.Page, error) {
+	return s.userRepo.ListPage(filter, params)
+}
+
+// GetOwnProjects возвращает проекты пользователя, которые видит пользователь viewerID
+// (0 — анонимный посетитель). Черновики видит только сам автор.
+func (s *UserService) GetOwnProjects(id, viewerID uint) ([]models.Project, error) {
+	projects, err := s.userRepo.OwnProjects(id, viewerID)
+	if err != nil || viewerID == id {
+		return projects, err
+	}
+	visible := projects[:0]
+	for _, p := range projects {
+		if p.Status != models.ProjectStatusDraft {
+			visible = append(visible, p)
+		}
+	}
+	return visible, nil
+}
+
+// UpdateRole меняет глобальную роль пользователя и завершает все его сессии:
+// access токены со старой ролью сразу перестают приниматься, а новая роль
+// попадает в токены при следующем входе.
+func (s *UserService) UpdateRole(id uint, role string) (*models.User, error) {
+	if !policy.IsGlobalRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	err = s.userRepo.DB().Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.WithTx(tx).UpdateRole(user.ID, role); err != nil {
+			return err
+		}
+		return s.refreshTokenRepo.WithTx(tx).RevokeAllForUser(user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+	return user, nil
 }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/dbtest"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+)
+
+func TestUpdateRoleRevokesSessions(t *testing.T) {
+	db := dbtest.Open(t, &models.User{}, &models.Session{}, &models.RefreshToken{})
+	userRepo := repository.NewUserRepository(db)
+	users := NewUserService(userRepo, repository.NewRefreshTokenRepository(db))
+
+	user := &models.User{Email: "role@example.com", PasswordHash: "hash", FirstName: "Old", Role: "user"}
+	if err := userRepo.Create(user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	expires := time.Now().Add(time.Hour)
+	if err := db.Create(&models.Session{UserID: user.ID, FamilyID: "family", ExpiresAt: expires}).Error; err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if err := db.Create(&models.RefreshToken{UserID: user.ID, FamilyID: "family", TokenHash: "hash", ExpiresAt: expires}).Error; err != nil {
+		t.Fatalf("create refresh token: %v", err)
+	}
+
+	updated, err := users.UpdateRole(user.ID, "moderator")
+	if err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	if updated.Role != "moderator" {
+		t.Fatalf("returned role = %q", updated.Role)
+	}
+
+	stored, err := userRepo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.Role != "moderator" || stored.FirstName != "Old" {
+		t.Fatalf("stored user role %q, first name %q; want moderator, Old", stored.Role, stored.FirstName)
+	}
+
+	var active int64
+	db.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", user.ID).Count(&active)
+	if active != 0 {
+		t.Fatalf("%d sessions still active after role change", active)
+	}
+	db.Model(&models.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", user.ID).Count(&active)
+	if active != 0 {
+		t.Fatalf("%d refresh tokens still active after role change", active)
+	}
+}
+
+func TestStaleUserUpdateKeepsRole(t *testing.T) {
+	db := dbtest.Open(t, &models.User{}, &models.Session{}, &models.RefreshToken{})
+	userRepo := repository.NewUserRepository(db)
+	users := NewUserService(userRepo, repository.NewRefreshTokenRepository(db))
+
+	user := &models.User{Email: "demoted@example.com", PasswordHash: "hash", Role: "admin"}
+	if err := userRepo.Create(user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	// Профиль прочитан до понижения роли, а сохранен после него
+	stale, err := users.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if _, err := users.UpdateRole(user.ID, "user"); err != nil {
+		t.Fatalf("UpdateRole: %v", err)
+	}
+	stale.City = "Казань"
+	if err := users.Update(stale, "city", "role"); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	stored, err := userRepo.GetByID(user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.Role != "user" || stored.City != "Казань" {
+		t.Fatalf("stored role %q, city %q; want user, Казань", stored.Role, stored.City)
+	}
+}
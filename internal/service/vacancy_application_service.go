@@ -0,0 +1,512 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/storage"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVacancyNotFound         = errors.New("vacancy not found")
+	ErrVacancyClosed           = errors.New("vacancy does not accept applications")
+	ErrApplicationNotFound     = errors.New("application not found")
+	ErrApplicationExists       = errors.New("application to this vacancy is already under review")
+	ErrInvalidApplicationStage = errors.New("stage must be one of: new, screening, interview, offer, accepted, rejected")
+	ErrStageChangeNotAllowed   = errors.New("application stage change is not allowed")
+	ErrApplicationStageChanged = errors.New("application stage has changed, reload the application and try again")
+	ErrCoverLetterTooLong      = fmt.Errorf("cover letter must be at most %d characters", maxCoverLetterLength)
+	ErrApplicationNoteInvalid  = fmt.Errorf("note must be between 1 and %d characters", maxApplicationNoteLength)
+	ErrStageCommentTooLong     = fmt.Errorf("comment must be at most %d characters", maxStageCommentLength)
+	ErrAttachmentTooLarge      = errors.New("attachment is too large")
+	ErrUnsupportedAttachment   = errors.New("attachment must be a PDF document")
+	ErrAttachmentNotFound      = errors.New("application has no attachment")
+)
+
+// Ограничения длины текстов отклика, в символах
+const (
+	maxCoverLetterLength     = 5000
+	maxApplicationNoteLength = 2000
+	maxStageCommentLength    = 500
+	maxAttachmentNameLength  = 200
+)
+
+// applicationStages — допустимые переходы между этапами отбора по решению команды
+// проекта. Отклик движется только вперед; принятый и отклоненный отклики больше
+// не меняют этап. Отозвать отклик может только кандидат.
+var applicationStages = map[string][]string{
+	models.ApplicationStageNew: {models.ApplicationStageScreening, models.ApplicationStageInterview,
+		models.ApplicationStageOffer, models.ApplicationStageAccepted, models.ApplicationStageRejected},
+	models.ApplicationStageScreening: {models.ApplicationStageInterview, models.ApplicationStageOffer,
+		models.ApplicationStageAccepted, models.ApplicationStageRejected},
+	models.ApplicationStageInterview: {models.ApplicationStageOffer, models.ApplicationStageAccepted, models.ApplicationStageRejected},
+	models.ApplicationStageOffer:     {models.ApplicationStageAccepted, models.ApplicationStageRejected},
+	models.ApplicationStageAccepted:  {},
+	models.ApplicationStageRejected:  {},
+}
+
+// ApplicationAttachment — файл, приложенный к отклику
+type ApplicationAttachment struct {
+	Name string
+	Body io.Reader
+}
+
+// ApplicationStageInput — запрос на смену этапа отклика. CloseVacancy при принятии
+// кандидата помечает вакансию заполненной.
+type ApplicationStageInput struct {
+	Stage        string
+	Comment      string
+	CloseVacancy bool
+}
+
+type VacancyApplicationServiceInterface interface {
+	Apply(ctx context.Context, vacancyID, userID uint, coverLetter string, attachment *ApplicationAttachment) (*models.VacancyApplication, error)
+	ListForUser(userID uint) ([]models.VacancyApplication, error)
+	Withdraw(ctx context.Context, applicationID, userID uint) error
+	OpenAttachment(ctx context.Context, applicationID, userID uint) (*models.VacancyApplication, io.ReadCloser, error)
+	OpenProjectAttachment(ctx context.Context, projectID, applicationID uint) (*models.VacancyApplication, io.ReadCloser, error)
+	List(filter repository.ApplicationFilter) ([]models.VacancyApplication, error)
+	Get(projectID, applicationID uint) (*models.VacancyApplication, error)
+	Move(projectID, applicationID, userID uint, input ApplicationStageInput) (*models.VacancyApplication, *models.ProjectMember, error)
+	AddNote(projectID, applicationID, authorID uint, body string) (*models.ApplicationNote, error)
+	Purge(ctx context.Context, userID uint) error
+	ScheduleAttachmentMove() error
+	MoveAttachments(ctx context.Context, job *models.Job) (string, error)
+}
+
+type VacancyApplicationService struct {
+	applicationRepo *repository.VacancyApplicationRepository
+	vacancyRepo     *repository.ProjectVacancyRepository
+	projectRepo     *repository.ProjectRepository
+	jobRepo         *repository.JobRepository
+	// attachments — закрытое хранилище вложений; public — общее хранилище,
+	// где вложения лежали раньше и откуда их переносит MoveAttachments
+	attachments   storage.Storage
+	public        storage.Storage
+	maxAttachment int64
+}
+
+func NewVacancyApplicationService(
+	applicationRepo *repository.VacancyApplicationRepository,
+	vacancyRepo *repository.ProjectVacancyRepository,
+	projectRepo *repository.ProjectRepository,
+	jobRepo *repository.JobRepository,
+	attachments storage.Storage,
+	public storage.Storage,
+	maxAttachmentSize int64,
+) VacancyApplicationServiceInterface {
+	return &VacancyApplicationService{
+		applicationRepo: applicationRepo,
+		vacancyRepo:     vacancyRepo,
+		projectRepo:     projectRepo,
+		jobRepo:         jobRepo,
+		attachments:     attachments,
+		public:          public,
+		maxAttachment:   maxAttachmentSize,
+	}
+}
+
+// IsApplicationStage сообщает, является ли stage этапом отбора откликов
+func IsApplicationStage(stage string) bool {
+	_, ok := applicationStages[stage]
+	return ok || stage == models.ApplicationStageWithdrawn
+}
+
+// isActiveApplicationStage сообщает, рассматривается ли еще отклик на этапе stage
+func isActiveApplicationStage(stage string) bool {
+	return len(applicationStages[stage]) > 0
+}
+
+// Apply создает отклик пользователя на открытую вакансию проекта, который он может
+// открыть по ссылке. Вложение необязательно; его тип определяется по содержимому.
+func (s *VacancyApplicationService) Apply(ctx context.Context, vacancyID, userID uint, coverLetter string, attachment *ApplicationAttachment) (*models.VacancyApplication, error) {
+	coverLetter = strings.TrimSpace(coverLetter)
+	if utf8.RuneCountInString(coverLetter) > maxCoverLetterLength {
+		return nil, ErrCoverLetterTooLong
+	}
+
+	vacancy, err := s.vacancyRepo.GetByID(vacancyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVacancyNotFound
+		}
+		return nil, err
+	}
+	visible, err := s.projectRepo.IsVisible(vacancy.ProjectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrVacancyNotFound
+	}
+	if vacancy.Status != models.VacancyStatusOpen {
+		return nil, fmt.Errorf("%w: vacancy is %s", ErrVacancyClosed, vacancy.Status)
+	}
+	if vacancyExpired(vacancy, time.Now()) {
+		return nil, fmt.Errorf("%w: vacancy has expired", ErrVacancyClosed)
+	}
+	if slices.Contains(closedToNewcomers, vacancy.Project.Status) {
+		return nil, fmt.Errorf("%w: project is %s", ErrVacancyClosed, vacancy.Project.Status)
+	}
+
+	member, err := s.projectRepo.IsMember(vacancy.ProjectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member || vacancy.Project.UserID == userID {
+		return nil, ErrAlreadyProjectMember
+	}
+	active, err := s.applicationRepo.HasActive(vacancyID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return nil, ErrApplicationExists
+	}
+
+	application := &models.VacancyApplication{
+		VacancyID:   vacancyID,
+		ProjectID:   vacancy.ProjectID,
+		UserID:      userID,
+		CoverLetter: coverLetter,
+		Stage:       models.ApplicationStageNew,
+	}
+	if attachment != nil {
+		if err := s.storeAttachment(ctx, application, attachment); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.applicationRepo.Create(application); err != nil {
+		if application.AttachmentKey != "" {
+			removeOrphanedFiles(s.attachments, []string{application.AttachmentKey})
+		}
+		return nil, err
+	}
+	return s.applicationRepo.GetByID(application.ID)
+}
+
+// storeAttachment проверяет размер и тип вложения и сохраняет его в закрытом хранилище
+func (s *VacancyApplicationService) storeAttachment(ctx context.Context, application *models.VacancyApplication, attachment *ApplicationAttachment) error {
+	// Из имени файла убирается путь, который передают некоторые браузеры
+	name := path.Base(strings.ReplaceAll(strings.TrimSpace(attachment.Name), "\\", "/"))
+	if name == "." || name == "/" {
+		name = ""
+	}
+	if utf8.RuneCountInString(name) > maxAttachmentNameLength {
+		name = string([]rune(name)[:maxAttachmentNameLength])
+	}
+
+	data, err := io.ReadAll(io.LimitReader(attachment.Body, s.maxAttachment+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > s.maxAttachment {
+		return ErrAttachmentTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := portfolioFileExtensions[contentType]
+	if !ok {
+		return ErrUnsupportedAttachment
+	}
+
+	token, err := generateOpaqueToken(16)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("vacancies/%d/applications/%s%s", application.VacancyID, token, ext)
+	if err := s.attachments.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return err
+	}
+	application.AttachmentKey = key
+	application.AttachmentName = name
+	application.AttachmentContentType = contentType
+	application.AttachmentSize = int64(len(data))
+	return nil
+}
+
+// ListForUser возвращает отклики пользователя, начиная с последних
+func (s *VacancyApplicationService) ListForUser(userID uint) ([]models.VacancyApplication, error) {
+	return s.applicationRepo.ListByUser(userID)
+}
+
+// Withdraw отзывает рассматриваемый отклик пользователя и удаляет его вложение:
+// команда проекта больше не может его скачать
+func (s *VacancyApplicationService) Withdraw(ctx context.Context, applicationID, userID uint) error {
+	application, err := s.applicationRepo.GetByID(applicationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrApplicationNotFound
+		}
+		return err
+	}
+	// Чужой отклик не отличается от несуществующего
+	if application.UserID != userID {
+		return ErrApplicationNotFound
+	}
+	if !isActiveApplicationStage(application.Stage) {
+		return fmt.Errorf("%w: application is %s", ErrStageChangeNotAllowed, application.Stage)
+	}
+
+	err = s.applicationRepo.Move(application, &models.ApplicationStageChange{
+		FromStage: application.Stage,
+		ToStage:   models.ApplicationStageWithdrawn,
+		UserID:    &userID,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrApplicationStageChanged
+		}
+		return err
+	}
+	return s.removeAttachment(ctx, application)
+}
+
+// OpenAttachment открывает вложение отклика для его автора
+func (s *VacancyApplicationService) OpenAttachment(ctx context.Context, applicationID, userID uint) (*models.VacancyApplication, io.ReadCloser, error) {
+	application, err := s.applicationRepo.GetByID(applicationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrApplicationNotFound
+		}
+		return nil, nil, err
+	}
+	// Чужой отклик не отличается от несуществующего
+	if application.UserID != userID {
+		return nil, nil, ErrApplicationNotFound
+	}
+	return s.openAttachment(ctx, application)
+}
+
+// OpenProjectAttachment открывает вложение отклика на вакансию проекта для его команды
+func (s *VacancyApplicationService) OpenProjectAttachment(ctx context.Context, projectID, applicationID uint) (*models.VacancyApplication, io.ReadCloser, error) {
+	application, err := s.applicationRepo.GetByID(applicationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrApplicationNotFound
+		}
+		return nil, nil, err
+	}
+	if application.ProjectID != projectID {
+		return nil, nil, ErrApplicationNotFound
+	}
+	return s.openAttachment(ctx, application)
+}
+
+func (s *VacancyApplicationService) openAttachment(ctx context.Context, application *models.VacancyApplication) (*models.VacancyApplication, io.ReadCloser, error) {
+	if application.AttachmentKey == "" {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	body, err := s.attachments.Get(ctx, application.AttachmentKey)
+	// Пока MoveAttachments не завершился, вложение может лежать в общем хранилище
+	if errors.Is(err, storage.ErrNotFound) {
+		body, err = s.public.Get(ctx, application.AttachmentKey)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+	return application, body, nil
+}
+
+// removeAttachment удаляет вложение отклика из обоих хранилищ и сведения о нем
+func (s *VacancyApplicationService) removeAttachment(ctx context.Context, application *models.VacancyApplication) error {
+	if application.AttachmentKey == "" {
+		return nil
+	}
+	if err := s.applicationRepo.ClearAttachment(application.ID); err != nil {
+		return err
+	}
+	for _, files := range []storage.Storage{s.attachments, s.public} {
+		if err := files.Delete(ctx, application.AttachmentKey); err != nil {
+			log.Printf("Failed to remove attachment of application %d: %v", application.ID, err)
+		}
+	}
+	return nil
+}
+
+// List возвращает отклики на вакансии проекта в порядке подачи
+func (s *VacancyApplicationService) List(filter repository.ApplicationFilter) ([]models.VacancyApplication, error) {
+	if filter.Stage != "" && !IsApplicationStage(filter.Stage) {
+		return nil, ErrInvalidApplicationStage
+	}
+	return s.applicationRepo.ListByProject(filter)
+}
+
+// Get возвращает отклик на вакансию проекта с заметками и историей этапов
+func (s *VacancyApplicationService) Get(projectID, applicationID uint) (*models.VacancyApplication, error) {
+	application, err := s.applicationRepo.GetDetailed(applicationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApplicationNotFound
+		}
+		return nil, err
+	}
+	if application.ProjectID != projectID {
+		return nil, ErrApplicationNotFound
+	}
+	return application, nil
+}
+
+// Move переводит отклик на следующий этап отбора. При принятии кандидат становится
+// участником проекта с ролью, указанной в вакансии; участник возвращается вторым значением.
+func (s *VacancyApplicationService) Move(projectID, applicationID, userID uint, input ApplicationStageInput) (*models.VacancyApplication, *models.ProjectMember, error) {
+	if _, ok := applicationStages[input.Stage]; !ok {
+		return nil, nil, ErrInvalidApplicationStage
+	}
+	if utf8.RuneCountInString(input.Comment) > maxStageCommentLength {
+		return nil, nil, ErrStageCommentTooLong
+	}
+
+	application, err := s.Get(projectID, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.Contains(applicationStages[application.Stage], input.Stage) {
+		return nil, nil, fmt.Errorf("%w: %s -> %s", ErrStageChangeNotAllowed, application.Stage, input.Stage)
+	}
+
+	change := &models.ApplicationStageChange{
+		FromStage: application.Stage,
+		ToStage:   input.Stage,
+		UserID:    &userID,
+		Comment:   input.Comment,
+	}
+	var member *models.ProjectMember
+	if input.Stage == models.ApplicationStageAccepted {
+		member, err = s.applicationRepo.Accept(application, change, application.Vacancy.Role, input.CloseVacancy, time.Now())
+	} else {
+		err = s.applicationRepo.Move(application, change)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrApplicationStageChanged
+		}
+		return nil, nil, err
+	}
+
+	updated, err := s.Get(projectID, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, member, nil
+}
+
+// AddNote добавляет к отклику заметку команды проекта
+func (s *VacancyApplicationService) AddNote(projectID, applicationID, authorID uint, body string) (*models.ApplicationNote, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxApplicationNoteLength {
+		return nil, ErrApplicationNoteInvalid
+	}
+	if _, err := s.Get(projectID, applicationID); err != nil {
+		return nil, err
+	}
+
+	note := &models.ApplicationNote{
+		ApplicationID: applicationID,
+		AuthorID:      authorID,
+		Body:          body,
+	}
+	if err := s.applicationRepo.AddNote(note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// Purge отзывает рассматриваемые отклики пользователя и удаляет вложения всех его откликов
+func (s *VacancyApplicationService) Purge(ctx context.Context, userID uint) error {
+	applications, err := s.applicationRepo.ListByUser(userID)
+	if err != nil {
+		return err
+	}
+	for i := range applications {
+		application := &applications[i]
+		if isActiveApplicationStage(application.Stage) {
+			err := s.applicationRepo.Move(application, &models.ApplicationStageChange{
+				FromStage: application.Stage,
+				ToStage:   models.ApplicationStageWithdrawn,
+				Comment:   "applicant account deleted",
+			})
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+		if err := s.removeAttachment(ctx, application); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScheduleAttachmentMove планирует перенос вложений в закрытое хранилище,
+// если он еще не выполнен и не запланирован
+func (s *VacancyApplicationService) ScheduleAttachmentMove() error {
+	_, err := s.jobRepo.FindActive(0, models.JobTypeAttachmentMove)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	completed, err := s.jobRepo.ListByStatus(0, models.JobTypeAttachmentMove, models.JobStatusCompleted)
+	if err != nil {
+		return err
+	}
+	if len(completed) > 0 {
+		return nil
+	}
+	return s.jobRepo.Create(&models.Job{
+		Type:   models.JobTypeAttachmentMove,
+		Status: models.JobStatusPending,
+		RunAt:  time.Now(),
+	})
+}
+
+// MoveAttachments переносит вложения откликов, сохраненные в общем хранилище,
+// в закрытое. Перенесенное вложение в общем хранилище уже не найдется, поэтому
+// повторный запуск продолжает с того места, где остановился прошлый.
+func (s *VacancyApplicationService) MoveAttachments(ctx context.Context, job *models.Job) (string, error) {
+	applications, err := s.applicationRepo.ListWithAttachment()
+	if err != nil {
+		return "", err
+	}
+
+	moved := 0
+	for i := range applications {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		application := &applications[i]
+		body, err := s.public.Get(ctx, application.AttachmentKey)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		err = s.attachments.Put(ctx, application.AttachmentKey, body, application.AttachmentSize, application.AttachmentContentType)
+		body.Close()
+		if err != nil {
+			return "", err
+		}
+		if err := s.public.Delete(ctx, application.AttachmentKey); err != nil {
+			return "", err
+		}
+		moved++
+	}
+	return fmt.Sprintf("moved %d attachments", moved), nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/dbtest"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/repository"
+	"github.com/levstremilov/shance-app/internal/storage"
+)
+
+// Порядок этапов отбора: отклик может двигаться только вперед
+var applicationStageOrder = []string{
+	models.ApplicationStageNew,
+	models.ApplicationStageScreening,
+	models.ApplicationStageInterview,
+	models.ApplicationStageOffer,
+}
+
+func TestApplicationStagesMoveForwardOnly(t *testing.T) {
+	for i, from := range applicationStageOrder {
+		for j, to := range applicationStageOrder {
+			allowed := slices.Contains(applicationStages[from], to)
+			if allowed != (j > i) {
+				t.Errorf("%s -> %s allowed = %v, want %v", from, to, allowed, j > i)
+			}
+		}
+		for _, to := range []string{models.ApplicationStageAccepted, models.ApplicationStageRejected} {
+			if !slices.Contains(applicationStages[from], to) {
+				t.Errorf("%s -> %s is not allowed", from, to)
+			}
+		}
+	}
+}
+
+func TestApplicationStagesFinal(t *testing.T) {
+	for _, stage := range []string{models.ApplicationStageAccepted, models.ApplicationStageRejected, models.ApplicationStageWithdrawn} {
+		if isActiveApplicationStage(stage) {
+			t.Errorf("%s application is still under review", stage)
+		}
+	}
+	for _, stage := range applicationStageOrder {
+		if !isActiveApplicationStage(stage) {
+			t.Errorf("%s application is not under review", stage)
+		}
+	}
+
+	// Отозвать отклик может только кандидат: команда не переводит отклик в withdrawn
+	for from, targets := range applicationStages {
+		if slices.Contains(targets, models.ApplicationStageWithdrawn) {
+			t.Errorf("%s -> withdrawn is allowed for the project team", from)
+		}
+	}
+}
+
+func TestIsApplicationStage(t *testing.T) {
+	for _, stage := range append(slices.Clone(applicationStageOrder),
+		models.ApplicationStageAccepted, models.ApplicationStageRejected, models.ApplicationStageWithdrawn) {
+		if !IsApplicationStage(stage) {
+			t.Errorf("IsApplicationStage(%q) = false", stage)
+		}
+	}
+	for _, stage := range []string{"", "hired", "New"} {
+		if IsApplicationStage(stage) {
+			t.Errorf("IsApplicationStage(%q) = true", stage)
+		}
+	}
+}
+
+type attachmentTest struct {
+	applications *VacancyApplicationService
+	repo         *repository.VacancyApplicationRepository
+	jobRepo      *repository.JobRepository
+	private      storage.Storage
+	public       storage.Storage
+}
+
+func newAttachmentTest(t *testing.T) *attachmentTest {
+	t.Helper()
+	db := dbtest.Open(t, &models.User{}, &models.VacancyApplication{}, &models.ApplicationStageChange{}, &models.Job{})
+	// Индекс поиска вакансий рассчитан на PostgreSQL; для подгрузки вакансии
+	// к отклику достаточно пустой таблицы
+	if err := db.Exec("CREATE TABLE project_vacancies (id integer PRIMARY KEY, project_id integer, title text)").Error; err != nil {
+		t.Fatal(err)
+	}
+	test := &attachmentTest{
+		repo:    repository.NewVacancyApplicationRepository(db),
+		jobRepo: repository.NewJobRepository(db),
+		private: storage.NewLocalStorage(t.TempDir(), ""),
+		public:  storage.NewLocalStorage(t.TempDir(), "http://localhost/uploads/"),
+	}
+	test.applications = NewVacancyApplicationService(test.repo, nil, nil, test.jobRepo, test.private, test.public, 1<<20).(*VacancyApplicationService)
+	return test
+}
+
+// create сохраняет отклик пользователя userID с вложением в хранилище files
+func (test *attachmentTest) create(t *testing.T, userID uint, files storage.Storage) *models.VacancyApplication {
+	t.Helper()
+	application := &models.VacancyApplication{
+		VacancyID:             1,
+		ProjectID:             7,
+		UserID:                userID,
+		Stage:                 models.ApplicationStageNew,
+		AttachmentKey:         "vacancies/1/applications/3f9a.pdf",
+		AttachmentName:        "resume.pdf",
+		AttachmentContentType: "application/pdf",
+		AttachmentSize:        int64(len("%PDF-1.4")),
+	}
+	if err := files.Put(context.Background(), application.AttachmentKey, strings.NewReader("%PDF-1.4"), application.AttachmentSize, application.AttachmentContentType); err != nil {
+		t.Fatal(err)
+	}
+	if err := test.repo.Create(application); err != nil {
+		t.Fatal(err)
+	}
+	return application
+}
+
+func readAttachment(t *testing.T, body io.ReadCloser) string {
+	t.Helper()
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestApplicationAttachmentAccess(t *testing.T) {
+	test := newAttachmentTest(t)
+	ctx := context.Background()
+	application := test.create(t, 1, test.private)
+
+	// Вложение не попадает в общее хранилище, которое раздается статически
+	if _, err := test.public.Get(ctx, application.AttachmentKey); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("attachment is in the public storage: %v", err)
+	}
+
+	_, body, err := test.applications.OpenAttachment(ctx, application.ID, 1)
+	if err != nil {
+		t.Fatalf("OpenAttachment by the applicant: %v", err)
+	}
+	if data := readAttachment(t, body); data != "%PDF-1.4" {
+		t.Fatalf("attachment = %q", data)
+	}
+	if _, _, err := test.applications.OpenAttachment(ctx, application.ID, 2); !errors.Is(err, ErrApplicationNotFound) {
+		t.Fatalf("OpenAttachment by another user error = %v, want ErrApplicationNotFound", err)
+	}
+
+	_, body, err = test.applications.OpenProjectAttachment(ctx, 7, application.ID)
+	if err != nil {
+		t.Fatalf("OpenProjectAttachment: %v", err)
+	}
+	body.Close()
+	if _, _, err := test.applications.OpenProjectAttachment(ctx, 8, application.ID); !errors.Is(err, ErrApplicationNotFound) {
+		t.Fatalf("OpenProjectAttachment of another project error = %v, want ErrApplicationNotFound", err)
+	}
+}
+
+func TestWithdrawRemovesAttachment(t *testing.T) {
+	test := newAttachmentTest(t)
+	ctx := context.Background()
+	application := test.create(t, 1, test.private)
+
+	if err := test.applications.Withdraw(ctx, application.ID, 1); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	if _, err := test.private.Get(ctx, application.AttachmentKey); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("attachment is still stored after Withdraw: %v", err)
+	}
+	if _, _, err := test.applications.OpenProjectAttachment(ctx, 7, application.ID); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Fatalf("OpenProjectAttachment after Withdraw error = %v, want ErrAttachmentNotFound", err)
+	}
+}
+
+func TestMoveAttachments(t *testing.T) {
+	test := newAttachmentTest(t)
+	ctx := context.Background()
+	application := test.create(t, 1, test.public)
+
+	// До переноса вложение уже не выдается по общей ссылке, но доступно через API
+	_, body, err := test.applications.OpenProjectAttachment(ctx, 7, application.ID)
+	if err != nil {
+		t.Fatalf("OpenProjectAttachment before the move: %v", err)
+	}
+	body.Close()
+
+	if err := test.applications.ScheduleAttachmentMove(); err != nil {
+		t.Fatal(err)
+	}
+	if err := test.applications.ScheduleAttachmentMove(); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := test.jobRepo.ListByStatus(0, models.JobTypeAttachmentMove, models.JobStatusPending)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("pending moves = %d, %v; want 1", len(pending), err)
+	}
+	job, err := test.jobRepo.Claim([]string{models.JobTypeAttachmentMove}, time.Now(), time.Now().Add(-time.Hour))
+	if err != nil || job == nil {
+		t.Fatalf("Claim = %v, %v", job, err)
+	}
+
+	if result, err := test.applications.MoveAttachments(ctx, job); err != nil || result != "moved 1 attachments" {
+		t.Fatalf("MoveAttachments = %q, %v", result, err)
+	}
+	if _, err := test.public.Get(ctx, application.AttachmentKey); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("attachment is still in the public storage: %v", err)
+	}
+	body, err = test.private.Get(ctx, application.AttachmentKey)
+	if err != nil {
+		t.Fatalf("attachment is not in the private storage: %v", err)
+	}
+	if data := readAttachment(t, body); data != "%PDF-1.4" {
+		t.Fatalf("moved attachment = %q", data)
+	}
+
+	// Повторный запуск ничего не переносит, а выполненный перенос не планируется снова
+	if result, err := test.applications.MoveAttachments(ctx, job); err != nil || result != "moved 0 attachments" {
+		t.Fatalf("second MoveAttachments = %q, %v", result, err)
+	}
+	if err := test.jobRepo.Complete(job.ID, "moved 1 attachments", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := test.applications.ScheduleAttachmentMove(); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := test.jobRepo.ListByStatus(0, models.JobTypeAttachmentMove, models.JobStatusPending); len(pending) != 0 {
+		t.Fatalf("move is scheduled again after it completed: %d jobs", len(pending))
+	}
+}
@@ -0,0 +1,180 @@
+// Package signing хранит ключи подписи JWT, выбирает активный ключ
+// и публикует открытые ключи в формате JWKS.
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmHS256 = "HS256"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no active signing key")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+// Key — ключ подписи. Ключ публикуется в JWKS до активации, чтобы проверяющие
+// сервисы успели его получить, и принимается при проверке до RetiresAt.
+type Key struct {
+	ID          string
+	Algorithm   string
+	Private     crypto.Signer
+	ActivatesAt time.Time
+	// RetiresAt — нулевое значение означает, что ключ не выводится из оборота
+	RetiresAt time.Time
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+func (k *Key) retired(now time.Time) bool {
+	return !k.RetiresAt.IsZero() && !now.Before(k.RetiresAt)
+}
+
+// KeySet подписывает токены активным ключом и проверяет подписи всеми действующими ключами.
+// Без асимметричных ключей токены подписываются HS256 общим секретом. При наличии
+// ключей HS256 токены принимаются только до AcceptHS256Until — на время перехода.
+type KeySet struct {
+	keys             []*Key
+	secret           []byte
+	acceptHS256Until time.Time
+	now              func() time.Time
+}
+
+func NewKeySet(keys []*Key, secret []byte, acceptHS256Until time.Time) (*KeySet, error) {
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("signing key without kid")
+		}
+		if seen[key.ID] {
+			return nil, fmt.Errorf("duplicate signing key %q", key.ID)
+		}
+		seen[key.ID] = true
+
+		switch key.Private.(type) {
+		case *rsa.PrivateKey:
+			key.Algorithm = AlgorithmRS256
+		case ed25519.PrivateKey:
+			key.Algorithm = AlgorithmEdDSA
+		default:
+			return nil, fmt.Errorf("signing key %q: unsupported key type %T", key.ID, key.Private)
+		}
+	}
+
+	sorted := append([]*Key(nil), keys...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ActivatesAt.After(sorted[j].ActivatesAt)
+	})
+
+	return &KeySet{
+		keys:             sorted,
+		secret:           secret,
+		acceptHS256Until: acceptHS256Until,
+		now:              time.Now,
+	}, nil
+}
+
+// Asymmetric сообщает, настроены ли асимметричные ключи
+func (s *KeySet) Asymmetric() bool {
+	return len(s.keys) > 0
+}
+
+// Sign подписывает claims активным ключом: последним из активированных и не выведенных
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if !s.Asymmetric() {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	}
+
+	key := s.activeKey(s.now())
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// Keyfunc возвращает ключ для проверки подписи токена по его kid и алгоритму
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	now := s.now()
+	alg := token.Method.Alg()
+
+	if alg == AlgorithmHS256 {
+		if s.acceptsHS256(now) {
+			return s.secret, nil
+		}
+		return nil, ErrUnknownKey
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	for _, key := range s.keys {
+		if key.ID == kid && key.Algorithm == alg && !key.retired(now) {
+			return key.Private.Public(), nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// ValidMethods перечисляет алгоритмы, которые сейчас принимаются при проверке
+func (s *KeySet) ValidMethods() []string {
+	now := s.now()
+
+	var methods []string
+	if s.acceptsHS256(now) {
+		methods = append(methods, AlgorithmHS256)
+	}
+	for _, alg := range []string{AlgorithmRS256, AlgorithmEdDSA} {
+		for _, key := range s.keys {
+			if key.Algorithm == alg && !key.retired(now) {
+				methods = append(methods, alg)
+				break
+			}
+		}
+	}
+	return methods
+}
+
+// InHS256Transition сообщает, что переходный период AcceptHS256Until еще не закончился.
+// Нулевое значение AcceptHS256Until означает, что переходного периода нет.
+func (s *KeySet) InHS256Transition() bool {
+	return s.now().Before(s.acceptHS256Until)
+}
+
+// IsSymmetric сообщает, подписан ли токен общим секретом
+func (s *KeySet) IsSymmetric(token *jwt.Token) bool {
+	return token.Method.Alg() == AlgorithmHS256
+}
+
+func (s *KeySet) activeKey(now time.Time) *Key {
+	for _, key := range s.keys {
+		if !key.ActivatesAt.After(now) && !key.retired(now) {
+			return key
+		}
+	}
+	return nil
+}
+
+func (s *KeySet) acceptsHS256(now time.Time) bool {
+	if len(s.secret) == 0 {
+		return false
+	}
+	return !s.Asymmetric() || now.Before(s.acceptHS256Until)
+}
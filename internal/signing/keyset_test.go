@@ -0,0 +1,245 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	testNow    = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	testSecret = []byte("test-secret-test-secret-test-secret")
+)
+
+func newEd25519Key(t *testing.T, id string, activatesAt, retiresAt time.Time) *Key {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Key{ID: id, Private: private, ActivatesAt: activatesAt, RetiresAt: retiresAt}
+}
+
+func newRSAKey(t *testing.T, id string, activatesAt, retiresAt time.Time) *Key {
+	t.Helper()
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Key{ID: id, Private: private, ActivatesAt: activatesAt, RetiresAt: retiresAt}
+}
+
+func newTestKeySet(t *testing.T, keys []*Key, secret []byte, acceptHS256Until time.Time) *KeySet {
+	t.Helper()
+	ks, err := NewKeySet(keys, secret, acceptHS256Until)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	ks.now = func() time.Time { return testNow }
+	return ks
+}
+
+func parse(ks *KeySet, token string) (*jwt.Token, error) {
+	return jwt.Parse(token, ks.Keyfunc, jwt.WithValidMethods(ks.ValidMethods()))
+}
+
+func signedKid(t *testing.T, ks *KeySet) string {
+	t.Helper()
+	token, err := ks.Sign(jwt.MapClaims{"sub": "1"})
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	parsed, err := parse(ks, token)
+	if err != nil {
+		t.Fatalf("parse signed token: %v", err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeySetSignsWithActiveKey(t *testing.T) {
+	day := 24 * time.Hour
+	cases := []struct {
+		name    string
+		keys    []*Key
+		wantKid string
+		wantErr error
+	}{
+		{
+			name: "newest activated key",
+			keys: []*Key{
+				newEd25519Key(t, "old", testNow.Add(-60*day), time.Time{}),
+				newEd25519Key(t, "current", testNow.Add(-day), time.Time{}),
+			},
+			wantKid: "current",
+		},
+		{
+			name: "published key is not used before activation",
+			keys: []*Key{
+				newEd25519Key(t, "current", testNow.Add(-day), time.Time{}),
+				newEd25519Key(t, "next", testNow.Add(day), time.Time{}),
+			},
+			wantKid: "current",
+		},
+		{
+			name: "retired key is skipped",
+			keys: []*Key{
+				newEd25519Key(t, "old", testNow.Add(-60*day), time.Time{}),
+				newEd25519Key(t, "retired", testNow.Add(-day), testNow),
+			},
+			wantKid: "old",
+		},
+		{
+			name: "rsa and ed25519 keys rotate together",
+			keys: []*Key{
+				newEd25519Key(t, "ed", testNow.Add(-60*day), time.Time{}),
+				newRSAKey(t, "rsa", testNow.Add(-day), time.Time{}),
+			},
+			wantKid: "rsa",
+		},
+		{
+			name: "no key is active yet",
+			keys: []*Key{
+				newEd25519Key(t, "next", testNow.Add(day), time.Time{}),
+			},
+			wantErr: ErrNoSigningKey,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ks := newTestKeySet(t, c.keys, nil, time.Time{})
+			if c.wantErr != nil {
+				if _, err := ks.Sign(jwt.MapClaims{}); !errors.Is(err, c.wantErr) {
+					t.Fatalf("Sign error = %v, want %v", err, c.wantErr)
+				}
+				return
+			}
+			if kid := signedKid(t, ks); kid != c.wantKid {
+				t.Fatalf("signed with %q, want %q", kid, c.wantKid)
+			}
+		})
+	}
+}
+
+func TestKeySetVerifiesUntilRetirement(t *testing.T) {
+	day := 24 * time.Hour
+	old := newEd25519Key(t, "old", testNow.Add(-60*day), testNow.Add(day))
+	current := newEd25519Key(t, "current", testNow.Add(-day), time.Time{})
+
+	// Токен подписан старым ключом до ротации
+	before := newTestKeySet(t, []*Key{old}, nil, time.Time{})
+	token, err := before.Sign(jwt.MapClaims{"sub": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ks := newTestKeySet(t, []*Key{old, current}, nil, time.Time{})
+	if _, err := parse(ks, token); err != nil {
+		t.Fatalf("token of a key that is not retired yet: %v", err)
+	}
+
+	ks.now = func() time.Time { return testNow.Add(day) }
+	if _, err := parse(ks, token); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("token of a retired key: error = %v, want ErrUnknownKey", err)
+	}
+	for _, jwk := range ks.JWKS().Keys {
+		if jwk.KeyID == "old" {
+			t.Fatal("retired key is still published in JWKS")
+		}
+	}
+}
+
+func TestKeySetKidLookup(t *testing.T) {
+	key := newEd25519Key(t, "key", testNow.Add(-time.Hour), time.Time{})
+	other := newEd25519Key(t, "other", testNow.Add(-2*time.Hour), time.Time{})
+	ks := newTestKeySet(t, []*Key{key, other}, nil, time.Time{})
+
+	cases := map[string]interface{}{
+		"unknown kid":        "missing",
+		"no kid":             nil,
+		"kid of another key": "other",
+	}
+	for name, kid := range cases {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"sub": "1"})
+		if kid != nil {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key.Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := parse(ks, signed); err == nil {
+			t.Errorf("%s: token accepted", name)
+		}
+	}
+
+	// Алгоритм токена должен совпадать с алгоритмом ключа с этим kid
+	rsaKey := newRSAKey(t, "rsa", testNow.Add(-time.Hour), time.Time{})
+	ks = newTestKeySet(t, []*Key{key, rsaKey}, nil, time.Time{})
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "1"})
+	token.Header["kid"] = "key"
+	signed, err := token.SignedString(rsaKey.Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parse(ks, signed); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("RS256 token with an EdDSA kid: error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestKeySetHS256Transition(t *testing.T) {
+	hs256, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "1"}).SignedString(testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := newEd25519Key(t, "key", testNow.Add(-time.Hour), time.Time{})
+
+	cases := []struct {
+		name         string
+		keys         []*Key
+		secret       []byte
+		until        time.Time
+		accepted     bool
+		inTransition bool
+	}{
+		{"secret only", nil, testSecret, time.Time{}, true, false},
+		{"keys without transition", []*Key{key}, testSecret, time.Time{}, false, false},
+		{"keys during transition", []*Key{key}, testSecret, testNow.Add(time.Hour), true, true},
+		{"keys after transition", []*Key{key}, testSecret, testNow, false, false},
+		{"keys without secret", []*Key{key}, nil, testNow.Add(time.Hour), false, true},
+	}
+	for _, c := range cases {
+		ks := newTestKeySet(t, c.keys, c.secret, c.until)
+		_, err := parse(ks, hs256)
+		if accepted := err == nil; accepted != c.accepted {
+			t.Errorf("%s: HS256 token accepted = %v, want %v (err %v)", c.name, accepted, c.accepted, err)
+		}
+		if got := ks.InHS256Transition(); got != c.inTransition {
+			t.Errorf("%s: InHS256Transition = %v, want %v", c.name, got, c.inTransition)
+		}
+	}
+
+	// С ключами новые токены подписываются асимметрично и в переходный период
+	ks := newTestKeySet(t, []*Key{key}, testSecret, testNow.Add(time.Hour))
+	if kid := signedKid(t, ks); kid != "key" {
+		t.Fatalf("signed with %q during transition, want the asymmetric key", kid)
+	}
+}
+
+func TestNewKeySetRejectsInvalidKeys(t *testing.T) {
+	key := newEd25519Key(t, "key", testNow, time.Time{})
+	cases := map[string][]*Key{
+		"missing kid":   {newEd25519Key(t, "", testNow, time.Time{})},
+		"duplicate kid": {key, newEd25519Key(t, "key", testNow, time.Time{})},
+	}
+	for name, keys := range cases {
+		if _, err := NewKeySet(keys, nil, time.Time{}); err == nil {
+			t.Errorf("%s: NewKeySet accepted the keys", name)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifest — файл со списком ключей:
+//
+//	{"keys": [{"kid": "2025-01", "private_key_file": "2025-01.pem",
+//	           "activates_at": "2025-01-01T00:00:00Z", "retires_at": "2025-07-01T00:00:00Z"}]}
+//
+// Относительные пути считаются от каталога файла.
+type manifest struct {
+	Keys []struct {
+		ID             string    `json:"kid"`
+		PrivateKeyFile string    `json:"private_key_file"`
+		ActivatesAt    time.Time `json:"activates_at"`
+		RetiresAt      time.Time `json:"retires_at"`
+	} `json:"keys"`
+}
+
+// LoadKeys читает ключи подписи из файла со списком ключей.
+// Поддерживаются RSA и Ed25519 ключи в PEM (PKCS#8 или PKCS#1).
+func LoadKeys(path string) ([]*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	keys := make([]*Key, 0, len(m.Keys))
+	for _, entry := range m.Keys {
+		keyPath := entry.PrivateKeyFile
+		if !filepath.IsAbs(keyPath) {
+			keyPath = filepath.Join(filepath.Dir(path), keyPath)
+		}
+
+		signer, err := readPrivateKey(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %q: %w", entry.ID, err)
+		}
+
+		keys = append(keys, &Key{
+			ID:          entry.ID,
+			Private:     signer,
+			ActivatesAt: entry.ActivatesAt,
+			RetiresAt:   entry.RetiresAt,
+		})
+	}
+	return keys, nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+		return signer, nil
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
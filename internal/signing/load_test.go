@@ -0,0 +1,93 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	dir := t.TempDir()
+
+	ed := newEd25519Key(t, "ed", time.Time{}, time.Time{})
+	der, err := x509.MarshalPKCS8PrivateKey(ed.Private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, filepath.Join(dir, "ed.pem"), "PRIVATE KEY", der)
+
+	rsaKey := newRSAKey(t, "rsa", time.Time{}, time.Time{})
+	writePEM(t, filepath.Join(dir, "rsa.pem"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey.Private.(*rsa.PrivateKey)))
+
+	manifest := `{"keys": [
+		{"kid": "2025-01", "private_key_file": "ed.pem", "activates_at": "2025-01-01T00:00:00Z", "retires_at": "2025-07-01T00:00:00Z"},
+		{"kid": "2025-06", "private_key_file": "` + filepath.Join(dir, "rsa.pem") + `", "activates_at": "2025-06-01T00:00:00Z"}
+	]}`
+	path := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadKeys(path)
+	if err != nil {
+		t.Fatalf("LoadKeys: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("loaded %d keys, want 2", len(keys))
+	}
+
+	first, second := keys[0], keys[1]
+	if first.ID != "2025-01" || !first.ActivatesAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+		!first.RetiresAt.Equal(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("first key = %+v", first)
+	}
+	if _, ok := first.Private.(ed25519.PrivateKey); !ok {
+		t.Fatalf("first key type %T, want ed25519", first.Private)
+	}
+	if second.ID != "2025-06" || !second.RetiresAt.IsZero() {
+		t.Fatalf("second key = %+v", second)
+	}
+	if _, ok := second.Private.(*rsa.PrivateKey); !ok {
+		t.Fatalf("second key type %T, want RSA", second.Private)
+	}
+
+	// Между активацией второго ключа и выводом первого подписывает второй,
+	// а токены первого еще принимаются
+	ks, err := NewKeySet(keys, nil, time.Time{})
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	ks.now = func() time.Time { return time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC) }
+	if kid := signedKid(t, ks); kid != "2025-06" {
+		t.Fatalf("signed with %q, want 2025-06", kid)
+	}
+	if got := len(ks.JWKS().Keys); got != 2 {
+		t.Fatalf("JWKS publishes %d keys, want 2", got)
+	}
+}
+
+func TestLoadKeysInvalidKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.pem"), []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(path, []byte(`{"keys": [{"kid": "bad", "private_key_file": "bad.pem"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeys(path); err == nil {
+		t.Fatal("LoadKeys accepted a file without a PEM block")
+	}
+}
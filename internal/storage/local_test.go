@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/levstremilov/shance-app/internal/config"
+)
+
+func TestCleanKey(t *testing.T) {
+	valid := []string{"photo.jpg", "projects/1/photo.jpg", "a/..b/c", "exports/1/data.zip"}
+	for _, key := range valid {
+		if got, err := cleanKey(key); err != nil || got != key {
+			t.Errorf("cleanKey(%q) = %q, %v; want the key unchanged", key, got, err)
+		}
+	}
+
+	invalid := []string{
+		"", ".", "..", "/etc/passwd", "../secret", "a/../../secret", "a/../b",
+		"a//b", "a/./b", "a/", `..\secret`, `a\b`,
+	}
+	for _, key := range invalid {
+		if got, err := cleanKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("cleanKey(%q) = %q, %v; want ErrInvalidKey", key, got, err)
+		}
+	}
+}
+
+func TestLocalStorageRejectsTraversal(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "uploads")
+	secret := filepath.Join(root, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := NewLocalStorage(dir, "http://localhost/uploads/")
+	ctx := context.Background()
+
+	for _, key := range []string{"../secret.txt", "a/../../secret.txt", "/secret.txt", `..\secret.txt`} {
+		if err := s.Put(ctx, key, strings.NewReader("overwritten"), 11, "text/plain"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+		if body, err := s.Get(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			if body != nil {
+				body.Close()
+			}
+			t.Errorf("Get(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+		if err := s.Delete(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Delete(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+		if _, err := s.URL(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("URL(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+
+	data, err := os.ReadFile(secret)
+	if err != nil || string(data) != "secret" {
+		t.Fatalf("file outside the storage directory changed: %q, %v", data, err)
+	}
+}
+
+func TestLocalStorageRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(dir, "http://localhost/uploads/")
+	ctx := context.Background()
+	key := "projects/1/отчет 1.pdf"
+
+	if err := s.Put(ctx, key, strings.NewReader("content"), 7, "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "projects", "1", "отчет 1.pdf")); err != nil {
+		t.Fatalf("file is not stored under the key path: %v", err)
+	}
+
+	body, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(data) != "content" {
+		t.Fatalf("Get returned %q, %v", data, err)
+	}
+
+	url, err := s.URL(key)
+	if err != nil {
+		t.Fatalf("URL: %v", err)
+	}
+	if want := "http://localhost/uploads/projects/1/%D0%BE%D1%82%D1%87%D0%B5%D1%82%201.pdf"; url != want {
+		t.Fatalf("URL = %q, want %q", url, want)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete of a missing object: %v", err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestNewPrivateRejectsPublicDirectory(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Storage.LocalDir = "uploads"
+
+	for _, dir := range []string{"", "uploads", "./uploads/", "uploads/private"} {
+		cfg.Storage.PrivateDir = dir
+		if _, err := NewPrivate(cfg); err == nil {
+			t.Errorf("NewPrivate with STORAGE_PRIVATE_DIR=%q succeeded, want an error", dir)
+		}
+	}
+	for _, dir := range []string{"data/private", "uploads-private", "../private"} {
+		cfg.Storage.PrivateDir = dir
+		if _, err := NewPrivate(cfg); err != nil {
+			t.Errorf("NewPrivate with STORAGE_PRIVATE_DIR=%q: %v", dir, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/levstremilov/shance-app/internal/config"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Storage хранит загруженные файлы. Ключ — путь объекта вида projects/1/photo.jpg.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет объект; удаление отсутствующего объекта не считается ошибкой
+	Delete(ctx context.Context, key string) error
+	// URL возвращает ссылку для скачивания объекта: статическую или подписанную
+	URL(key string) (string, error)
+}
+
+// New создает Storage в соответствии с STORAGE_DRIVER: local или s3
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "local", "":
+		publicURL := cfg.Storage.PublicURL
+		if publicURL == "" {
+			publicURL = fmt.Sprintf("http://%s:%s%s", cfg.Server.Host, cfg.Server.Port, LocalPath)
+		}
+		return NewLocalStorage(cfg.Storage.LocalDir, publicURL), nil
+	case "s3":
+		if cfg.Storage.S3AccessKey == "" || cfg.Storage.S3SecretKey == "" {
+			return nil, errors.New("S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 storage driver")
+		}
+		return NewS3Storage(S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PathStyle: cfg.Storage.S3PathStyle,
+			PublicURL: cfg.Storage.PublicURL,
+			URLTTL:    cfg.Storage.URLTTL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Storage.Driver)
+	}
+}
+
+// NewPrivate создает закрытое хранилище для файлов, которые нельзя раздавать по
+// общей ссылке, например вложений откликов. Для local это отдельная директория вне
+// раздаваемой статически, для s3 — отдельный бакет без публичного доступа.
+func NewPrivate(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case "local", "":
+		if cfg.Storage.PrivateDir == "" {
+			return nil, errors.New("STORAGE_PRIVATE_DIR is required for the local storage driver")
+		}
+		if inside(cfg.Storage.PrivateDir, cfg.Storage.LocalDir) {
+			return nil, errors.New("STORAGE_PRIVATE_DIR must be outside STORAGE_LOCAL_DIR, which is served publicly")
+		}
+		return NewLocalStorage(cfg.Storage.PrivateDir, ""), nil
+	case "s3":
+		if cfg.Storage.S3AccessKey == "" || cfg.Storage.S3SecretKey == "" {
+			return nil, errors.New("S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 storage driver")
+		}
+		if cfg.Storage.S3PrivateBucket == "" || cfg.Storage.S3PrivateBucket == cfg.Storage.S3Bucket {
+			return nil, errors.New("S3_PRIVATE_BUCKET is required for the s3 storage driver and must differ from S3_BUCKET")
+		}
+		return NewS3Storage(S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3PrivateBucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PathStyle: cfg.Storage.S3PathStyle,
+			URLTTL:    cfg.Storage.URLTTL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Storage.Driver)
+	}
+}
+
+// inside сообщает, совпадает ли директория dir с parent или лежит внутри нее
+func inside(dir, parent string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	parent, err = filepath.Abs(parent)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(parent, dir)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// cleanKey проверяет, что ключ — относительный путь без переходов наверх
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// escapeKey кодирует сегменты ключа для подстановки в URL
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// uriEscape кодирует все символы, кроме незарезервированных (RFC 3986)
+func uriEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
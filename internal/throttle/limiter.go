@@ -0,0 +1,107 @@
+package throttle
+
+import "time"
+
+// Config задает политику ограничения
+type Config struct {
+	// MaxAttempts — число неудачных попыток, после которого ключ блокируется на Lockout
+	MaxAttempts int
+	// BaseDelay — задержка после первой неудачи; каждая следующая удваивает ее, но не больше MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Lockout   time.Duration
+	// Window — через сколько после последней неудачи счетчик сбрасывается
+	Window time.Duration
+}
+
+// Result описывает состояние ключа после неудачной попытки
+type Result struct {
+	Failures   int
+	RetryAfter time.Duration
+	// LockedNow равен true только для попытки, которая привела к блокировке
+	LockedNow bool
+}
+
+type Limiter struct {
+	store Store
+	cfg   Config
+	now   func() time.Time
+}
+
+func NewLimiter(store Store, cfg Config) *Limiter {
+	return &Limiter{
+		store: store,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Check возвращает, сколько осталось ждать до следующей попытки; 0 — попытка разрешена
+func (l *Limiter) Check(key string) (time.Duration, error) {
+	entry, err := l.store.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	return retryAfter(entry, l.now()), nil
+}
+
+// Fail учитывает неудачную попытку
+func (l *Limiter) Fail(key string) (Result, error) {
+	now := l.now()
+	lockedNow := false
+
+	entry, err := l.store.Update(key, func(e *Entry) {
+		expired := !e.LastFailure.IsZero() && now.Sub(e.LastFailure) > l.cfg.Window
+		lockoutServed := e.Locked && !now.Before(e.BlockedUntil)
+		if expired || lockoutServed {
+			*e = Entry{}
+		}
+
+		e.Failures++
+		e.LastFailure = now
+		if e.Failures >= l.cfg.MaxAttempts {
+			lockedNow = !e.Locked
+			e.Locked = true
+			e.BlockedUntil = now.Add(l.cfg.Lockout)
+		} else {
+			e.BlockedUntil = now.Add(l.delay(e.Failures))
+		}
+
+		e.ExpiresAt = now.Add(l.cfg.Window)
+		if e.BlockedUntil.After(e.ExpiresAt) {
+			e.ExpiresAt = e.BlockedUntil
+		}
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Failures:   entry.Failures,
+		RetryAfter: retryAfter(entry, now),
+		LockedNow:  lockedNow,
+	}, nil
+}
+
+// Reset сбрасывает счетчик, например после успешного входа или разблокировки
+func (l *Limiter) Reset(key string) error {
+	return l.store.Delete(key)
+}
+
+func (l *Limiter) delay(failures int) time.Duration {
+	d := l.cfg.BaseDelay
+	for i := 1; i < failures; i++ {
+		d *= 2
+		if d >= l.cfg.MaxDelay {
+			return l.cfg.MaxDelay
+		}
+	}
+	return d
+}
+
+func retryAfter(entry Entry, now time.Time) time.Duration {
+	if now.Before(entry.BlockedUntil) {
+		return entry.BlockedUntil.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,116 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLimiter(cfg Config) (*Limiter, *time.Time) {
+	now := time.Now()
+	l := NewLimiter(NewMemoryStore(), cfg)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiterDelayDoublesUpToMaxDelay(t *testing.T) {
+	l, _ := newTestLimiter(Config{
+		MaxAttempts: 10,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Second,
+		Lockout:     time.Hour,
+		Window:      time.Hour,
+	})
+
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		result, err := l.Fail("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Failures != i+1 || result.RetryAfter != want || result.LockedNow {
+			t.Fatalf("failure %d: result = %+v, want RetryAfter %v", i+1, result, want)
+		}
+	}
+
+	wait, err := l.Check("key")
+	if err != nil || wait != 5*time.Second {
+		t.Fatalf("Check = %v, %v; want 5s", wait, err)
+	}
+	if wait, _ := l.Check("other"); wait != 0 {
+		t.Fatalf("Check of another key = %v, want 0", wait)
+	}
+}
+
+func TestLimiterLocksAfterMaxAttempts(t *testing.T) {
+	l, now := newTestLimiter(Config{
+		MaxAttempts: 3,
+		Lockout:     time.Hour,
+		Window:      10 * time.Minute,
+	})
+
+	for i := 1; i < 3; i++ {
+		if result, _ := l.Fail("key"); result.LockedNow || result.RetryAfter != 0 {
+			t.Fatalf("failure %d: result = %+v, want no lockout", i, result)
+		}
+	}
+	result, err := l.Fail("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.LockedNow || result.RetryAfter != time.Hour {
+		t.Fatalf("last failure: result = %+v, want a lockout for 1h", result)
+	}
+
+	// Неудача во время блокировки продлевает ее, но не считается новой блокировкой
+	*now = now.Add(5 * time.Minute)
+	if result, _ := l.Fail("key"); result.LockedNow || result.RetryAfter != time.Hour {
+		t.Fatalf("failure during lockout: result = %+v", result)
+	}
+
+	// Блокировка пережила окно счетчика: ключ остается заблокированным
+	*now = now.Add(45 * time.Minute)
+	if wait, _ := l.Check("key"); wait != 15*time.Minute {
+		t.Fatalf("Check during lockout = %v, want 15m", wait)
+	}
+
+	// После отбытой блокировки счет начинается заново
+	*now = now.Add(15 * time.Minute)
+	if wait, _ := l.Check("key"); wait != 0 {
+		t.Fatalf("Check after lockout = %v, want 0", wait)
+	}
+	if result, _ := l.Fail("key"); result.Failures != 1 || result.LockedNow {
+		t.Fatalf("first failure after lockout: result = %+v", result)
+	}
+}
+
+func TestLimiterWindowResetsFailures(t *testing.T) {
+	l, now := newTestLimiter(Config{
+		MaxAttempts: 3,
+		Lockout:     time.Hour,
+		Window:      10 * time.Minute,
+	})
+
+	l.Fail("key")
+	l.Fail("key")
+	*now = now.Add(11 * time.Minute)
+	if result, _ := l.Fail("key"); result.Failures != 1 || result.LockedNow {
+		t.Fatalf("failure after the window: result = %+v, want the counter reset", result)
+	}
+}
+
+func TestLimiterReset(t *testing.T) {
+	l, _ := newTestLimiter(Config{
+		MaxAttempts: 1,
+		Lockout:     time.Hour,
+		Window:      time.Hour,
+	})
+
+	if result, _ := l.Fail("key"); !result.LockedNow {
+		t.Fatalf("result = %+v, want a lockout", result)
+	}
+	if err := l.Reset("key"); err != nil {
+		t.Fatal(err)
+	}
+	if wait, _ := l.Check("key"); wait != 0 {
+		t.Fatalf("Check after Reset = %v, want 0", wait)
+	}
+}
@@ -0,0 +1,92 @@
+package throttle
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/levstremilov/shance-app/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore хранит счетчики в таблице throttle_entries, общей для всех
+// экземпляров приложения. Update блокирует строку ключа до конца транзакции.
+type PostgresStore struct {
+	db        *gorm.DB
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Get(key string) (Entry, error) {
+	var row models.ThrottleEntry
+	err := s.db.Where("bucket = ?", key).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Entry{}, nil
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	return toEntry(&row), nil
+}
+
+func (s *PostgresStore) Update(key string, fn func(*Entry)) (Entry, error) {
+	if err := s.sweep(time.Now()); err != nil {
+		return Entry{}, err
+	}
+
+	var entry Entry
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		row := models.ThrottleEntry{Bucket: key}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("bucket = ?", key).First(&row).Error; err != nil {
+			return err
+		}
+
+		entry = toEntry(&row)
+		fn(&entry)
+
+		row.Failures = entry.Failures
+		row.LastFailure = entry.LastFailure
+		row.BlockedUntil = entry.BlockedUntil
+		row.Locked = entry.Locked
+		row.ExpiresAt = entry.ExpiresAt
+		return tx.Save(&row).Error
+	})
+	if err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+func (s *PostgresStore) Delete(key string) error {
+	return s.db.Where("bucket = ?", key).Delete(&models.ThrottleEntry{}).Error
+}
+
+func (s *PostgresStore) sweep(now time.Time) error {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	return s.db.Where("expires_at < ?", now).Delete(&models.ThrottleEntry{}).Error
+}
+
+func toEntry(row *models.ThrottleEntry) Entry {
+	return Entry{
+		Failures:     row.Failures,
+		LastFailure:  row.LastFailure,
+		BlockedUntil: row.BlockedUntil,
+		Locked:       row.Locked,
+		ExpiresAt:    row.ExpiresAt,
+	}
+}
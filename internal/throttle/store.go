@@ -0,0 +1,29 @@
+// Package throttle ограничивает частоту неудачных попыток (например, входа)
+// с экспоненциальной задержкой и временной блокировкой.
+package throttle
+
+import "time"
+
+// Entry — состояние счетчика неудачных попыток по одному ключу
+type Entry struct {
+	Failures     int
+	LastFailure  time.Time
+	BlockedUntil time.Time
+	// Locked означает блокировку после превышения порога, а не обычную задержку
+	Locked bool
+	// ExpiresAt — время, после которого запись можно удалить
+	ExpiresAt time.Time
+}
+
+// Store хранит счетчики. Реализации должны выполнять Update атомарно,
+// чтобы несколько экземпляров приложения не теряли попытки.
+type Store interface {
+	Get(key string) (Entry, error)
+	// Update вызывает fn с текущим состоянием ключа (нулевым, если записи нет)
+	// и сохраняет результат
+	Update(key string, fn func(*Entry)) (Entry, error)
+	Delete(key string) error
+}
+
+// sweepInterval — как часто хранилища удаляют истекшие записи
+const sweepInterval = time.Minute
@@ -0,0 +1,98 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Секрет из приложения B RFC 6238 для HMAC-SHA1: ASCII "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Коды из приложения B RFC 6238; в RFC они 8-значные, здесь — последние 6 цифр
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCodeAtRFC6238(t *testing.T) {
+	for _, v := range rfcVectors {
+		code, err := CodeAt(rfcSecret, Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("CodeAt: %v", err)
+		}
+		if code != v.code {
+			t.Errorf("code at %d = %s, want %s", v.unix, code, v.code)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	cases := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{"current step", 0, true},
+		{"previous step", -1, true},
+		{"next step", 1, true},
+		{"two steps back", -2, false},
+		{"two steps ahead", 2, false},
+	}
+	for _, c := range cases {
+		code, err := CodeAt(rfcSecret, current+c.offset)
+		if err != nil {
+			t.Fatalf("CodeAt: %v", err)
+		}
+		step, ok := Verify(rfcSecret, code, now)
+		if ok != c.ok {
+			t.Errorf("%s: Verify = %v, want %v", c.name, ok, c.ok)
+		}
+		if ok && step != current+c.offset {
+			t.Errorf("%s: matched step %d, want %d", c.name, step, current+c.offset)
+		}
+	}
+}
+
+func TestVerifyNormalizesInput(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	if _, ok := Verify(rfcSecret, " 287 082 ", now); !ok {
+		t.Error("code with spaces is rejected")
+	}
+	if _, ok := Verify(strings.ToLower(rfcSecret), "287082", now); !ok {
+		t.Error("lower-case secret is rejected")
+	}
+	for _, code := range []string{"", "28708", "2870820", "94287082", "abcdef"} {
+		if _, ok := Verify(rfcSecret, code, now); ok {
+			t.Errorf("Verify accepted %q", code)
+		}
+	}
+	if _, ok := Verify("not base32!", "287082", now); ok {
+		t.Error("Verify accepted a code for an invalid secret")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q decodes to %d bytes, %v; want 20", secret, len(key), err)
+	}
+	if other, _ := GenerateSecret(); other == secret {
+		t.Fatal("GenerateSecret returned the same secret twice")
+	}
+}
@@ -7,7 +7,14 @@ package main
 // @BasePath /api/v1
 // @schemes http
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Bearer <JWT или персональный токен>. Персональный токен также принимается в заголовке X-API-Key, JWT — в cookie access_token
+
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -15,33 +22,187 @@ import (
 	"github.com/levstremilov/shance-app/internal/config"
 	"github.com/levstremilov/shance-app/internal/database"
 	"github.com/levstremilov/shance-app/internal/handler"
+	"github.com/levstremilov/shance-app/internal/jobs"
+	"github.com/levstremilov/shance-app/internal/mailer"
+	"github.com/levstremilov/shance-app/internal/models"
+	"github.com/levstremilov/shance-app/internal/oauth"
+	"github.com/levstremilov/shance-app/internal/policy"
 	"github.com/levstremilov/shance-app/internal/repository"
 	"github.com/levstremilov/shance-app/internal/server"
 	"github.com/levstremilov/shance-app/internal/service"
+	"github.com/levstremilov/shance-app/internal/signing"
+	"github.com/levstremilov/shance-app/internal/storage"
+	"github.com/levstremilov/shance-app/internal/throttle"
 	_ "github.com/lib/pq"
 
 	"gorm.io/gorm"
 )
 
-func initDependencies(db *gorm.DB) (*handler.AuthHandler, *handler.UserHandler, *handler.ProjectHandler, *handler.TagHandler, *handler.ProjectVacancyHandler, service.AuthServiceInterface) {
+func initOAuthProviders(cfg *config.Config) []*oauth.Provider {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var providers []*oauth.Provider
+	for _, p := range cfg.OAuth.Providers {
+		provider, err := oauth.NewProvider(ctx, oauth.ProviderConfig{
+			Name:         p.Name,
+			Kind:         p.Kind,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			Scopes:       p.Scopes,
+			RedirectURL:  fmt.Sprintf("%s/%s/callback", cfg.OAuth.RedirectBaseURL, p.Name),
+		}, nil)
+		if err != nil {
+			log.Printf("OAuth provider %s is disabled: %v", p.Name, err)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// initThrottleStore выбирает хранилище счетчиков попыток входа. Счетчики в памяти
+// не разделяются между экземплярами приложения.
+func initThrottleStore(db *gorm.DB, cfg *config.Config) throttle.Store {
+	if cfg.Throttle.Store == "memory" {
+		return throttle.NewMemoryStore()
+	}
+	return throttle.NewPostgresStore(db)
+}
+
+// initSigningKeys загружает ключи подписи JWT. Без файла ключей
+// токены подписываются HS256 секретом из JWT_SECRET.
+//
+// Вне ENV=dev пустой секрет и секрет по умолчанию не используются: без файла
+// ключей приложение не запускается, а с файлом HS256 токены не принимаются,
+// иначе токен мог бы подделать любой, кто знает секрет из исходного кода.
+func initSigningKeys(cfg *config.Config) (*signing.KeySet, error) {
+	secret := []byte(cfg.JWT.Secret)
+	insecureSecret := cfg.JWT.Secret == "" || cfg.JWT.Secret == config.DefaultJWTSecret
+
+	var keys []*signing.Key
+	if cfg.JWT.KeysFile != "" {
+		loaded, err := signing.LoadKeys(cfg.JWT.KeysFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = loaded
+		if insecureSecret && cfg.Server.Env != "dev" {
+			secret = nil
+		}
+	} else if cfg.Server.Env != "dev" {
+		if insecureSecret {
+			return nil, errors.New("JWT_SECRET must be set to a non-default value or JWT_KEYS_FILE must be configured")
+		}
+		log.Printf("JWT_KEYS_FILE is not set, tokens are signed with the shared HS256 secret")
+	}
+
+	return signing.NewKeySet(keys, secret, cfg.JWT.AcceptHS256Until)
+}
+
+func initDependencies(db *gorm.DB, cfg *config.Config, mail mailer.Mailer, files, privateFiles storage.Storage, keys *signing.KeySet) (*handler.AuthHandler, *handler.OAuthHandler, *handler.MFAHandler, *handler.TokenHandler, *handler.KeysHandler, *handler.SessionHandler, *handler.AccountHandler, *handler.UserHandler, *handler.ProfileHandler, *handler.ProjectHandler, *handler.TagHandler, *handler.ProjectVacancyHandler, *handler.SearchHandler, *handler.InvitationHandler, *handler.JoinRequestHandler, *handler.ApplicationHandler, service.AuthServiceInterface, service.PersonalAccessTokenServiceInterface, service.SessionServiceInterface, service.AuditServiceInterface, *service.ProjectVacancyService, *policy.Engine, *jobs.Runner) {
 	userRepo := repository.NewUserRepository(db)
+	throttleStore := initThrottleStore(db, cfg)
 	projectRepo := repository.NewProjectRepository(db)
 	tagRepo := repository.NewTagRepository(db)
 	vacancyRepo := repository.NewProjectVacancyRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	oneTimeTokenRepo := repository.NewOneTimeTokenRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	personalAccessTokenRepo := repository.NewPersonalAccessTokenRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	photoRepo := repository.NewProjectPhotoRepository(db)
+	profileRepo := repository.NewProfileRepository(db)
+	invitationRepo := repository.NewProjectInvitationRepository(db)
+	joinRequestRepo := repository.NewProjectJoinRequestRepository(db)
+	applicationRepo := repository.NewVacancyApplicationRepository(db)
 
-	authService := service.NewAuthService(userRepo, "your-secret-key", 24*time.Hour, 168*time.Hour)
-	userService := service.NewUserService(userRepo)
-	projectService := service.NewProjectService(projectRepo, tagRepo, userRepo)
+	auditService := service.NewAuditService(auditRepo)
+	loginThrottleService := service.NewLoginThrottleService(
+		throttle.NewLimiter(throttleStore, throttle.Config{
+			MaxAttempts: cfg.Throttle.AccountMaxAttempts,
+			BaseDelay:   cfg.Throttle.BaseDelay,
+			MaxDelay:    cfg.Throttle.MaxDelay,
+			Lockout:     cfg.Throttle.LockoutDuration,
+			Window:      cfg.Throttle.Window,
+		}),
+		throttle.NewLimiter(throttleStore, throttle.Config{
+			MaxAttempts: cfg.Throttle.IPMaxAttempts,
+			BaseDelay:   cfg.Throttle.BaseDelay,
+			MaxDelay:    cfg.Throttle.MaxDelay,
+			Lockout:     cfg.Throttle.LockoutDuration,
+			Window:      cfg.Throttle.Window,
+		}),
+		// Без задержек: MFA токен просто перестает действовать после MFAMaxAttempts неверных кодов
+		throttle.NewLimiter(throttleStore, throttle.Config{
+			MaxAttempts: cfg.Auth.MFAMaxAttempts,
+			Lockout:     cfg.Auth.MFAChallengeTTL,
+			Window:      cfg.Auth.MFAChallengeTTL,
+		}),
+		userRepo, oneTimeTokenRepo, auditService, mail, cfg.Server.PublicURL, cfg.Auth.AccountUnlockTTL,
+	)
+	invitationService := service.NewProjectInvitationService(invitationRepo, projectRepo, userRepo, keys, cfg.JWT.Issuer, cfg.JWT.Audience, mail, cfg.Server.PublicURL, cfg.Projects.InvitationTTL)
+	mfaService := service.NewMFAService(userRepo, recoveryCodeRepo, cfg.Auth.MFAIssuer, cfg.Auth.RequireAdminMFA)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, sessionRepo, mfaService, loginThrottleService, keys, cfg.JWT.Issuer, cfg.JWT.Audience, cfg.JWT.AccessTokenTTL, cfg.JWT.RefreshTokenTTL, cfg.Auth.MFAChallengeTTL, cfg.Auth.ImpersonationTTL, auditService, invitationService)
+	accountService := service.NewAccountService(userRepo, oneTimeTokenRepo, refreshTokenRepo, mail, cfg.Server.PublicURL, cfg.Auth.PasswordResetTTL, cfg.Auth.EmailVerificationTTL)
+	oauthService := service.NewOAuthService(initOAuthProviders(cfg), identityRepo, userRepo, authService, invitationService, cfg.Server.PublicURL, cfg.OAuth.StateTTL)
+	tokenService := service.NewPersonalAccessTokenService(personalAccessTokenRepo)
+	sessionService := service.NewSessionService(sessionRepo, refreshTokenRepo)
+	userService := service.NewUserService(userRepo, refreshTokenRepo)
+	projectService := service.NewProjectService(projectRepo, tagRepo, userRepo, cfg.Auth.RequireVerifiedEmail)
+	joinRequestService := service.NewProjectJoinRequestService(joinRequestRepo, projectRepo)
 	tagService := service.NewTagService(tagRepo)
-	vacancyService := service.NewProjectVacancyService(vacancyRepo)
+	vacancyService := service.NewProjectVacancyService(vacancyRepo, projectRepo, jobRepo, cfg.Jobs.VacancyExpiryInterval)
+	applicationService := service.NewVacancyApplicationService(applicationRepo, vacancyRepo, projectRepo, jobRepo, privateFiles, files, cfg.Uploads.MaxAttachmentSize)
+	photoService := service.NewProjectPhotoService(photoRepo, projectRepo, files, cfg.Uploads.MaxPhotoSize, cfg.Uploads.MaxProjectPhotos)
+	profileService := service.NewProfileService(profileRepo, userRepo, files, cfg.Uploads.MaxAvatarSize, cfg.Uploads.MaxPortfolioSize, cfg.Uploads.MaxPortfolioFiles)
+	searchService := service.NewSearchService(projectRepo, vacancyRepo, userRepo, tagRepo)
+	jobService := service.NewJobService(jobRepo)
+	exportService := service.NewDataExportService(userRepo, projectRepo, vacancyRepo, joinRequestRepo, applicationRepo, profileRepo, jobRepo, auditService, cfg.Account.ExportDir, cfg.Account.ExportTTL, cfg.Jobs.ExportCleanupInterval)
+	deletionService := service.NewAccountDeletionService(userRepo, projectRepo, joinRequestRepo, refreshTokenRepo, jobRepo, exportService, profileService, applicationService, auditService, mail, cfg.Server.PublicURL, cfg.Account.DeletionGracePeriod)
+
+	jobRunner := jobs.NewRunner(jobRepo, jobs.Config{
+		PollInterval: cfg.Jobs.PollInterval,
+		Lease:        cfg.Jobs.Lease,
+		MaxAttempts:  cfg.Jobs.MaxAttempts,
+		RetryDelay:   cfg.Jobs.RetryDelay,
+	})
+	jobRunner.Register(models.JobTypeDataExport, exportService.Run)
+	jobRunner.Register(models.JobTypeAccountDeletion, deletionService.Run)
+	jobRunner.Register(models.JobTypeVacancyExpiry, vacancyService.Run)
+	jobRunner.Register(models.JobTypeExportCleanup, exportService.Cleanup)
+	if err := exportService.ScheduleCleanup(); err != nil {
+		log.Printf("Failed to schedule export cleanup: %v", err)
+	}
+	jobRunner.Register(models.JobTypeAttachmentMove, applicationService.MoveAttachments)
+	if err := applicationService.ScheduleAttachmentMove(); err != nil {
+		log.Printf("Failed to schedule attachment move: %v", err)
+	}
 
-	authHandler := handler.NewAuthHandler(authService)
-	userHandler := handler.NewUserHandler(userService)
-	projectHandler := handler.NewProjectHandler(projectService)
+	policyEngine := policy.NewEngine(projectService)
+
+	authHandler := handler.NewAuthHandler(authService, accountService, loginThrottleService, cfg.JWT.AccessTokenTTL)
+	oauthHandler := handler.NewOAuthHandler(oauthService, cfg.JWT.AccessTokenTTL)
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	tokenHandler := handler.NewTokenHandler(tokenService)
+	keysHandler := handler.NewKeysHandler(keys)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	accountHandler := handler.NewAccountHandler(jobService, exportService, deletionService)
+	userHandler := handler.NewUserHandler(userService, photoService, profileService)
+	profileHandler := handler.NewProfileHandler(profileService)
+	projectHandler := handler.NewProjectHandler(projectService, photoService)
 	tagHandler := handler.NewTagHandler(tagService)
 	vacancyHandler := handler.NewProjectVacancyHandler(vacancyService)
+	searchHandler := handler.NewSearchHandler(searchService, photoService, profileService)
+	invitationHandler := handler.NewInvitationHandler(invitationService)
+	joinRequestHandler := handler.NewJoinRequestHandler(joinRequestService)
+	applicationHandler := handler.NewApplicationHandler(applicationService)
 
-	return authHandler, userHandler, projectHandler, tagHandler, vacancyHandler, authService
+	return authHandler, oauthHandler, mfaHandler, tokenHandler, keysHandler, sessionHandler, accountHandler, userHandler, profileHandler, projectHandler, tagHandler, vacancyHandler, searchHandler, invitationHandler, joinRequestHandler, applicationHandler, authService, tokenService, sessionService, auditService, vacancyService, policyEngine, jobRunner
 }
 
 func main() {
@@ -55,9 +216,36 @@ func main() {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
-	authHandler, userHandler, projectHandler, tagHandler, vacancyHandler, authService := initDependencies(db)
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize mailer: %v", err)
+	}
+
+	files, err := storage.New(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	privateFiles, err := storage.NewPrivate(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize private storage: %v", err)
+	}
+
+	keys, err := initSigningKeys(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	authHandler, oauthHandler, mfaHandler, tokenHandler, keysHandler, sessionHandler, accountHandler, userHandler, profileHandler, projectHandler, tagHandler, vacancyHandler, searchHandler, invitationHandler, joinRequestHandler, applicationHandler, authService, tokenService, sessionService, auditService, vacancyService, policyEngine, jobRunner := initDependencies(db, cfg, mail, files, privateFiles, keys)
 
-	r := server.SetUpRouter(projectHandler, authHandler, userHandler, tagHandler, vacancyHandler, authService, cfg)
+	if err := vacancyService.ScheduleExpiry(); err != nil {
+		log.Printf("Failed to schedule vacancy expiry: %v", err)
+	}
+	go jobRunner.Run(context.Background())
+
+	r, err := server.SetUpRouter(projectHandler, authHandler, oauthHandler, mfaHandler, tokenHandler, keysHandler, sessionHandler, accountHandler, userHandler, profileHandler, tagHandler, vacancyHandler, searchHandler, invitationHandler, joinRequestHandler, applicationHandler, authService, tokenService, sessionService, auditService, vacancyService, policyEngine, cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up router: %v", err)
+	}
 	if err := r.Run(fmt.Sprintf(":%s", cfg.Server.Port)); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}